@@ -0,0 +1,396 @@
+/*
+Package adr imports architecture decision records kept as numbered Markdown
+files, such as the ones created by adr-tools
+(https://github.com/npryce/adr-tools) or following the MADR template
+(https://adr.github.io/madr/).
+
+Only files whose name starts with a number followed by a dash and that have a
+".md" extension are imported (e.g. "0001-record-architecture-decisions.md").
+The number is used as the decision ID. The title, date and status are read
+from the YAML front matter if present, from "Date:" and "Status:" lines or
+from the "Status" section otherwise. Links to other decisions listed with the
+status (e.g. "Superseded by [2. Use X](0002-use-x.md)") are resolved so that
+both ends of a supersedes relationship reference each other.
+
+A decision is associated with a model element if its front matter defines an
+"element" key set to the name of a person or software system or to the path
+of another element (e.g. "System/Container"), or if its content mentions the
+path of a container, component or code element. The names of people and
+software systems are too common to be looked for in the content. Paths must
+appear as a whole, "System/Web" is not mentioned by "System/WebApp". The most
+specific path mentioned wins.
+*/
+package adr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"goa.design/model/mdl"
+)
+
+var (
+	// fileRegexp matches the names of decision record files.
+	fileRegexp = regexp.MustCompile(`^(\d+)-.*\.md$`)
+	// titleRegexp matches the number prefix of adr-tools titles.
+	titleRegexp = regexp.MustCompile(`^\d+\.\s*`)
+	// linkRegexp matches Markdown links.
+	linkRegexp = regexp.MustCompile(`\[([^\]]*)\]\(([^)]+)\)`)
+)
+
+// Import loads the decision records in dir and adds them to the design
+// documentation.
+func Import(dir string, d *mdl.Design) error {
+	decs, err := Load(dir, d.Model)
+	if err != nil {
+		return err
+	}
+	if d.Documentation == nil {
+		d.Documentation = &mdl.Documentation{}
+	}
+	for _, dec := range decs {
+		for _, existing := range d.Documentation.Decisions {
+			if existing.ID == dec.ID {
+				return fmt.Errorf("decision %q is already defined in the design", dec.ID)
+			}
+		}
+	}
+	d.Documentation.Decisions = append(d.Documentation.Decisions, decs...)
+	return nil
+}
+
+// Load parses the decision records in dir. m is used to resolve the element
+// the decisions apply to, it may be nil.
+func Load(dir string, m *mdl.Model) ([]*mdl.Decision, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var decs []*mdl.Decision
+	files := make(map[string]string) // file name to decision ID
+	paths := elementPaths(m)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		matches := fileRegexp.FindStringSubmatch(e.Name())
+		if matches == nil {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		dec, err := parse(decisionID(matches[1]), string(b), paths)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		if hasID(decs, dec.ID) {
+			return nil, fmt.Errorf("%s: decision ID %q already in use", e.Name(), dec.ID)
+		}
+		files[e.Name()] = dec.ID
+		decs = append(decs, dec)
+	}
+	resolveLinks(decs, files)
+	sort.SliceStable(decs, func(i, j int) bool {
+		ni, _ := strconv.Atoi(decs[i].ID)
+		nj, _ := strconv.Atoi(decs[j].ID)
+		return ni < nj
+	})
+	return decs, nil
+}
+
+// parse parses the content of a decision record file.
+func parse(id, content string, paths map[string]string) (*mdl.Decision, error) {
+	meta, body, err := frontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+	dec := &mdl.Decision{
+		ID:      id,
+		Title:   meta["title"],
+		Date:    meta["date"],
+		Content: body,
+		Format:  mdl.FormatMarkdown,
+	}
+	status := meta["status"]
+	var section string
+	for line := range strings.Lines(body) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(line, "#"))
+			if dec.Title == "" && strings.HasPrefix(line, "# ") {
+				dec.Title = titleRegexp.ReplaceAllString(heading, "")
+			}
+			section = strings.ToLower(heading)
+			continue
+		}
+		key, val, ok := field(line)
+		switch {
+		case ok && key == "date":
+			if dec.Date == "" {
+				dec.Date = val
+			}
+		case ok && key == "status":
+			if status == "" {
+				status = val
+			}
+		case section == "status" && line != "":
+			if status == "" {
+				status = line
+			}
+			dec.Links = append(dec.Links, links(line)...)
+		}
+	}
+	dec.Status = statusKind(status)
+	dec.Links = append(dec.Links, links(status)...)
+	if el, ok := meta["element"]; ok {
+		id, ok := paths[el]
+		if !ok {
+			return nil, fmt.Errorf("unknown element %q", el)
+		}
+		dec.ElementID = id
+	} else {
+		dec.ElementID = referencedElement(body, paths)
+	}
+	return dec, nil
+}
+
+// frontMatter parses the YAML front matter of content if any. Only flat
+// "key: value" pairs are supported, other lines are ignored. It returns the
+// key value pairs and the content that follows the front matter.
+func frontMatter(content string) (map[string]string, string, error) {
+	meta := make(map[string]string)
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return meta, content, nil
+	}
+	rest := content[strings.Index(content, "\n")+1:]
+	for {
+		i := strings.Index(rest, "\n")
+		if i < 0 {
+			return nil, "", fmt.Errorf("front matter is not terminated")
+		}
+		line := strings.TrimRight(rest[:i], "\r")
+		rest = rest[i+1:]
+		if line == "---" {
+			return meta, rest, nil
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		meta[strings.ToLower(strings.TrimSpace(key))] = unquote(strings.TrimSpace(val))
+	}
+}
+
+// field parses lines of the form "Key: value" optionally prefixed with a
+// list marker as used by adr-tools and older versions of MADR.
+func field(line string) (string, string, bool) {
+	line = strings.TrimLeft(line, "*- ")
+	key, val, ok := strings.Cut(line, ":")
+	if !ok || strings.ContainsAny(key, " []()") {
+		return "", "", false
+	}
+	return strings.ToLower(key), strings.TrimSpace(val), true
+}
+
+// links returns the links to other decision records listed in status.
+func links(status string) []*mdl.DecisionLink {
+	var res []*mdl.DecisionLink
+	for _, m := range linkRegexp.FindAllStringSubmatchIndex(status, -1) {
+		target := filepath.Base(status[m[4]:m[5]])
+		desc := strings.TrimSpace(status[:m[0]])
+		if prev := linkRegexp.FindAllStringIndex(status[:m[0]], -1); len(prev) > 0 {
+			desc = strings.TrimSpace(status[prev[len(prev)-1][1]:m[0]])
+		}
+		desc = strings.Trim(desc, ",;")
+		if len(desc) > 0 {
+			desc = strings.ToUpper(desc[:1]) + desc[1:]
+		}
+		res = append(res, &mdl.DecisionLink{ID: target, Description: desc})
+	}
+	return res
+}
+
+// resolveLinks replaces the file names used as link IDs with the
+// corresponding decision IDs, removes links to unknown decisions and makes
+// sure both ends of supersedes relationships are recorded.
+func resolveLinks(decs []*mdl.Decision, files map[string]string) {
+	byID := make(map[string]*mdl.Decision, len(decs))
+	for _, dec := range decs {
+		byID[dec.ID] = dec
+	}
+	for _, dec := range decs {
+		var resolved []*mdl.DecisionLink
+		for _, l := range dec.Links {
+			id, ok := files[l.ID]
+			if !ok || id == dec.ID || hasLink(resolved, id, l.Description) {
+				continue
+			}
+			l.ID = id
+			resolved = append(resolved, l)
+		}
+		dec.Links = resolved
+	}
+	for _, dec := range decs {
+		for _, l := range dec.Links {
+			other := byID[l.ID]
+			switch strings.ToLower(l.Description) {
+			case "supersedes":
+				other.Status = mdl.DecisionSuperseded
+				addLink(other, dec.ID, "Superseded by")
+			case "superseded by":
+				dec.Status = mdl.DecisionSuperseded
+				addLink(other, dec.ID, "Supersedes")
+			}
+		}
+	}
+}
+
+// addLink adds a link to the decision with the given ID if not already
+// present.
+func addLink(dec *mdl.Decision, id, desc string) {
+	if hasLink(dec.Links, id, desc) {
+		return
+	}
+	dec.Links = append(dec.Links, &mdl.DecisionLink{ID: id, Description: desc})
+}
+
+// hasLink returns true if links contains a link to the decision with the
+// given ID and description.
+func hasLink(links []*mdl.DecisionLink, id, desc string) bool {
+	for _, l := range links {
+		if l.ID == id && strings.EqualFold(l.Description, desc) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasID returns true if decs contains a decision with the given ID.
+func hasID(decs []*mdl.Decision, id string) bool {
+	for _, dec := range decs {
+		if dec.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// statusKind returns the decision status corresponding to the given text.
+// Decisions with no or an unknown status are considered proposed.
+func statusKind(status string) mdl.DecisionStatusKind {
+	word, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(status)), " ")
+	switch strings.Trim(word, ".,;:*_") {
+	case "accepted":
+		return mdl.DecisionAccepted
+	case "superseded":
+		return mdl.DecisionSuperseded
+	case "deprecated":
+		return mdl.DecisionDeprecated
+	case "rejected":
+		return mdl.DecisionRejected
+	default:
+		return mdl.DecisionProposed
+	}
+}
+
+// referencedElement returns the ID of the most specific element whose path
+// is mentioned in content, the empty string if there is none. Only qualified
+// paths are considered, the names of people and software systems are not.
+func referencedElement(content string, paths map[string]string) string {
+	var best string
+	for p := range paths {
+		if !strings.Contains(p, "/") || !mentions(content, p) {
+			continue
+		}
+		if len(p) > len(best) || len(p) == len(best) && p < best {
+			best = p
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return paths[best]
+}
+
+// mentions returns true if content mentions path as a whole: the text
+// surrounding an occurrence of path does not extend its first or last name
+// and does not prefix it with another path segment.
+func mentions(content, path string) bool {
+	first, _ := utf8.DecodeRuneInString(path)
+	last, _ := utf8.DecodeLastRuneInString(path)
+	for i := 0; ; {
+		j := strings.Index(content[i:], path)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(path)
+		before, _ := utf8.DecodeLastRuneInString(content[:start])
+		after, _ := utf8.DecodeRuneInString(content[end:])
+		if before != '/' && !(isNameRune(first) && isNameRune(before)) && !(isNameRune(last) && isNameRune(after)) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isNameRune returns true if r may be part of a word of an element name.
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// elementPaths returns the IDs of the people, software systems, containers,
+// components and code elements of m indexed by path.
+func elementPaths(m *mdl.Model) map[string]string {
+	paths := make(map[string]string)
+	if m == nil {
+		return paths
+	}
+	for _, p := range m.People {
+		paths[p.Name] = p.ID
+	}
+	for _, s := range m.Systems {
+		paths[s.Name] = s.ID
+		for _, c := range s.Containers {
+			cp := s.Name + "/" + c.Name
+			paths[cp] = c.ID
+			for _, cmp := range c.Components {
+				cmpp := cp + "/" + cmp.Name
+				paths[cmpp] = cmp.ID
+				for _, ce := range cmp.CodeElements {
+					paths[cmpp+"/"+ce.Name] = ce.ID
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// decisionID returns the decision ID corresponding to the number prefix of
+// a decision record file name.
+func decisionID(num string) string {
+	id := strings.TrimLeft(num, "0")
+	if id == "" {
+		return "0"
+	}
+	return id
+}
+
+// unquote removes the quotes surrounding s if any.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
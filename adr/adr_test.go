@@ -0,0 +1,261 @@
+package adr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+var testModel = &mdl.Model{
+	Systems: []*mdl.SoftwareSystem{{
+		ID:   "sys",
+		Name: "System",
+		Containers: []*mdl.Container{{
+			ID:   "cont",
+			Name: "Container",
+			Components: []*mdl.Component{{
+				ID:   "cmp",
+				Name: "Component",
+			}},
+		}},
+	}},
+}
+
+const (
+	adrTools = `# 1. Record architecture decisions
+
+Date: 2016-02-12
+
+## Status
+
+Accepted
+
+## Context
+
+We need to record the architectural decisions made on System/Container.
+`
+	adrToolsSuperseded = `# 2. Use PostgreSQL
+
+Date: 2016-03-01
+
+## Status
+
+Superseded by [3. Use CockroachDB](0003-use-cockroachdb.md)
+
+## Decision
+
+System/Container/Component stores data in System/Container.
+`
+	adrToolsSupersedes = `# 3. Use CockroachDB
+
+Date: 2016-04-01
+
+## Status
+
+Accepted
+
+Supersedes [2. Use PostgreSQL](0002-use-postgresql.md)
+`
+	madr = `---
+status: "rejected"
+date: 2024-01-15
+element: System
+deciders: [alice, bob]
+---
+# Use GraphQL
+
+## Context and Problem Statement
+
+Should the API use GraphQL?
+`
+	madr2 = `# Use gRPC
+
+* Status: deprecated
+* Date: 2020-05-01
+
+## Context and Problem Statement
+
+Services talk to each other.
+`
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"0001-record-architecture-decisions.md": adrTools,
+		"0002-use-postgresql.md":                adrToolsSuperseded,
+		"0003-use-cockroachdb.md":               adrToolsSupersedes,
+		"0010-use-graphql.md":                   madr,
+		"0011-use-grpc.md":                      madr2,
+		"README.md":                             "# Decisions",
+		"template.md":                           "# Title",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decs, err := Load(dir, testModel)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []struct {
+		id, title, date, element string
+		status                   mdl.DecisionStatusKind
+		links                    []mdl.DecisionLink
+	}{
+		{"1", "Record architecture decisions", "2016-02-12", "cont", mdl.DecisionAccepted, nil},
+		{"2", "Use PostgreSQL", "2016-03-01", "cmp", mdl.DecisionSuperseded, []mdl.DecisionLink{{ID: "3", Description: "Superseded by"}}},
+		{"3", "Use CockroachDB", "2016-04-01", "", mdl.DecisionAccepted, []mdl.DecisionLink{{ID: "2", Description: "Supersedes"}}},
+		{"10", "Use GraphQL", "2024-01-15", "sys", mdl.DecisionRejected, nil},
+		{"11", "Use gRPC", "2020-05-01", "", mdl.DecisionDeprecated, nil},
+	}
+	if len(decs) != len(want) {
+		t.Fatalf("got %d decisions, want %d", len(decs), len(want))
+	}
+	for i, w := range want {
+		d := decs[i]
+		if d.ID != w.id {
+			t.Errorf("decision %d: got ID %q, want %q", i, d.ID, w.id)
+		}
+		if d.Title != w.title {
+			t.Errorf("decision %s: got title %q, want %q", w.id, d.Title, w.title)
+		}
+		if d.Date != w.date {
+			t.Errorf("decision %s: got date %q, want %q", w.id, d.Date, w.date)
+		}
+		if d.ElementID != w.element {
+			t.Errorf("decision %s: got element %q, want %q", w.id, d.ElementID, w.element)
+		}
+		if d.Status != w.status {
+			t.Errorf("decision %s: got status %d, want %d", w.id, d.Status, w.status)
+		}
+		if d.Format != mdl.FormatMarkdown {
+			t.Errorf("decision %s: got format %d, want %d", w.id, d.Format, mdl.FormatMarkdown)
+		}
+		if len(d.Links) != len(w.links) {
+			t.Errorf("decision %s: got %d links, want %d", w.id, len(d.Links), len(w.links))
+			continue
+		}
+		for j, l := range w.links {
+			if *d.Links[j] != l {
+				t.Errorf("decision %s: got link %+v, want %+v", w.id, *d.Links[j], l)
+			}
+		}
+	}
+}
+
+func TestLoadReciprocalLinks(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"0001-use-postgresql.md":  "# 1. Use PostgreSQL\n\n## Status\n\nAccepted\n",
+		"0002-use-cockroachdb.md": "# 2. Use CockroachDB\n\n## Status\n\nAccepted\n\nSupersedes [1. Use PostgreSQL](0001-use-postgresql.md)\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decs, err := Load(dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if decs[0].Status != mdl.DecisionSuperseded {
+		t.Errorf("got status %d, want %d", decs[0].Status, mdl.DecisionSuperseded)
+	}
+	if len(decs[0].Links) != 1 || *decs[0].Links[0] != (mdl.DecisionLink{ID: "2", Description: "Superseded by"}) {
+		t.Errorf("got links %v, want link superseded by 2", decs[0].Links)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+		want  string
+	}{
+		"duplicate ID":    {map[string]string{"0001-foo.md": "# Foo", "1-bar.md": "# Bar"}, "already in use"},
+		"unknown element": {map[string]string{"0001-foo.md": "---\nelement: Unknown\n---\n# Foo"}, "unknown element"},
+		"front matter":    {map[string]string{"0001-foo.md": "---\nstatus: accepted\n# Foo"}, "not terminated"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := Load(dir, testModel)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001-foo.md"), []byte("# Foo"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	design := &mdl.Design{Model: testModel}
+	if err := Import(dir, design); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if design.Documentation == nil || len(design.Documentation.Decisions) != 1 {
+		t.Fatalf("got documentation %v, want one decision", design.Documentation)
+	}
+	if err := Import(dir, design); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("got error %v, want already defined", err)
+	}
+}
+
+func TestReferencedElement(t *testing.T) {
+	m := &mdl.Model{
+		People: []*mdl.Person{{ID: "user", Name: "User"}},
+		Systems: []*mdl.SoftwareSystem{{
+			ID:   "shop",
+			Name: "Shop",
+			Containers: []*mdl.Container{
+				{ID: "web", Name: "Web"},
+				{ID: "webapp", Name: "WebApp"},
+			},
+		}},
+	}
+	cases := []struct {
+		Name    string
+		Content string
+		Want    string
+	}{
+		{"container", "Shop/Web serves pages.", "web"},
+		{"prefix", "Shop/WebApp serves pages.", "webapp"},
+		{"prefix of unknown path", "Shop/Webhooks serves pages.", ""},
+		{"system", "The Shop sells things.", ""},
+		{"person", "Each User has an account.", ""},
+		{"part of a word", "Shopping/Web and Shop/Webs.", ""},
+		{"suffix of a path", "Other/Shop/Web serves pages.", ""},
+		{"most specific", "User buys from Shop/Web and Shop/WebApp.", "webapp"},
+		{"punctuation", "Deployed with (Shop/Web).", "web"},
+		{"none", "Nothing relevant.", ""},
+	}
+	paths := elementPaths(m)
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if got := referencedElement(c.Content, paths); got != c.Want {
+				t.Errorf("got element %q, want %q", got, c.Want)
+			}
+		})
+	}
+}
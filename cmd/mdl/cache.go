@@ -0,0 +1,219 @@
+// This file implements the render cache of the svg command. Each rendered SVG
+// records a digest of the view content, styles, layout and render options so
+// that views that did not change since are not rendered again.
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goa.design/model/mdl"
+	model "goa.design/model/pkg"
+	"goa.design/model/query"
+)
+
+type (
+	// renderCache computes and records the digests of the rendered views.
+	renderCache struct {
+		g         *query.Graph
+		renderer  string
+		direction string
+		compact   bool
+	}
+
+	// viewContent is the content of a view that determines its rendering.
+	viewContent struct {
+		Version       string
+		Renderer      string
+		Direction     string
+		Compact       bool
+		Key           string
+		Title         string
+		Description   string
+		Kind          query.ViewKind
+		Props         *mdl.ViewProps
+		Elements      []*elementContent
+		Relationships []*relationshipContent
+		Styles        *mdl.Styles
+		Layout        Layout
+	}
+
+	// elementContent is the content of an element shown in a view.
+	elementContent struct {
+		ID          string
+		Name        string
+		Kind        query.ElementKind
+		Path        string
+		Description string
+		Technology  string
+		Tags        string
+		Properties  map[string]string
+		Parent      string
+	}
+
+	// relationshipContent is the content of a relationship shown in a view.
+	relationshipContent struct {
+		Relationship *mdl.Relationship
+		Description  string
+		Order        string
+		Props        *mdl.RelationshipView
+	}
+)
+
+// digestAttr is the name of the SVG root element attribute that records the
+// view digest.
+const digestAttr = "data-mdl-digest"
+
+// newRenderCache returns the render cache of the views of design rendered with
+// the options of cfg.
+func newRenderCache(design *mdl.Design, cfg config) *renderCache {
+	renderer := cfg.renderer
+	if renderer == "" {
+		renderer = "browser"
+	}
+	return &renderCache{
+		g:         query.New(design),
+		renderer:  renderer,
+		direction: strings.ToUpper(cfg.direction),
+		compact:   cfg.compact,
+	}
+}
+
+// stale returns the keys of the views that need to be rendered in dir: the
+// views that have no SVG file or whose SVG file digest does not match.
+func (c *renderCache) stale(keys []string, dir string) ([]string, error) {
+	var res []string
+	for _, key := range keys {
+		b, err := os.ReadFile(filepath.Join(dir, key+".svg"))
+		if errors.Is(err, fs.ErrNotExist) {
+			res = append(res, key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		recorded, err := svgDigest(b)
+		if err != nil || recorded == "" {
+			res = append(res, key)
+			continue
+		}
+		layout, err := svgLayout(b)
+		if err != nil {
+			res = append(res, key)
+			continue
+		}
+		digest, err := c.digest(key, layout)
+		if err != nil {
+			return nil, err
+		}
+		if digest != recorded {
+			res = append(res, key)
+		}
+	}
+	return res, nil
+}
+
+// stamp records the digest of the view with the given key in its rendered
+// SVG document b.
+func (c *renderCache) stamp(key string, b []byte) ([]byte, error) {
+	layout, err := svgLayout(b)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := c.digest(key, layout)
+	if err != nil {
+		return nil, err
+	}
+	i := bytes.Index(b, []byte("<svg"))
+	if i < 0 {
+		return nil, fmt.Errorf("invalid SVG: missing svg element")
+	}
+	i += len("<svg")
+	res := make([]byte, 0, len(b)+len(digestAttr)+len(digest)+4)
+	res = append(res, b[:i]...)
+	res = append(res, fmt.Sprintf(" %s=%q", digestAttr, digest)...)
+	return append(res, b[i:]...), nil
+}
+
+// digest returns the digest of the view with the given key rendered with the
+// given layout.
+func (c *renderCache) digest(key string, layout Layout) (string, error) {
+	v := c.g.View(key)
+	if v == nil {
+		return "", fmt.Errorf("view %s: not found", key)
+	}
+	content := viewContent{
+		Version:     model.Version(),
+		Renderer:    c.renderer,
+		Direction:   c.direction,
+		Compact:     c.compact,
+		Key:         v.Key,
+		Title:       v.Title,
+		Description: v.Description,
+		Kind:        v.Kind,
+		Props:       v.Props,
+		Layout:      layout,
+	}
+	if c.g.Design.Views != nil {
+		content.Styles = c.g.Design.Views.Styles
+	}
+	for _, e := range v.Elements {
+		ec := &elementContent{
+			ID:          e.ID,
+			Name:        e.Name,
+			Kind:        e.Kind,
+			Path:        e.Path,
+			Description: e.Description,
+			Technology:  e.Technology,
+			Tags:        e.Tags,
+			Properties:  e.Properties,
+		}
+		if e.Parent != nil {
+			ec.Parent = e.Parent.ID
+		}
+		content.Elements = append(content.Elements, ec)
+	}
+	for _, r := range v.Relationships {
+		content.Relationships = append(content.Relationships, &relationshipContent{
+			Relationship: r.Relationship,
+			Description:  r.Description,
+			Order:        r.Order,
+			Props:        r.Props,
+		})
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// svgDigest returns the view digest recorded in the SVG document b, the empty
+// string if there is none.
+func svgDigest(b []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("invalid SVG: %w", err)
+		}
+		if root, ok := tok.(xml.StartElement); ok {
+			for _, a := range root.Attr {
+				if a.Name.Local == digestAttr {
+					return a.Value, nil
+				}
+			}
+			return "", nil
+		}
+	}
+}
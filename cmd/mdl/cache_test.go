@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func cacheTestDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person",
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "shop", Description: "Buys from"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{
+				{ID: "shop", Name: "Shop", Tags: "Element,Software System"},
+				{ID: "bank", Name: "Bank", Tags: "Element,Software System"},
+			},
+		},
+		Views: &mdl.Views{
+			LandscapeViews: []*mdl.LandscapeView{{ViewProps: &mdl.ViewProps{
+				Key:               "Landscape",
+				ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "shop"}},
+				RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}},
+			}}},
+			ContextViews: []*mdl.ContextView{{SoftwareSystemID: "bank", ViewProps: &mdl.ViewProps{
+				Key:          "Bank",
+				ElementViews: []*mdl.ElementView{{ID: "bank"}},
+			}}},
+			Styles: &mdl.Styles{},
+		},
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	const svg = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10">` +
+		`<script type="application/json"><![CDATA[{"layout":{"user":{"x":10,"y":20}}}]]></script></svg>`
+	keys := []string{"Landscape", "Bank"}
+	render := func(t *testing.T, dir string, c *renderCache) {
+		t.Helper()
+		for _, key := range keys {
+			b, err := c.stamp(key, []byte(svg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, key+".svg"), b, 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	cases := []struct {
+		Name   string
+		Change func(d *mdl.Design, cfg *config, dir string)
+		Stale  []string
+	}{
+		{"unchanged", func(*mdl.Design, *config, string) {}, nil},
+		{"element", func(d *mdl.Design, _ *config, _ string) { d.Model.People[0].Description = "Shops" }, []string{"Landscape"}},
+		{"relationship", func(d *mdl.Design, _ *config, _ string) { d.Model.People[0].Relationships[0].Technology = "HTTPS" }, []string{"Landscape"}},
+		{"element outside of views", func(d *mdl.Design, _ *config, _ string) {
+			d.Model.Systems = append(d.Model.Systems, &mdl.SoftwareSystem{ID: "other", Name: "Other"})
+		}, nil},
+		{"styles", func(d *mdl.Design, _ *config, _ string) {
+			d.Views.Styles.Elements = []*mdl.ElementStyle{{Tag: "Person", Background: "#000000"}}
+		}, keys},
+		{"options", func(_ *mdl.Design, cfg *config, _ string) { cfg.direction = "left" }, keys},
+		{"layout", func(_ *mdl.Design, _ *config, dir string) {
+			p := filepath.Join(dir, "Bank.svg")
+			b, _ := os.ReadFile(p)
+			os.WriteFile(p, []byte(strings.Replace(string(b), `"x":10`, `"x":30`, 1)), 0600) // nolint: errcheck
+		}, []string{"Bank"}},
+		{"missing digest", func(_ *mdl.Design, _ *config, dir string) {
+			os.WriteFile(filepath.Join(dir, "Bank.svg"), []byte(svg), 0600) // nolint: errcheck
+		}, []string{"Bank"}},
+		{"missing file", func(_ *mdl.Design, _ *config, dir string) {
+			os.Remove(filepath.Join(dir, "Landscape.svg")) // nolint: errcheck
+		}, []string{"Landscape"}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := config{renderer: "native"}
+			render(t, dir, newRenderCache(cacheTestDesign(), cfg))
+			d := cacheTestDesign()
+			c.Change(d, &cfg, dir)
+			stale, err := newRenderCache(d, cfg).stale(keys, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(stale, c.Stale) {
+				t.Errorf("got stale views %v, want %v", stale, c.Stale)
+			}
+		})
+	}
+}
+
+func TestRenderCacheStamp(t *testing.T) {
+	c := newRenderCache(cacheTestDesign(), config{})
+	b, err := c.stamp("Bank", []byte(`<?xml version="1.0"?><svg viewBox="0 0 10 10"></svg>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := svgDigest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := c.digest("Bank", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != want {
+		t.Errorf("got digest %q in %s, want %q", digest, b, want)
+	}
+	if _, err := c.stamp("Unknown", b); err == nil {
+		t.Error("expected error for unknown view")
+	}
+}
@@ -0,0 +1,314 @@
+// This file implements the check mode of the svg command. The views are
+// rendered in a temporary directory and compared with the SVG files of the
+// output directory so that CI can verify that committed diagrams match the
+// current model.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// diagram is the structure of a rendered view compared by the check mode.
+type diagram struct {
+	// Elements lists the IDs of the elements of the view.
+	Elements []string
+	// Layout is the layout embedded in the SVG: the positions of the
+	// elements and the vertices of the relationships.
+	Layout Layout
+	// Paths maps the IDs of the relationships to their SVG path.
+	Paths map[string]string
+}
+
+// runCheck renders the selected views of the design described in pkg and
+// reports the views whose rendering differs from the SVG files in cfg.dir.
+func runCheck(pkg string, cfg config) error {
+	absDir, err := filepath.Abs(cfg.dir)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp("", "mdl-check")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove temp dir: %v\n", err)
+		}
+	}()
+
+	// Render the views with the layouts saved in the output directory.
+	if err := copyLayouts(absDir, tmpDir); err != nil {
+		return err
+	}
+	rcfg := cfg
+	rcfg.dir, rcfg.rerender, rcfg.quiet = tmpDir, true, true
+	_, selected, err := renderSVG(pkg, rcfg)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, key := range selected {
+		diffs, err := checkView(filepath.Join(absDir, key+".svg"), filepath.Join(tmpDir, key+".svg"))
+		if err != nil {
+			return fmt.Errorf("view %s: %w", key, err)
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("ok\t%s\n", key)
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL\t%s\n", key)
+		for _, d := range diffs {
+			fmt.Printf("\t%s\n", d)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d views differ from the SVG files in %s, run \"mdl svg\" to update them", failed, len(selected), cfg.dir)
+	}
+	return nil
+}
+
+// copyLayouts copies the SVG files and the layout file of dir to tmpDir.
+func copyLayouts(dir, tmpDir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".svg") && e.Name() != "layout.json" {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, e.Name()), b, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkView returns the differences between the committed SVG file and the
+// rendered one.
+func checkView(committed, rendered string) ([]string, error) {
+	b, err := os.ReadFile(committed)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{"missing SVG file " + committed}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	want, err := parseDiagram(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", committed, err)
+	}
+	if b, err = os.ReadFile(rendered); err != nil {
+		return nil, err
+	}
+	got, err := parseDiagram(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rendered, err)
+	}
+	return compareDiagrams(want, got), nil
+}
+
+// parseDiagram extracts the structure of the SVG document b.
+func parseDiagram(b []byte) (*diagram, error) {
+	meta, err := svgMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+	d := &diagram{Paths: make(map[string]string)}
+	if meta != nil {
+		d.Layout, _ = meta["layout"].(map[string]any)
+		elems, _ := meta["elements"].([]any)
+		for _, e := range elems {
+			if m, ok := e.(map[string]any); ok {
+				if id, ok := m["id"].(string); ok {
+					d.Elements = append(d.Elements, id)
+				}
+			}
+		}
+	}
+	slices.Sort(d.Elements)
+
+	// The browser serializes the SVG as HTML, parse it leniently.
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+	var edge string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid SVG: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attrs := make(map[string]string, len(se.Attr))
+		for _, a := range se.Attr {
+			attrs[a.Name.Local] = a.Value
+		}
+		if !slices.Contains(strings.Fields(attrs["class"]), "edge") {
+			continue
+		}
+		switch se.Name.Local {
+		case "g":
+			edge = attrs["id"]
+		case "path":
+			if edge != "" {
+				d.Paths[edge] = attrs["d"]
+			}
+		}
+	}
+	return d, nil
+}
+
+// compareDiagrams returns a description of the differences between the
+// committed diagram want and the rendered diagram got.
+func compareDiagrams(want, got *diagram) []string {
+	var diffs []string
+	for _, id := range got.Elements {
+		if !slices.Contains(want.Elements, id) {
+			diffs = append(diffs, fmt.Sprintf("element %s is not in the committed diagram", id))
+		}
+	}
+	for _, id := range want.Elements {
+		if !slices.Contains(got.Elements, id) {
+			diffs = append(diffs, fmt.Sprintf("element %s is no longer in the view", id))
+		}
+	}
+	for _, id := range sortedKeys(want.Layout, got.Layout) {
+		w, g := want.Layout[id], got.Layout[id]
+		if w == nil || g == nil || !sameValue(w, g) {
+			diffs = append(diffs, fmt.Sprintf("layout of %s: committed %s, rendered %s", id, jsonString(w), jsonString(g)))
+		}
+	}
+	for _, id := range sortedKeys(want.Paths, got.Paths) {
+		w, wok := want.Paths[id]
+		g, gok := got.Paths[id]
+		switch {
+		case !wok:
+			diffs = append(diffs, fmt.Sprintf("relationship %s is not in the committed diagram", id))
+		case !gok:
+			diffs = append(diffs, fmt.Sprintf("relationship %s is no longer in the view", id))
+		case !samePath(w, g):
+			diffs = append(diffs, fmt.Sprintf("path of relationship %s: committed %q, rendered %q", id, w, g))
+		}
+	}
+	return diffs
+}
+
+// positionTolerance is the maximum difference between coordinates considered
+// equal, it absorbs rounding differences between renderings.
+const positionTolerance = 0.5
+
+// sameValue returns true if the JSON values a and b are equal, numbers being
+// compared with positionTolerance.
+func sameValue(a, b any) bool {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		return ok && math.Abs(a-b) <= positionTolerance
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !sameValue(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if !sameValue(v, b[k]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+// pathTokens matches the commands and the numbers of SVG paths.
+var pathTokens = regexp.MustCompile(`[A-Za-z]|[-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?`)
+
+// samePath returns true if the SVG paths a and b have the same commands and
+// coordinates, coordinates being compared with positionTolerance.
+func samePath(a, b string) bool {
+	at, bt := pathTokens.FindAllString(a, -1), pathTokens.FindAllString(b, -1)
+	if len(at) != len(bt) {
+		return false
+	}
+	for i := range at {
+		x, errx := strconv.ParseFloat(at[i], 64)
+		y, erry := strconv.ParseFloat(bt[i], 64)
+		if errx != nil || erry != nil {
+			if at[i] != bt[i] {
+				return false
+			}
+			continue
+		}
+		if math.Abs(x-y) > positionTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the sorted union of the keys of a and b.
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// jsonString returns the JSON representation of v.
+func jsonString(v any) string {
+	if v == nil {
+		return "none"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
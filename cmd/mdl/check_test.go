@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDiagram(t *testing.T) {
+	const svg = `<svg xmlns="http://www.w3.org/2000/svg"><script type="application/json"><![CDATA[{
+		"elements": [{"id": "web"}, {"id": "user"}],
+		"layout": {"user": {"x": 10, "y": 20}, "e-r1": [{"x": 5, "y": 5}]}
+	}]]></script>
+	<g class="edge" id="r1" data-from="user" data-to="web"><path class="edge" d="M10,20 L30,40"></path></g>
+	<g class="node" id="web"><path d="M0,0 L1,1"/></g></svg>`
+	d, err := parseDiagram([]byte(svg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d.Elements, []string{"user", "web"}) {
+		t.Errorf("got elements %v, want user, web", d.Elements)
+	}
+	if len(d.Layout) != 2 {
+		t.Errorf("got layout %v, want 2 entries", d.Layout)
+	}
+	if !reflect.DeepEqual(d.Paths, map[string]string{"r1": "M10,20 L30,40"}) {
+		t.Errorf("got paths %v, want path of r1", d.Paths)
+	}
+}
+
+func TestCompareDiagrams(t *testing.T) {
+	base := func() *diagram {
+		return &diagram{
+			Elements: []string{"user", "web"},
+			Layout:   Layout{"user": map[string]any{"x": 10.0, "y": 20.0}},
+			Paths:    map[string]string{"r1": "M10,20 L30,40"},
+		}
+	}
+	cases := []struct {
+		Name   string
+		Change func(d *diagram)
+		Diffs  []string
+	}{
+		{"same", func(*diagram) {}, nil},
+		{"rounding", func(d *diagram) {
+			d.Layout["user"] = map[string]any{"x": 10.2, "y": 20.0}
+			d.Paths["r1"] = "M 10.3 20 L 30 40"
+		}, nil},
+		{"added element", func(d *diagram) { d.Elements = append(d.Elements, "db") }, []string{
+			"element db is not in the committed diagram",
+		}},
+		{"removed element", func(d *diagram) { d.Elements = d.Elements[:1] }, []string{
+			"element web is no longer in the view",
+		}},
+		{"moved element", func(d *diagram) { d.Layout["user"] = map[string]any{"x": 15.0, "y": 20.0} }, []string{
+			`layout of user: committed {"x":10,"y":20}, rendered {"x":15,"y":20}`,
+		}},
+		{"new path", func(d *diagram) { d.Paths["r1"] = "M10,20 L30,60" }, []string{
+			`path of relationship r1: committed "M10,20 L30,40", rendered "M10,20 L30,60"`,
+		}},
+		{"removed relationship", func(d *diagram) { delete(d.Paths, "r1") }, []string{
+			"relationship r1 is no longer in the view",
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			got := base()
+			c.Change(got)
+			if diffs := compareDiagrams(base(), got); !reflect.DeepEqual(diffs, c.Diffs) {
+				t.Errorf("got differences %q, want %q", diffs, c.Diffs)
+			}
+		})
+	}
+}
+
+// TestSVGCheck checks the SVG files rendered for the basic example against
+// a fresh rendering.
+func TestSVGCheck(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := config{dir: outDir, renderer: "native", all: true}
+	if err := runSVG("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("runSVG failed: %v", err)
+	}
+	cfg.check = true
+	if err := runSVG("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("check failed on up to date diagrams: %v", err)
+	}
+	if err := os.Remove(filepath.Join(outDir, "SystemContext.svg")); err != nil {
+		t.Fatal(err)
+	}
+	err := runSVG("goa.design/model/examples/basic/model", cfg)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 views differ") {
+		t.Fatalf("got error %v, want 1 of 2 views differ", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "SystemContext.svg")); err == nil {
+		t.Errorf("check wrote SystemContext.svg")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"goa.design/model/codegen"
+	"goa.design/model/diff"
+	"goa.design/model/mdl"
+	"goa.design/model/stz"
+)
+
+// runDiff prints the changes between the designs described by old and new.
+// Each argument is either the path to a design or Structurizr workspace JSON
+// file, a package import path or a package import path followed by "@" and a
+// git revision.
+func runDiff(old, new string, cfg config) error {
+	if old == "" || new == "" {
+		return fmt.Errorf(`missing OLD or NEW argument, use "--help" for usage`)
+	}
+	write, err := diffWriter(cfg.format)
+	if err != nil {
+		return err
+	}
+	od, err := loadDiffDesign(old, cfg.debug)
+	if err != nil {
+		return fmt.Errorf("%s: %w", old, err)
+	}
+	nd, err := loadDiffDesign(new, cfg.debug)
+	if err != nil {
+		return fmt.Errorf("%s: %w", new, err)
+	}
+	return write(os.Stdout, diff.Compare(od, nd))
+}
+
+// diffWriter returns the function that writes changes in the given format.
+func diffWriter(format string) (func(io.Writer, []*diff.Change) error, error) {
+	switch format {
+	case "", "text":
+		return diff.WriteText, nil
+	case "markdown", "md":
+		return diff.WriteMarkdown, nil
+	case "json":
+		return diff.WriteJSON, nil
+	default:
+		return nil, fmt.Errorf("invalid diff format %q: use text, markdown or json", format)
+	}
+}
+
+// loadDiffDesign loads the design identified by arg, see runDiff.
+func loadDiffDesign(arg string, debug bool) (*mdl.Design, error) {
+	if strings.HasSuffix(arg, ".json") {
+		return stz.LoadDesign(arg)
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if pkg, rev, ok := strings.Cut(arg, "@"); ok {
+		b, err = jsonAtRevision(pkg, rev, debug)
+	} else {
+		b, err = codegen.JSON(pkg, debug)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var design mdl.Design
+	if err := json.Unmarshal(b, &design); err != nil {
+		return nil, fmt.Errorf("failed to load design: %s", err.Error())
+	}
+	return &design, nil
+}
+
+// jsonAtRevision generates the JSON representation of the design described in
+// pkg as of the given git revision. It checks out the revision in a temporary
+// git worktree and generates the JSON from the same relative directory.
+func jsonAtRevision(pkg, rev string, debug bool) ([]byte, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	top, err := git(cwd, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(top, cwd)
+	if err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp("", codegen.TmpDirPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+	wt := filepath.Join(tmpDir, "worktree")
+	if _, err := git(cwd, "worktree", "add", "--detach", wt, rev); err != nil {
+		return nil, err
+	}
+	defer git(cwd, "worktree", "remove", "--force", wt) // nolint: errcheck
+
+	return codegen.JSONInDir(filepath.Join(wt, rel), pkg, debug)
+}
+
+// git runs the git command with the given arguments in dir and returns its
+// trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	b, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(b)))
+	}
+	return strings.TrimSpace(string(b)), nil
+}
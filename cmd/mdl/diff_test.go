@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffWriter(t *testing.T) {
+	for _, format := range []string{"", "text", "markdown", "md", "json"} {
+		if _, err := diffWriter(format); err != nil {
+			t.Errorf("format %q: unexpected error %v", format, err)
+		}
+	}
+	if _, err := diffWriter("html"); err == nil {
+		t.Errorf("format html: expected error")
+	}
+}
+
+func TestLoadDiffDesignFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "design.json")
+	if err := os.WriteFile(path, []byte(`{"name":"Test","model":{"people":[{"id":"p","name":"User"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d, err := loadDiffDesign(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Test" || len(d.Model.People) != 1 {
+		t.Errorf("got design %+v", d)
+	}
+	if _, err := loadDiffDesign(filepath.Join(t.TempDir(), "missing.json"), false); err == nil {
+		t.Errorf("expected error for missing file")
+	}
+
+	workspace := filepath.Join(t.TempDir(), "workspace.json")
+	if err := os.WriteFile(workspace, []byte(`{"id":1,"name":"Test","model":{"people":[{"id":"p","name":"User"}]},"views":{"configuration":{"styles":{"elements":[{"tag":"Person"}]}}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	d, err = loadDiffDesign(workspace, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Model.People) != 1 || d.Views.Styles == nil || len(d.Views.Styles.Elements) != 1 {
+		t.Errorf("got workspace design %+v", d)
+	}
+}
+
+func TestJSONAtRevision(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generator")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := git(cwd, "rev-parse", "HEAD"); err != nil {
+		t.Skip("not in a git repository")
+	}
+	b, err := jsonAtRevision("goa.design/model/examples/basic/model", "HEAD", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"model"`) {
+		t.Errorf("unexpected output %s", b)
+	}
+	if got, err := os.Getwd(); err != nil || got != cwd {
+		t.Errorf("got working directory %q, want %q", got, cwd)
+	}
+}
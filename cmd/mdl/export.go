@@ -0,0 +1,305 @@
+// This file converts the SVG files rendered by the svg command to PNG and PDF
+// documents. The conversion runs in the same headless browser session used to
+// render the views so that the output matches what the editor displays.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// exportPage is a view converted by the export command.
+type exportPage struct {
+	// Key is the view key.
+	Key string
+	// SVG is the content of the SVG file rendered for the view.
+	SVG []byte
+	// Width and Height are the dimensions of the SVG in pixels.
+	Width, Height float64
+	// PaperSize is the paper size of the view.
+	PaperSize mdl.PaperSizeKind
+}
+
+// pageMargin is the margin around the diagrams in PDF pages in inches.
+const pageMargin = 0.4
+
+// runExport renders the selected views of the design described in pkg as SVG
+// files and converts them to the format given by cfg.format.
+func runExport(pkg string, cfg config) error {
+	switch cfg.format {
+	case "", "svg":
+		return runSVG(pkg, cfg)
+	case "png", "pdf":
+	default:
+		return fmt.Errorf("invalid export format %q: use svg, png or pdf", cfg.format)
+	}
+	if cfg.scale <= 0 {
+		return fmt.Errorf("invalid scale %v: must be positive", cfg.scale)
+	}
+	design, selected, err := renderSVG(pkg, cfg)
+	if err != nil {
+		return err
+	}
+	absDir, err := filepath.Abs(cfg.dir)
+	if err != nil {
+		return err
+	}
+	pages, err := exportPages(design, selected, absDir)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := os.MkdirTemp("", "mdl-export")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to remove temp dir: %v\n", err)
+		}
+	}()
+
+	return withChromedp(cfg.timeout, cfg.debug, func(exec navigateExec) error {
+		if cfg.format == "pdf" && cfg.combine != "" {
+			return exportPDF(exec, pages, tmpDir, cfg.combine, cfg.timeout*time.Duration(len(pages)))
+		}
+		for _, p := range pages {
+			out := filepath.Join(absDir, p.Key+"."+cfg.format)
+			var err error
+			if cfg.format == "png" {
+				err = exportPNG(exec, p, tmpDir, out, cfg.scale, cfg.timeout)
+			} else {
+				err = exportPDF(exec, []*exportPage{p}, tmpDir, out, cfg.timeout)
+			}
+			if err != nil {
+				return fmt.Errorf("export %s: %w", p.Key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// exportPages reads the SVG files rendered for the selected views of design
+// in dir.
+func exportPages(design *mdl.Design, selected []string, dir string) ([]*exportPage, error) {
+	g := query.New(design)
+	pages := make([]*exportPage, 0, len(selected))
+	for _, key := range selected {
+		b, err := os.ReadFile(filepath.Join(dir, key+".svg"))
+		if err != nil {
+			return nil, err
+		}
+		w, h, err := svgSize(b)
+		if err != nil {
+			return nil, fmt.Errorf("view %s: %w", key, err)
+		}
+		p := &exportPage{Key: key, SVG: b, Width: w, Height: h}
+		if v := g.View(key); v != nil && v.Props != nil {
+			p.PaperSize = v.Props.PaperSize
+		}
+		pages = append(pages, p)
+	}
+	return pages, nil
+}
+
+// exportPNG writes the PNG rendering of p at the given scale to out.
+func exportPNG(exec navigateExec, p *exportPage, tmpDir, out string, scale float64, timeout time.Duration) error {
+	doc := `<!doctype html><html><head><style>body{margin:0}img{display:block}</style></head><body>` +
+		imageTag(p, fmt.Sprintf("width:%gpx;height:%gpx", p.Width, p.Height)) + `</body></html>`
+	var b []byte
+	capture := chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		b, err = page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormatPng).
+			WithCaptureBeyondViewport(true).
+			WithClip(&page.Viewport{Width: p.Width, Height: p.Height, Scale: scale}).
+			Do(ctx)
+		return err
+	})
+	if err := openDocument(exec, doc, tmpDir, timeout, capture); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, b, 0600); err != nil {
+		return err
+	}
+	fmt.Println("Saved:", out)
+	return nil
+}
+
+// exportPDF writes a PDF document to out with one page per element of pages.
+func exportPDF(exec navigateExec, pages []*exportPage, tmpDir, out string, timeout time.Duration) error {
+	var b []byte
+	printPDF := chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		b, _, err = page.PrintToPDF().
+			WithPreferCSSPageSize(true).
+			WithPrintBackground(true).
+			WithMarginTop(0).
+			WithMarginBottom(0).
+			WithMarginLeft(0).
+			WithMarginRight(0).
+			Do(ctx)
+		return err
+	})
+	if err := openDocument(exec, pdfDocument(pages), tmpDir, timeout, printPDF); err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, b, 0600); err != nil {
+		return err
+	}
+	fmt.Println("Saved:", out)
+	return nil
+}
+
+// openDocument writes the HTML document doc in tmpDir, opens it in the browser
+// and runs the given action once it is loaded.
+func openDocument(exec navigateExec, doc, tmpDir string, timeout time.Duration, action chromedp.Action) error {
+	f, err := os.CreateTemp(tmpDir, "*.html")
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(doc); err != nil {
+		f.Close() // nolint: errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(f.Name())}
+	return exec(u.String(), timeout, action)
+}
+
+// pdfDocument returns the HTML document printed to produce the PDF pages. Each
+// page is sized according to the paper size of the view and the diagram is
+// scaled to fit the page.
+func pdfDocument(pages []*exportPage) string {
+	var css, body strings.Builder
+	css.WriteString("body{margin:0}")
+	css.WriteString(fmt.Sprintf(".page{box-sizing:border-box;padding:%gin;break-after:page}", pageMargin))
+	css.WriteString(".page:last-child{break-after:auto}")
+	css.WriteString("img{display:block;width:100%;height:100%;object-fit:contain}")
+	for i, p := range pages {
+		w, h, ok := paperSize(p.PaperSize)
+		if !ok {
+			// Browsers render CSS pixels at 96 per inch.
+			w, h = p.Width/96+2*pageMargin, p.Height/96+2*pageMargin
+		}
+		fmt.Fprintf(&css, "@page p%d{size:%.3fin %.3fin;margin:0}", i, w, h)
+		fmt.Fprintf(&body, `<div class="page" style="page:p%d;width:%.3fin;height:%.3fin">%s</div>`,
+			i, w, h, imageTag(p, ""))
+	}
+	return `<!doctype html><html><head><style>` + css.String() + `</style></head><body>` +
+		body.String() + `</body></html>`
+}
+
+// imageTag returns the HTML image element that displays the SVG of p. Using
+// images rather than inline SVG elements keeps the identifiers used by the
+// diagrams of different views from clashing.
+func imageTag(p *exportPage, style string) string {
+	src := "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(p.SVG)
+	tag := `<img alt="` + html.EscapeString(p.Key) + `" src="` + src + `"`
+	if style != "" {
+		tag += ` style="` + style + `"`
+	}
+	return tag + `>`
+}
+
+// paperSize returns the width and height in inches of the given paper size.
+// It returns false if the size is undefined.
+func paperSize(size mdl.PaperSizeKind) (width, height float64, ok bool) {
+	// ISO 216 sizes in millimeters, portrait orientation.
+	iso := map[mdl.PaperSizeKind][2]float64{
+		mdl.SizeA0Portrait: {841, 1189},
+		mdl.SizeA1Portrait: {594, 841},
+		mdl.SizeA2Portrait: {420, 594},
+		mdl.SizeA3Portrait: {297, 420},
+		mdl.SizeA4Portrait: {210, 297},
+		mdl.SizeA5Portrait: {148, 210},
+		mdl.SizeA6Portrait: {105, 148},
+	}
+	switch size {
+	case mdl.SizeA0Landscape, mdl.SizeA1Landscape, mdl.SizeA2Landscape, mdl.SizeA3Landscape,
+		mdl.SizeA4Landscape, mdl.SizeA5Landscape, mdl.SizeA6Landscape:
+		// Landscape sizes directly precede their portrait counterpart.
+		mm := iso[size+1]
+		return mm[1] / 25.4, mm[0] / 25.4, true
+	case mdl.SizeA0Portrait, mdl.SizeA1Portrait, mdl.SizeA2Portrait, mdl.SizeA3Portrait,
+		mdl.SizeA4Portrait, mdl.SizeA5Portrait, mdl.SizeA6Portrait:
+		mm := iso[size]
+		return mm[0] / 25.4, mm[1] / 25.4, true
+	case mdl.SizeLegalLandscape:
+		return 14, 8.5, true
+	case mdl.SizeLegalPortrait:
+		return 8.5, 14, true
+	case mdl.SizeLetterLandscape:
+		return 11, 8.5, true
+	case mdl.SizeLetterPortrait:
+		return 8.5, 11, true
+	case mdl.SizeSlide16X9:
+		return 13.333, 7.5, true
+	case mdl.SizeSlide16X10:
+		return 12, 7.5, true
+	case mdl.SizeSlide4X3:
+		return 10, 7.5, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// svgSize returns the dimensions in pixels of the SVG document doc. It uses
+// the view box of the root element if any and its width and height otherwise.
+func svgSize(doc []byte) (width, height float64, err error) {
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid SVG: %w", err)
+		}
+		root, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root.Name.Local != "svg" {
+			return 0, 0, fmt.Errorf("invalid SVG: root element is %q", root.Name.Local)
+		}
+		var w, h, viewBox string
+		for _, a := range root.Attr {
+			switch a.Name.Local {
+			case "width":
+				w = a.Value
+			case "height":
+				h = a.Value
+			case "viewBox":
+				viewBox = a.Value
+			}
+		}
+		if fields := strings.Fields(strings.ReplaceAll(viewBox, ",", " ")); len(fields) == 4 {
+			width, err1 := strconv.ParseFloat(fields[2], 64)
+			height, err2 := strconv.ParseFloat(fields[3], 64)
+			if err1 == nil && err2 == nil && width > 0 && height > 0 {
+				return width, height, nil
+			}
+		}
+		width, err1 := strconv.ParseFloat(strings.TrimSuffix(w, "px"), 64)
+		height, err2 := strconv.ParseFloat(strings.TrimSuffix(h, "px"), 64)
+		if err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+			return 0, 0, fmt.Errorf("SVG has no view box and no size in pixels")
+		}
+		return width, height, nil
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"goa.design/model/mdl"
+)
+
+func TestPaperSize(t *testing.T) {
+	cases := []struct {
+		Size          mdl.PaperSizeKind
+		Width, Height float64
+		OK            bool
+	}{
+		{mdl.SizeUndefined, 0, 0, false},
+		{mdl.SizeA4Landscape, 11.69, 8.27, true},
+		{mdl.SizeA4Portrait, 8.27, 11.69, true},
+		{mdl.SizeA0Landscape, 46.81, 33.11, true},
+		{mdl.SizeA6Portrait, 4.13, 5.83, true},
+		{mdl.SizeLetterLandscape, 11, 8.5, true},
+		{mdl.SizeLegalPortrait, 8.5, 14, true},
+		{mdl.SizeSlide16X9, 13.33, 7.5, true},
+		{mdl.SizeSlide4X3, 10, 7.5, true},
+	}
+	for _, c := range cases {
+		w, h, ok := paperSize(c.Size)
+		if ok != c.OK || math.Abs(w-c.Width) > 0.01 || math.Abs(h-c.Height) > 0.01 {
+			t.Errorf("%s: got %.2fx%.2f (%v), want %.2fx%.2f (%v)", c.Size.Name(), w, h, ok, c.Width, c.Height, c.OK)
+		}
+	}
+}
+
+func TestSVGSize(t *testing.T) {
+	cases := []struct {
+		Name          string
+		Doc           string
+		Width, Height float64
+		Err           string
+	}{
+		{"view box", `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 800 600" width="400" height="300"/>`, 800, 600, ""},
+		{"size", `<?xml version="1.0"?><svg width="400px" height="300px"></svg>`, 400, 300, ""},
+		{"relative size", `<svg width="100%" height="100%"></svg>`, 0, 0, "no view box"},
+		{"not svg", `<html></html>`, 0, 0, "root element"},
+		{"empty", ``, 0, 0, "invalid SVG"},
+	}
+	for _, c := range cases {
+		w, h, err := svgSize([]byte(c.Doc))
+		if c.Err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.Err) {
+				t.Errorf("%s: got error %v, want %q", c.Name, err, c.Err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.Name, err)
+			continue
+		}
+		if w != c.Width || h != c.Height {
+			t.Errorf("%s: got %vx%v, want %vx%v", c.Name, w, h, c.Width, c.Height)
+		}
+	}
+}
+
+func TestPDFDocument(t *testing.T) {
+	pages := []*exportPage{
+		{Key: "A4", SVG: []byte("<svg/>"), Width: 960, Height: 480, PaperSize: mdl.SizeA4Landscape},
+		{Key: "Free", SVG: []byte("<svg/>"), Width: 960, Height: 480},
+	}
+	doc := pdfDocument(pages)
+	for _, want := range []string{
+		"@page p0{size:11.693in 8.268in;margin:0}",
+		"@page p1{size:10.800in 5.800in;margin:0}",
+		`style="page:p0;`,
+		`style="page:p1;`,
+		`alt="A4" src="data:image/svg+xml;base64,`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("missing %q in:\n%s", want, doc)
+		}
+	}
+}
+
+// This test exports the views of the basic example as PNG and PDF documents.
+// Requires headless Chrome available in environment.
+func TestExportEndToEnd(t *testing.T) {
+	if !hasChrome() {
+		t.Skip("skipping: Chrome/Chromium not available in PATH")
+	}
+
+	outDir := t.TempDir()
+	cfg := config{
+		dir:      outDir,
+		renderer: "native",
+		timeout:  30 * time.Second,
+		all:      true,
+		format:   "png",
+		scale:    2,
+	}
+	if err := runExport("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("export PNG: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(outDir, "SystemContext.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, _, err := svgSize(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filepath.Join(outDir, "SystemContext.png"))
+	if err != nil {
+		t.Fatalf("missing PNG: %v", err)
+	}
+	defer f.Close() // nolint: errcheck
+	img, err := png.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("invalid PNG: %v", err)
+	}
+	if math.Abs(float64(img.Width)-2*w) > 2 {
+		t.Errorf("got PNG width %d, want %v", img.Width, 2*w)
+	}
+
+	cfg.format = "pdf"
+	cfg.combine = filepath.Join(outDir, "all.pdf")
+	if err := runExport("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("export PDF: %v", err)
+	}
+	pdf, err := os.ReadFile(cfg.combine)
+	if err != nil {
+		t.Fatalf("missing PDF: %v", err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("got %q, want a PDF document", pdf[:min(len(pdf), 16)])
+	}
+}
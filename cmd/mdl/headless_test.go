@@ -2,9 +2,13 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestRenderBrokerMatchesViewAndDigest(t *testing.T) {
@@ -81,3 +85,69 @@ func TestValidateHeadlessResult(t *testing.T) {
 		})
 	}
 }
+
+func TestDispatchViews(t *testing.T) {
+	views := []string{"A", "B", "C", "D", "E", "F"}
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+		done    []string
+	)
+	render := func(viewID string, _ navigateExec) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		done = append(done, viewID)
+		mu.Unlock()
+		return nil
+	}
+	execs := make([]navigateExec, 3)
+	if err := dispatchViews(views, execs, render); err != nil {
+		t.Fatalf("dispatch views: %v", err)
+	}
+	if len(done) != len(views) {
+		t.Errorf("got rendered views %v, want %v", done, views)
+	}
+	if peak < 2 || peak > 3 {
+		t.Errorf("got %d concurrent renders, want 2 or 3", peak)
+	}
+}
+
+func TestDispatchViewsReportsFailures(t *testing.T) {
+	views := []string{"A", "B", "C", "D"}
+	var (
+		mu       sync.Mutex
+		rendered []string
+	)
+	render := func(viewID string, _ navigateExec) error {
+		mu.Lock()
+		rendered = append(rendered, viewID)
+		mu.Unlock()
+		if viewID == "A" || viewID == "B" {
+			// Let both workers pick up their view before failing.
+			time.Sleep(10 * time.Millisecond)
+			return errors.New("timeout waiting for browser result")
+		}
+		return nil
+	}
+	err := dispatchViews(views, make([]navigateExec, 2), render)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{
+		"render A: timeout waiting for browser result",
+		"render B: timeout waiting for browser result",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in %q", want, err.Error())
+		}
+	}
+	if len(rendered) != 2 {
+		t.Errorf("got rendered views %v, want no view dispatched after the failures", rendered)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+type (
+	// impactReport lists what is affected by a change to an element.
+	impactReport struct {
+		// Target is the changed element.
+		Target *query.Element
+		// Dependents lists the elements that depend on the target directly
+		// or transitively, closest first.
+		Dependents []*impactedElement
+		// Instances lists the deployment instances of the target and of its
+		// dependents.
+		Instances []*query.Element
+		// Views lists the keys of the views that include the target, a
+		// dependent or an instance.
+		Views []string
+		// Teams lists the owning teams of the target, dependents and
+		// instances.
+		Teams []string
+	}
+
+	// impactedElement is an element that depends on the target.
+	impactedElement struct {
+		// Element is the dependent element.
+		Element *query.Element
+		// Chain describes the shortest chain of relationships from the
+		// element to the target, one relationship per entry.
+		Chain []string
+	}
+)
+
+// runImpact prints the elements, deployment instances, views and teams
+// affected by a change to the element with the given path in the design
+// described in pkg.
+func runImpact(pkg, path string, cfg config) error {
+	if cfg.design != "" && path == "" {
+		// The element path is the only argument when using -design.
+		pkg, path = "", pkg
+	}
+	if pkg == "" && cfg.design == "" {
+		return errMissingPackage
+	}
+	if path == "" {
+		return fmt.Errorf(`missing element path argument, use "--help" for usage`)
+	}
+	design, err := resolveDesign(pkg, cfg)
+	if err != nil {
+		return err
+	}
+	g := query.New(design)
+	target := g.ElementByPath(path)
+	if target == nil {
+		return fmt.Errorf("no element with path %q in design", path)
+	}
+	printImpact(os.Stdout, impact(g, target, cfg.team), cfg.team)
+	return nil
+}
+
+// impact computes the impact of a change to target. teamProp is the name of
+// the element property that holds the owning team.
+func impact(g *query.Graph, target *query.Element, teamProp string) *impactReport {
+	report := &impactReport{Target: target}
+	affected := []*query.Element{target}
+	for _, d := range dependents(g, target) {
+		if d.Kind == query.KindContainerInstance || d.Kind == query.KindSoftwareSystemInstance {
+			continue
+		}
+		var chain []string
+		for _, rel := range g.ShortestPath(d.ID, target.ID) {
+			chain = append(chain, describeRelationship(g, rel))
+		}
+		report.Dependents = append(report.Dependents, &impactedElement{Element: d, Chain: chain})
+		affected = append(affected, d)
+	}
+	for _, e := range g.Elements() {
+		if e.InstanceOf == nil {
+			continue
+		}
+		for _, a := range affected {
+			// The instance of an affected element, of one of its children
+			// (e.g. the containers of a software system) or of its parent.
+			if a.Contains(e.InstanceOf) || e.InstanceOf.Contains(a) {
+				report.Instances = append(report.Instances, e)
+				break
+			}
+		}
+	}
+	affected = append(affected, report.Instances...)
+
+	views := make(map[string]bool)
+	teams := make(map[string]bool)
+	for _, a := range affected {
+		for _, vp := range g.Views(a.ID) {
+			if !views[vp.Key] {
+				views[vp.Key] = true
+				report.Views = append(report.Views, vp.Key)
+			}
+		}
+		if t := team(a, teamProp); t != "" && !teams[t] {
+			teams[t] = true
+			report.Teams = append(report.Teams, t)
+		}
+	}
+	sort.Strings(report.Views)
+	sort.Strings(report.Teams)
+	return report
+}
+
+// dependents returns the elements that depend on target directly or
+// transitively, closest first. Unlike query.Graph.Dependents, dependents does
+// not walk through the ancestors and descendants of target: a relationship to
+// the software system of a container does not make its source a dependent of
+// the container.
+func dependents(g *query.Graph, target *query.Element) []*query.Element {
+	visited := map[string]bool{target.ID: true}
+	queue := []string{target.ID}
+	var res []*query.Element
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, r := range g.ImpliedIncoming(id) {
+			if visited[r.SourceID] {
+				continue
+			}
+			visited[r.SourceID] = true
+			e := g.Element(r.SourceID)
+			if e == nil || e.Contains(target) || target.Contains(e) {
+				continue
+			}
+			res = append(res, e)
+			queue = append(queue, e.ID)
+		}
+	}
+	return res
+}
+
+// printImpact writes a human readable version of the report to w.
+func printImpact(w io.Writer, r *impactReport, teamProp string) {
+	fmt.Fprintf(w, "Impact of changing %s %q\n", r.Target.Kind, r.Target.Path)
+	fmt.Fprintf(w, "\nDependents (%d):\n", len(r.Dependents))
+	for _, d := range r.Dependents {
+		fmt.Fprintf(w, "  %s %q%s\n", d.Element.Kind, d.Element.Path, teamSuffix(d.Element, teamProp))
+		for _, rel := range d.Chain {
+			fmt.Fprintf(w, "      %s\n", rel)
+		}
+	}
+	fmt.Fprintf(w, "\nDeployment instances (%d):\n", len(r.Instances))
+	for _, i := range r.Instances {
+		fmt.Fprintf(w, "  %s %q%s\n", i.Kind, i.Path, teamSuffix(i, teamProp))
+	}
+	fmt.Fprintf(w, "\nViews (%d):\n", len(r.Views))
+	for _, v := range r.Views {
+		fmt.Fprintf(w, "  %s\n", v)
+	}
+	fmt.Fprintf(w, "\nTeams (%d):\n", len(r.Teams))
+	for _, t := range r.Teams {
+		fmt.Fprintf(w, "  %s\n", t)
+	}
+}
+
+// describeRelationship returns a one line description of rel.
+func describeRelationship(g *query.Graph, rel *mdl.Relationship) string {
+	src, dest := rel.SourceID, rel.DestinationID
+	if e := g.Element(src); e != nil {
+		src = e.Path
+	}
+	if e := g.Element(dest); e != nil {
+		dest = e.Path
+	}
+	res := fmt.Sprintf("%s -> %s", src, dest)
+	if rel.Description != "" {
+		res += ": " + rel.Description
+	}
+	if rel.Technology != "" {
+		res += " [" + rel.Technology + "]"
+	}
+	return res
+}
+
+// team returns the owning team of e read from the property with the given
+// name on e, its ancestors or the element it instantiates.
+func team(e *query.Element, teamProp string) string {
+	if t := e.Property(teamProp); t != "" {
+		return t
+	}
+	if e.InstanceOf != nil {
+		return e.InstanceOf.Property(teamProp)
+	}
+	return ""
+}
+
+// teamSuffix returns the text appended to element descriptions to show
+// their owning team if any.
+func teamSuffix(e *query.Element, teamProp string) string {
+	if t := team(e, teamProp); t != "" {
+		return fmt.Sprintf(" (%s: %s)", teamProp, t)
+	}
+	return ""
+}
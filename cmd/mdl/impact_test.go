@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+func TestImpact(t *testing.T) {
+	d := &mdl.Design{
+		Model: &mdl.Model{
+			People: []*mdl.Person{
+				{ID: "user", Name: "User", Relationships: []*mdl.Relationship{{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses"}}},
+			},
+			Systems: []*mdl.SoftwareSystem{{
+				ID:   "shop",
+				Name: "Shop",
+				Containers: []*mdl.Container{
+					{ID: "web", Name: "Web", Properties: map[string]string{"team": "frontend"}, Relationships: []*mdl.Relationship{
+						{ID: "r2", SourceID: "web", DestinationID: "orders", Description: "Calls", Technology: "HTTP"},
+					}},
+					{ID: "api", Name: "API", Properties: map[string]string{"team": "orders"}, Components: []*mdl.Component{
+						{ID: "orders", Name: "Orders", Relationships: []*mdl.Relationship{{ID: "r3", SourceID: "orders", DestinationID: "db", Description: "Reads from"}}},
+					}},
+					{ID: "db", Name: "DB", Properties: map[string]string{"team": "dba"}},
+					{ID: "batch", Name: "Batch"},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID:          "node",
+				Name:        "Server",
+				Environment: "Production",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "apiInst", ContainerID: "api"},
+					{ID: "dbInst", ContainerID: "db"},
+					{ID: "batchInst", ContainerID: "batch"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{ViewProps: &mdl.ViewProps{Key: "Containers", ElementViews: []*mdl.ElementView{{ID: "web"}, {ID: "db"}}}}},
+			DeploymentViews: []*mdl.DeploymentView{
+				{ViewProps: &mdl.ViewProps{Key: "Production", ElementViews: []*mdl.ElementView{{ID: "node"}, {ID: "apiInst"}}}},
+				{ViewProps: &mdl.ViewProps{Key: "Batch", ElementViews: []*mdl.ElementView{{ID: "batchInst"}}}},
+			},
+		},
+	}
+	g := query.New(d)
+	r := impact(g, g.ElementByPath("Shop/DB"), "team")
+
+	var buf bytes.Buffer
+	printImpact(&buf, r, "team")
+	want := `Impact of changing Container "Shop/DB"
+
+Dependents (3):
+  Component "Shop/API/Orders" (team: orders)
+      Shop/API/Orders -> Shop/DB: Reads from
+  Container "Shop/Web" (team: frontend)
+      Shop/Web -> Shop/API/Orders: Calls [HTTP]
+      Shop/API/Orders -> Shop/DB: Reads from
+  Person "User"
+      User -> Shop/Web: Browses
+      Shop/Web -> Shop/API/Orders: Calls [HTTP]
+      Shop/API/Orders -> Shop/DB: Reads from
+
+Deployment instances (2):
+  Container Instance "Production/Server/API" (team: orders)
+  Container Instance "Production/Server/DB" (team: dba)
+
+Views (2):
+  Containers
+  Production
+
+Teams (3):
+  dba
+  frontend
+  orders
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(buf.String(), "Batch") {
+		t.Errorf("unrelated elements reported")
+	}
+}
+
+func TestImpactDeployedSystemDependent(t *testing.T) {
+	d := &mdl.Design{
+		Model: &mdl.Model{
+			People: []*mdl.Person{
+				{ID: "admin", Name: "Admin", Relationships: []*mdl.Relationship{{ID: "r1", SourceID: "admin", DestinationID: "shop", Description: "Manages"}}},
+			},
+			Systems: []*mdl.SoftwareSystem{
+				{ID: "shop", Name: "Shop", Containers: []*mdl.Container{{ID: "db", Name: "DB"}}},
+				{ID: "billing", Name: "Billing", Relationships: []*mdl.Relationship{
+					{ID: "r2", SourceID: "billing", DestinationID: "db", Description: "Reads invoices from"},
+				}, Containers: []*mdl.Container{{ID: "worker", Name: "Worker"}, {ID: "ui", Name: "UI"}}},
+			},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID:          "node",
+				Name:        "Server",
+				Environment: "Production",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "workerInst", ContainerID: "worker"},
+					{ID: "uiInst", ContainerID: "ui"},
+				},
+			}},
+		},
+	}
+	g := query.New(d)
+	r := impact(g, g.ElementByPath("Shop/DB"), "")
+
+	var deps []string
+	for _, dep := range r.Dependents {
+		deps = append(deps, dep.Element.Path)
+	}
+	if got, want := strings.Join(deps, ","), "Billing"; got != want {
+		t.Errorf("got dependents %q, want %q", got, want)
+	}
+	var instances []string
+	for _, i := range r.Instances {
+		instances = append(instances, i.Path)
+	}
+	if got, want := strings.Join(instances, ","), "Production/Server/Worker,Production/Server/UI"; got != want {
+		t.Errorf("got instances %q, want %q", got, want)
+	}
+}
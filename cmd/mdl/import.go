@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"goa.design/model/codegen"
+	"goa.design/model/stz"
+)
+
+// runImport converts the Structurizr workspace stored in path (JSON or DSL)
+// into Go source code that describes the same design with the model DSL.
+func runImport(path string, cfg config) error {
+	if path == "" {
+		return fmt.Errorf(`missing FILE argument, use "--help" for usage`)
+	}
+	out := cfg.out
+	if out == defaultOut {
+		out = "design.go"
+	}
+	design, err := stz.LoadDesign(path)
+	if err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+	b, err := codegen.DSL(design, packageName(filepath.Base(filepath.Dir(abs))))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, b, 0600)
+}
+
+// packageName returns a valid Go package name derived from the given
+// directory name.
+func packageName(dir string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) && b.Len() > 0) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "design"
+	}
+	return b.String()
+}
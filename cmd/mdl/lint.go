@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"goa.design/model/codegen"
+	"goa.design/model/lint"
+)
+
+// runLint evaluates the design described in pkg, prints the rule violations
+// and returns an error if any violation has severity error.
+func runLint(pkg string, cfg config) error {
+	if cfg.design != "" {
+		return fmt.Errorf("lint requires PACKAGE: the lint rules are evaluated against the DSL, not the design JSON")
+	}
+	if pkg == "" {
+		return fmt.Errorf(`missing PACKAGE argument, use "--help" for usage`)
+	}
+	lcfg, err := lintConfig(cfg.rules)
+	if err != nil {
+		return err
+	}
+	b, err := codegen.Lint(pkg, cfg.debug)
+	if err != nil {
+		return err
+	}
+	var vs []*lint.Violation
+	if err := json.Unmarshal(b, &vs); err != nil {
+		return fmt.Errorf("failed to load lint results: %s", err.Error())
+	}
+	vs = lcfg.Apply(vs)
+	printViolations(os.Stdout, vs)
+	if lint.HasErrors(vs) {
+		return fmt.Errorf("lint failed")
+	}
+	return nil
+}
+
+// lintConfig builds the lint configuration from the values of the -rule
+// flag. Each value must be of the form NAME=SEVERITY.
+func lintConfig(rules []string) (lint.Config, error) {
+	c := make(lint.Config)
+	for _, r := range rules {
+		name, sev, ok := strings.Cut(r, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule %q, expected NAME=SEVERITY", r)
+		}
+		if err := c.Set(strings.TrimSpace(name), strings.TrimSpace(sev)); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// printViolations writes one line per violation followed by a summary. File
+// paths are made relative to the current directory when possible.
+func printViolations(w io.Writer, vs []*lint.Violation) {
+	cwd, _ := os.Getwd()
+	counts := make(map[lint.Severity]int)
+	for _, v := range vs {
+		if cwd != "" && v.File != "" {
+			if rel, err := filepath.Rel(cwd, v.File); err == nil && !strings.HasPrefix(rel, "..") {
+				v.File = rel
+			}
+		}
+		fmt.Fprintln(w, v.String())
+		counts[v.Severity]++
+	}
+	fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n",
+		counts[lint.SeverityError], counts[lint.SeverityWarning], counts[lint.SeverityInfo])
+}
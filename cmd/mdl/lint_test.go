@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"goa.design/model/lint"
+)
+
+func TestLintConfig(t *testing.T) {
+	c, err := lintConfig([]string{"orphan-element=off", " container-technology = error "})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if c["orphan-element"] != lint.SeverityOff || c["container-technology"] != lint.SeverityError {
+		t.Errorf("got config %v", c)
+	}
+	for _, invalid := range []string{"orphan-element", "unknown=error", "orphan-element=fatal"} {
+		if _, err := lintConfig([]string{invalid}); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+}
+
+func TestPrintViolations(t *testing.T) {
+	var buf bytes.Buffer
+	printViolations(&buf, []*lint.Violation{
+		{Rule: "container-technology", Severity: lint.SeverityError, Message: `container "S/C" has no technology`, File: "/tmp/model.go", Line: 12},
+		{Rule: "orphan-element", Severity: lint.SeverityWarning, Message: `person "User" is not part of any relationship`},
+	})
+	want := `/tmp/model.go:12: error: container "S/C" has no technology [container-technology]
+warning: person "User" is not part of any relationship [orphan-element]
+1 error(s), 1 warning(s), 0 info
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -1,24 +1,35 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	goacodegen "goa.design/goa/v3/codegen"
 
+	"goa.design/model/adr"
 	"goa.design/model/codegen"
+	"goa.design/model/diff"
+	"goa.design/model/layout"
 	"goa.design/model/mdl"
+	"goa.design/model/mermaid"
 	model "goa.design/model/pkg"
+	"goa.design/model/plantuml"
+	"goa.design/model/query"
+	"goa.design/model/stz"
 
 	cdnetwork "github.com/chromedp/cdproto/network"
 	cdruntime "github.com/chromedp/cdproto/runtime"
@@ -31,16 +42,37 @@ type (
 		help    bool
 		out     string
 		dir     string
+		adr     string
 		port    int
 		devmode bool
 		devdist string
+		design  string
 		// svg command options
 		views     SliceFlag
 		all       bool
 		direction string
 		compact   bool
+		renderer  string
 		timeout   time.Duration
-		force     bool
+		parallel  int
+		check     bool
+		rerender  bool
+		baseline  string
+		// quiet omits the progress output, set by the check mode.
+		quiet bool
+		// lint command options
+		rules SliceFlag
+		// impact command options
+		team string
+		// gen and diff command options
+		format string
+		// gen command options
+		layout bool
+		// export command options
+		scale   float64
+		combine string
+		// skill command options
+		force bool
 	}
 
 	// SliceFlag implements flag.Value for repeated string flags.
@@ -61,16 +93,26 @@ func main() {
 		os.Exit(0)
 	}
 
-	cmd, pkg := parseCommand()
+	cmd, pkg, arg := parseCommand()
 
 	var err error
 	switch cmd {
 	case "gen":
-		err = generateJSON(pkg, cfg)
+		err = generate(pkg, cfg)
 	case "serve":
 		err = startServer(pkg, cfg)
 	case "svg":
 		err = runSVG(pkg, cfg)
+	case "export":
+		err = runExport(pkg, cfg)
+	case "lint":
+		err = runLint(pkg, cfg)
+	case "impact":
+		err = runImpact(pkg, arg, cfg)
+	case "diff":
+		err = runDiff(pkg, arg, cfg)
+	case "import":
+		err = runImport(pkg, cfg)
 	case "skill":
 		if pkg != "install" {
 			err = fmt.Errorf(`unknown skill command %q, expected "install"`, pkg)
@@ -90,22 +132,34 @@ func main() {
 	}
 }
 
+// defaultOut is the default path of the file written by the gen command.
+const defaultOut = "design.json"
+
 func parseArgs() config {
 	cfg := config{
-		out:     "design.json",
+		out:     defaultOut,
 		dir:     goacodegen.Gendir,
 		port:    0,
 		devmode: os.Getenv("DEVMODE") == "1",
 		devdist: os.Getenv("DEVDIST"),
 		// defaults for svg command
 		timeout: 20 * time.Second,
+		// defaults for export command
+		scale: 1,
 	}
 
 	flag.BoolVar(&cfg.debug, "debug", false, "print debug output")
 	flag.BoolVar(&cfg.help, "help", false, "print this information")
 	flag.BoolVar(&cfg.help, "h", false, "print this information")
-	flag.StringVar(&cfg.out, "out", cfg.out, "set path to generated JSON representation")
-	flag.StringVar(&cfg.dir, "dir", cfg.dir, "set output directory used by editor to save SVG files")
+	flag.StringVar(&cfg.out, "out", cfg.out, "set path to generated JSON representation (design.go for import)")
+	flag.StringVar(&cfg.dir, "dir", cfg.dir, "set output directory used by editor to save SVG files and by gen to write one file per view")
+	flag.StringVar(
+		&cfg.design,
+		"design",
+		"",
+		"load the design from the given JSON file (generated by gen or Structurizr workspace) instead of PACKAGE [all but lint]",
+	)
+	flag.StringVar(&cfg.adr, "adr", "", "import architecture decision records from given directory [gen only]")
 	flag.IntVar(
 		&cfg.port,
 		"port",
@@ -122,8 +176,29 @@ func parseArgs() config {
 		"override the view auto-layout direction: DOWN|UP|LEFT|RIGHT",
 	)
 	flag.BoolVar(&cfg.compact, "compact", false, "enable compact auto-layout")
+	flag.StringVar(&cfg.renderer, "renderer", "browser", "set SVG renderer: browser|native [svg and export only]")
 	flag.DurationVar(&cfg.timeout, "timeout", cfg.timeout, "timeout per view (e.g. 15s)")
+	flag.BoolVar(&cfg.check, "check", false, "compare the rendered views with the SVG files in the output directory instead of writing them [svg only]")
+	flag.IntVar(&cfg.parallel, "parallel", 1, "number of browser tabs rendering views concurrently [svg and export only]")
+	flag.StringVar(
+		&cfg.baseline,
+		"baseline",
+		"",
+		"highlight changes made since the given design JSON file, PACKAGE or PACKAGE@REV [svg and export only]",
+	)
+	flag.BoolVar(&cfg.rerender, "rerender", false, "render the selected views even if they did not change since they were last rendered [svg and export only]")
 	flag.BoolVar(&cfg.force, "force", false, "replace a locally modified installed skill")
+	flag.StringVar(&cfg.team, "team", "team", "name of the element property that holds the owning team [impact only]")
+	flag.StringVar(
+		&cfg.format,
+		"format",
+		"",
+		"set output format: json|structurizr-dsl|plantuml|mermaid for gen (default json), text|markdown|json for diff (default text), svg|png|pdf for export (default svg)",
+	)
+	flag.BoolVar(&cfg.layout, "layout", false, "compute the position of the elements of views that have none [gen only]")
+	flag.Float64Var(&cfg.scale, "scale", cfg.scale, "set the scale of the exported PNG images [export only]")
+	flag.StringVar(&cfg.combine, "combine", "", "write all the exported views to the given multi-page PDF file [export only]")
+	flag.Var(&cfg.rules, "rule", "set lint rule severity as NAME=off|info|warning|error (repeatable) [lint only]")
 
 	// Parse only the flags, not the command and package
 	args := os.Args[1:]
@@ -137,26 +212,30 @@ func parseArgs() config {
 	return cfg
 }
 
-func parseCommand() (string, string) {
+// parseCommand returns the command, the package and the additional argument
+// accepted by the impact and diff commands.
+func parseCommand() (string, string, string) {
 	args := os.Args[1:]
-	var cmd, pkg string
+	var cmd, pkg, extra string
 
 	for i, arg := range args {
 		if strings.HasPrefix(arg, "-") {
 			break
 		}
-		switch i {
-		case 0:
+		switch {
+		case i == 0:
 			cmd = arg
-		case 1:
+		case i == 1:
 			pkg = arg
+		case i == 2 && (cmd == "impact" || cmd == "diff"):
+			extra = arg
 		default:
 			printUsage()
 			os.Exit(1)
 		}
 	}
 
-	return cmd, pkg
+	return cmd, pkg, extra
 }
 
 func findFlagStart(args []string) int {
@@ -168,22 +247,94 @@ func findFlagStart(args []string) int {
 	return len(args)
 }
 
-func generateJSON(pkg string, cfg config) error {
-	if pkg == "" {
-		return fmt.Errorf(`missing PACKAGE argument, use "--help" for usage`)
+// generate writes the representation of the design described in pkg in the
+// format given by cfg.format.
+func generate(pkg string, cfg config) error {
+	switch cfg.format {
+	case "", "json", "plantuml", "mermaid":
+	case "structurizr-dsl":
+		if cfg.out == defaultOut {
+			cfg.out = "workspace.dsl"
+		}
+	default:
+		return fmt.Errorf("invalid gen format %q: use json, structurizr-dsl, plantuml or mermaid", cfg.format)
 	}
 
-	b, err := codegen.JSON(pkg, cfg.debug)
+	b, err := designJSON(pkg, cfg)
 	if err != nil {
 		return err
 	}
 
+	if cfg.adr != "" || cfg.layout {
+		var design mdl.Design
+		if err := json.Unmarshal(b, &design); err != nil {
+			return fmt.Errorf("failed to load design: %s", err.Error())
+		}
+		if cfg.adr != "" {
+			if err := adr.Import(cfg.adr, &design); err != nil {
+				return err
+			}
+		}
+		if cfg.layout {
+			if err := layout.Views(&design); err != nil {
+				return err
+			}
+		}
+		if b, err = json.MarshalIndent(&design, "", "    "); err != nil {
+			return err
+		}
+	}
+
+	switch cfg.format {
+	case "structurizr-dsl":
+		var design mdl.Design
+		if err := json.Unmarshal(b, &design); err != nil {
+			return fmt.Errorf("failed to load design: %s", err.Error())
+		}
+		var buf bytes.Buffer
+		if err := stz.WriteDSL(&buf, &design); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	case "plantuml":
+		var design mdl.Design
+		if err := json.Unmarshal(b, &design); err != nil {
+			return fmt.Errorf("failed to load design: %s", err.Error())
+		}
+		return writeViews(&design, cfg.dir, ".puml", plantuml.WriteView)
+	case "mermaid":
+		var design mdl.Design
+		if err := json.Unmarshal(b, &design); err != nil {
+			return fmt.Errorf("failed to load design: %s", err.Error())
+		}
+		return writeViews(&design, cfg.dir, ".mmd", mermaid.WriteView)
+	}
+
 	return os.WriteFile(cfg.out, b, 0600)
 }
 
+// writeViews writes one file per view of the design in dir. The name of each
+// file is the view key followed by ext and its content is written by write.
+func writeViews(design *mdl.Design, dir, ext string, write func(io.Writer, *query.Graph, *query.View) error) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	g := query.New(design)
+	for _, v := range g.AllViews() {
+		var buf bytes.Buffer
+		if err := write(&buf, g, v); err != nil {
+			return fmt.Errorf("view %s: %w", v.Key, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, v.Key+ext), buf.Bytes(), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func startServer(pkg string, cfg config) error {
-	if pkg == "" {
-		return fmt.Errorf(`missing PACKAGE argument, use "--help" for usage`)
+	if pkg == "" && cfg.design == "" {
+		return errMissingPackage
 	}
 
 	absDir, err := filepath.Abs(cfg.dir)
@@ -202,12 +353,12 @@ func startServer(pkg string, cfg config) error {
 		cfg.port = 8080
 	}
 
-	return serve(absDir, pkg, cfg.port, cfg.devdist, cfg.debug)
+	return serve(absDir, pkg, cfg)
 }
 
-func serve(out, pkg string, port int, devdist string, debug bool) error {
+func serve(out, pkg string, cfg config) error {
 	// Load initial design
-	design, err := loadDesign(pkg, debug)
+	design, err := resolveDesign(pkg, cfg)
 	if err != nil {
 		return err
 	}
@@ -215,37 +366,65 @@ func serve(out, pkg string, port int, devdist string, debug bool) error {
 	server := NewServer(design)
 
 	// Watch for changes and update server
-	if err := watch(pkg, func() {
-		if newDesign, err := loadDesign(pkg, debug); err != nil {
-			fmt.Println("error parsing DSL:\n" + err.Error())
+	reload := func() {
+		if newDesign, err := resolveDesign(pkg, cfg); err != nil {
+			fmt.Println("error loading design:\n" + err.Error())
 		} else {
 			server.SetDesign(newDesign)
 		}
-	}); err != nil {
+	}
+	if cfg.design != "" {
+		err = watchFile(cfg.design, reload)
+	} else {
+		err = watch(pkg, reload)
+	}
+	if err != nil {
 		return err
 	}
 
-	return server.Serve(out, devdist, port)
+	return server.Serve(out, cfg.devdist, cfg.port)
 }
 
 // runSVG serves one fixed model, renders selected views in one browser process,
 // and saves only matched, validated browser results.
 func runSVG(pkg string, cfg config) error {
-	if pkg == "" {
-		return fmt.Errorf(`missing PACKAGE argument, use "--help" for usage`)
+	if cfg.check {
+		return runCheck(pkg, cfg)
+	}
+	_, _, err := renderSVG(pkg, cfg)
+	return err
+}
+
+// renderSVG renders the selected views of the design described in pkg as SVG
+// files in cfg.dir. It returns the design and the keys of the rendered views.
+func renderSVG(pkg string, cfg config) (*mdl.Design, []string, error) {
+	if pkg == "" && cfg.design == "" {
+		return nil, nil, errMissingPackage
+	}
+	switch cfg.renderer {
+	case "", "browser", "native":
+	default:
+		return nil, nil, fmt.Errorf("invalid renderer %q: use browser or native", cfg.renderer)
 	}
 
 	absDir, err := filepath.Abs(cfg.dir)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	if err := os.MkdirAll(absDir, 0700); err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	design, err := loadDesign(pkg, cfg.debug)
+	design, err := resolveDesign(pkg, cfg)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	if cfg.baseline != "" {
+		base, err := loadDiffDesign(cfg.baseline, cfg.debug)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baseline %s: %w", cfg.baseline, err)
+		}
+		diff.Highlight(base, design)
 	}
 
 	viewKeys := collectViewKeys(design)
@@ -261,15 +440,49 @@ func runSVG(pkg string, cfg config) error {
 		}
 		for _, v := range cfg.views {
 			if !m[v] {
-				return fmt.Errorf("unknown view %q; known views: %s", v, strings.Join(viewKeys, ", "))
+				return nil, nil, fmt.Errorf("unknown view %q; known views: %s", v, strings.Join(viewKeys, ", "))
 			}
 			selected = append(selected, v)
 		}
 	}
 	if len(selected) == 0 {
-		return fmt.Errorf("no views to render; use --all or --view")
+		return nil, nil, fmt.Errorf("no views to render; use --all or --view")
 	}
 
+	// Only render the views whose content, styles, layout or render options
+	// changed since they were last rendered.
+	cache := newRenderCache(design, cfg)
+	stale := selected
+	if !cfg.rerender {
+		if stale, err = cache.stale(selected, absDir); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(stale) > 0 {
+		if cfg.renderer == "native" {
+			err = renderViewsNative(design, stale, absDir, cache, cfg)
+		} else {
+			err = renderViewsBrowser(design, stale, absDir, cache, cfg)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	switch {
+	case cfg.quiet:
+	case len(stale) == len(selected):
+		fmt.Printf("Rendered %d views\n", len(stale))
+	case len(stale) == 0:
+		fmt.Printf("All %d views are up to date\n", len(selected))
+	default:
+		fmt.Printf("Rendered %d of %d views, the others are up to date\n", len(stale), len(selected))
+	}
+	return design, selected, nil
+}
+
+// renderViewsBrowser renders the given views of design with the editor
+// running in a headless browser and saves them in absDir.
+func renderViewsBrowser(design *mdl.Design, views []string, absDir string, cache *renderCache, cfg config) error {
 	server := NewServer(design)
 	digest, err := designDigest(design)
 	if err != nil {
@@ -294,7 +507,7 @@ func runSVG(pkg string, cfg config) error {
 	}()
 
 	baseURL := "http://" + listener.Addr().String()
-	if err := renderViewsHeadless(baseURL, digest, selected, cfg, broker, server); err != nil {
+	if err := renderViewsHeadless(baseURL, digest, views, cfg, broker, server, cache); err != nil {
 		if closeErr := httpServer.Close(); closeErr != nil {
 			return fmt.Errorf("%w; close headless server: %v", err, closeErr)
 		}
@@ -357,6 +570,9 @@ func collectViewKeys(d *mdl.Design) []string {
 	for _, v := range d.Views.ComponentViews {
 		add([]*mdl.ViewProps{v.ViewProps})
 	}
+	for _, v := range d.Views.CodeViews {
+		add([]*mdl.ViewProps{v.ViewProps})
+	}
 	for _, v := range d.Views.DynamicViews {
 		add([]*mdl.ViewProps{v.ViewProps})
 	}
@@ -373,6 +589,7 @@ func collectViewKeys(d *mdl.Design) []string {
 }
 
 // renderViewsHeadless reuses one browser process and waits for typed HTTP results.
+// The views are dispatched across cfg.parallel tabs of the browser.
 func renderViewsHeadless(
 	baseURL string,
 	modelDigest string,
@@ -380,14 +597,15 @@ func renderViewsHeadless(
 	cfg config,
 	broker *renderBroker,
 	server *Server,
+	cache *renderCache,
 ) error {
 	direction, err := normalizeLayoutDirection(cfg.direction)
 	if err != nil {
 		return err
 	}
-	return withChromedp(cfg.timeout, cfg.debug, func(exec navigateExec) error {
-		for _, viewID := range views {
-			if err := renderViewHeadless(
+	return withChromedpTabs(cfg.timeout, cfg.debug, max(cfg.parallel, 1), func(execs []navigateExec) error {
+		return dispatchViews(views, execs, func(viewID string, exec navigateExec) error {
+			return renderViewHeadless(
 				baseURL,
 				modelDigest,
 				viewID,
@@ -395,13 +613,52 @@ func renderViewsHeadless(
 				cfg,
 				broker,
 				server,
+				cache,
 				exec,
-			); err != nil {
-				return fmt.Errorf("render %s: %w", viewID, err)
+			)
+		})
+	})
+}
+
+// dispatchViews renders the views concurrently with one worker per exec. No
+// view is dispatched once one fails, the views being rendered complete and the
+// returned error reports each failed view.
+func dispatchViews(views []string, execs []navigateExec, render func(viewID string, exec navigateExec) error) error {
+	var (
+		errs   = make([]error, len(views))
+		next   = make(chan int)
+		failed = make(chan struct{})
+		once   sync.Once
+		wg     sync.WaitGroup
+	)
+	for _, exec := range execs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				select {
+				case <-failed:
+					continue
+				default:
+				}
+				if err := render(views[i], exec); err != nil {
+					errs[i] = fmt.Errorf("render %s: %w", views[i], err)
+					once.Do(func() { close(failed) })
+				}
 			}
+		}()
+	}
+dispatch:
+	for i := range views {
+		select {
+		case next <- i:
+		case <-failed:
+			break dispatch
 		}
-		return nil
-	})
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
 // renderViewHeadless waits for the exact view and model result before saving it.
@@ -413,6 +670,7 @@ func renderViewHeadless(
 	cfg config,
 	broker *renderBroker,
 	server *Server,
+	cache *renderCache,
 	exec navigateExec,
 ) error {
 	results, unregister, err := broker.register(viewID, modelDigest)
@@ -443,11 +701,17 @@ func renderViewHeadless(
 		return fmt.Errorf("browser failed: %s", result.Error)
 	}
 
-	err = server.storeSVG(viewID, strings.NewReader(result.SVG))
+	svg, err := cache.stamp(viewID, []byte(result.SVG))
+	if err != nil {
+		return fmt.Errorf("record SVG digest: %w", err)
+	}
+	err = server.storeSVG(viewID, bytes.NewReader(svg))
 	if err != nil {
 		return fmt.Errorf("save SVG: %w", err)
 	}
-	fmt.Println("Saved:", filepath.Join(server.outDir, viewID+".svg"))
+	if !cfg.quiet {
+		fmt.Println("Saved:", filepath.Join(server.outDir, viewID+".svg"))
+	}
 	return nil
 }
 
@@ -485,16 +749,25 @@ func normalizeLayoutDirection(direction string) (string, error) {
 	}
 }
 
-// navigateExec opens one isolated page in the shared browser process.
-type navigateExec func(url string, timeout time.Duration) error
+// navigateExec opens one isolated page in the shared browser process and runs
+// the given actions once the page is loaded.
+type navigateExec func(url string, timeout time.Duration, actions ...chromedp.Action) error
 
 // withChromedp wraps the chromedp session lifecycle
 func withChromedp(timeout time.Duration, debug bool, fn func(exec navigateExec) error) error {
-	return chromedpExec(timeout, debug, fn)
+	return chromedpExec(timeout, debug, 1, func(execs []navigateExec) error {
+		return fn(execs[0])
+	})
+}
+
+// withChromedpTabs wraps the chromedp session lifecycle of a browser process
+// with the given number of tabs.
+func withChromedpTabs(timeout time.Duration, debug bool, tabs int, fn func(execs []navigateExec) error) error {
+	return chromedpExec(timeout, debug, tabs, fn)
 }
 
 // chromedpExec encapsulates direct chromedp usage.
-func chromedpExec(timeout time.Duration, debug bool, fn func(exec navigateExec) error) error {
+func chromedpExec(timeout time.Duration, debug bool, tabs int, fn func(execs []navigateExec) error) error {
 	// Use an explicit exec allocator with flags suitable for CI environments
 	allocatorOpts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.WSURLReadTimeout(timeout),
@@ -513,14 +786,33 @@ func chromedpExec(timeout time.Duration, debug bool, fn func(exec navigateExec)
 
 	ctx, cancel := chromedp.NewContext(allocCtx)
 	defer cancel()
-	tabCtx, tabCancel := chromedp.NewContext(ctx)
-	defer tabCancel()
+	// Start the browser first so that all the tabs share its process.
+	if err := chromedp.Run(ctx); err != nil {
+		return fmt.Errorf("start headless browser: %w", err)
+	}
+	execs := make([]navigateExec, tabs)
+	for i := range execs {
+		tabCtx, tabCancel := chromedp.NewContext(ctx)
+		defer tabCancel()
+		exec, err := tabExec(tabCtx, debug)
+		if err != nil {
+			return err
+		}
+		execs[i] = exec
+	}
+
+	return fn(execs)
+}
+
+// tabExec starts the browser tab of tabCtx and returns the function that
+// navigates it.
+func tabExec(tabCtx context.Context, debug bool) (navigateExec, error) {
 	if err := chromedp.Run(tabCtx); err != nil {
-		return fmt.Errorf("start headless browser tab: %w", err)
+		return nil, fmt.Errorf("start headless browser tab: %w", err)
 	}
 	if debug {
 		if err := chromedp.Run(tabCtx, cdnetwork.Enable()); err != nil {
-			return fmt.Errorf("enable browser network diagnostics: %w", err)
+			return nil, fmt.Errorf("enable browser network diagnostics: %w", err)
 		}
 		chromedp.ListenTarget(tabCtx, func(event any) {
 			switch typed := event.(type) {
@@ -554,7 +846,7 @@ func chromedpExec(timeout time.Duration, debug bool, fn func(exec navigateExec)
 		})
 	}
 
-	exec := func(url string, timeout time.Duration) error {
+	exec := func(url string, timeout time.Duration, actions ...chromedp.Action) error {
 		if timeout <= 0 {
 			return fmt.Errorf("browser navigation deadline exceeded")
 		}
@@ -563,10 +855,44 @@ func chromedpExec(timeout time.Duration, debug bool, fn func(exec navigateExec)
 		if err := chromedp.Run(navCtx, chromedp.Navigate(url)); err != nil {
 			return fmt.Errorf("navigate browser: %w", err)
 		}
+		if err := chromedp.Run(navCtx, actions...); err != nil {
+			return fmt.Errorf("run browser actions: %w", err)
+		}
 		return nil
 	}
+	return exec, nil
+}
 
-	return fn(exec)
+// errMissingPackage is returned by the commands that need a design when
+// neither PACKAGE nor -design is given.
+var errMissingPackage = fmt.Errorf(`missing PACKAGE argument or -design flag, use "--help" for usage`)
+
+// resolveDesign returns the design stored in the file given by -design if any,
+// the design described in pkg otherwise.
+func resolveDesign(pkg string, cfg config) (*mdl.Design, error) {
+	if cfg.design != "" {
+		return stz.LoadDesign(cfg.design)
+	}
+	if pkg == "" {
+		return nil, errMissingPackage
+	}
+	return loadDesign(pkg, cfg.debug)
+}
+
+// designJSON returns the JSON representation of the design stored in the file
+// given by -design if any, of the design described in pkg otherwise.
+func designJSON(pkg string, cfg config) ([]byte, error) {
+	if cfg.design == "" {
+		if pkg == "" {
+			return nil, errMissingPackage
+		}
+		return codegen.JSON(pkg, cfg.debug)
+	}
+	design, err := stz.LoadDesign(cfg.design)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(design, "", "    ")
 }
 
 func loadDesign(pkg string, debug bool) (*mdl.Design, error) {
@@ -588,13 +914,34 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "  %s serve PACKAGE [FLAGS]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "    Start a HTTP server that serves a graphical editor for the design described in PACKAGE.\n")
 	fmt.Fprintf(os.Stderr, "  %s gen PACKAGE [FLAGS]\n", os.Args[0])
-	fmt.Fprintf(os.Stderr, "    Generate a JSON representation of the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "    Generate a JSON (or Structurizr DSL with \"-format structurizr-dsl\") representation of the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "    \"-format plantuml\" writes one C4-PlantUML diagram per view in the directory given by \"-dir\".\n")
+	fmt.Fprintf(os.Stderr, "    \"-format mermaid\" writes one Mermaid diagram per view in the directory given by \"-dir\".\n")
+	fmt.Fprintf(os.Stderr, "    \"-layout\" sets the position of the elements of views that have none.\n")
 	fmt.Fprintf(os.Stderr, "  %s svg PACKAGE [FLAGS]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "    Auto-layout and export SVG diagram(s) for the design described in PACKAGE.\n")
+	fmt.Fprintf(os.Stderr, "    Views that did not change since they were last rendered are skipped unless \"-rerender\" is set.\n")
+	fmt.Fprintf(os.Stderr, "    \"-check\" exits with a non-zero status if the SVG files in \"-dir\" do not match the rendered views.\n")
+	fmt.Fprintf(os.Stderr, "    \"-parallel N\" renders N views at a time in the headless browser.\n")
+	fmt.Fprintf(os.Stderr, "    \"-renderer native\" lays out and draws the diagrams in Go instead of a headless browser.\n")
+	fmt.Fprintf(os.Stderr, "  %s export PACKAGE [FLAGS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Export diagram(s) for the design described in PACKAGE as SVG, PNG (\"-format png\") or PDF (\"-format pdf\").\n")
+	fmt.Fprintf(os.Stderr, "    PDF pages are sized according to the paper size of the views, \"-combine FILE\" writes a single document.\n")
+	fmt.Fprintf(os.Stderr, "  %s lint PACKAGE [FLAGS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Check the design described in PACKAGE against the lint rules, exit with a non-zero status on errors.\n")
+	fmt.Fprintf(os.Stderr, "  %s impact PACKAGE PATH [FLAGS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    List the elements, deployment instances, views and teams affected by a change to the element at PATH (e.g. \"System/Container\").\n")
+	fmt.Fprintf(os.Stderr, "  %s diff OLD NEW [FLAGS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Report the elements, relationships, deployment nodes, views and styles added, removed or modified between two designs.\n")
+	fmt.Fprintf(os.Stderr, "    OLD and NEW are design JSON files (generated by gen or Structurizr workspaces), packages or packages at a git revision (e.g. \"PACKAGE@main\").\n")
+	fmt.Fprintf(os.Stderr, "  %s import FILE [FLAGS]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "    Generate Go source code that describes the Structurizr workspace stored in FILE (JSON or workspace.dsl) with the Model DSL.\n")
 	fmt.Fprintf(os.Stderr, "  %s skill install [-force]\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "    Install the MDL diagram-editing skill for detected coding agents.\n")
 	fmt.Fprintf(os.Stderr, "\nPACKAGE must be the import path to a Go package containing Model DSL.\n")
-	fmt.Fprintf(os.Stderr, "PACKAGE is required by serve, gen, and svg.\n\n")
+	fmt.Fprintf(os.Stderr, "PACKAGE is required by serve, gen, svg, export, lint, and impact.\n")
+	fmt.Fprintf(os.Stderr, "All these commands but lint also accept \"-design FILE\" instead of PACKAGE where FILE is a JSON file\n")
+	fmt.Fprintf(os.Stderr, "generated by gen or a Structurizr workspace, e.g. \"%s svg -design design.json\".\n\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "FLAGS:\n")
 	flag.PrintDefaults()
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -122,3 +124,47 @@ func TestChromedpExecNavigatesDirectPage(t *testing.T) {
 		t.Fatalf("navigate direct page: %v", err)
 	}
 }
+
+// TestDesignFlag renders and queries a design loaded from a JSON file
+// generated by the gen command instead of compiling the package.
+func TestDesignFlag(t *testing.T) {
+	dir := t.TempDir()
+	designFile := filepath.Join(dir, "design.json")
+	if err := generate("goa.design/model/examples/basic/model", config{out: designFile}); err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	outDir := filepath.Join(dir, "gen")
+	cfg := config{design: designFile, dir: outDir, renderer: "native", all: true}
+	if err := runSVG("", cfg); err != nil {
+		t.Fatalf("runSVG: %v", err)
+	}
+	for _, key := range []string{"SystemContext", "Container View"} {
+		if _, err := os.Stat(filepath.Join(outDir, key+".svg")); err != nil {
+			t.Errorf("missing generated svg: %v", err)
+		}
+	}
+
+	if err := runImpact("Software System/Application", "", config{design: designFile}); err != nil {
+		t.Errorf("runImpact: %v", err)
+	}
+
+	out := filepath.Join(dir, "copy.json")
+	if err := generate("", config{design: designFile, out: out}); err != nil {
+		t.Fatalf("generate from design: %v", err)
+	}
+	d, err := resolveDesign("", config{design: out})
+	if err != nil {
+		t.Fatalf("load generated design: %v", err)
+	}
+	if len(collectViewKeys(d)) != 2 {
+		t.Errorf("got views %v, want SystemContext and Container View", collectViewKeys(d))
+	}
+
+	if err := runLint("", config{design: designFile}); err == nil {
+		t.Error("expected lint to require a package")
+	}
+	if _, _, err := renderSVG("", config{}); err != errMissingPackage {
+		t.Errorf("got error %v, want %v", err, errMissingPackage)
+	}
+}
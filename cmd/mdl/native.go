@@ -0,0 +1,72 @@
+// This file renders views without a browser. The native renderer reads the
+// layouts saved in the output directory like the editor does and writes the
+// same layout-bearing SVG files.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+	"goa.design/model/svg"
+)
+
+// renderViewsNative renders the selected views of design with package svg and
+// saves them in outDir.
+func renderViewsNative(design *mdl.Design, selected []string, outDir string, cache *renderCache, cfg config) error {
+	direction, err := normalizeLayoutDirection(cfg.direction)
+	if err != nil {
+		return err
+	}
+	server := &Server{outDir: outDir}
+	layouts, err := server.savedLayouts()
+	if err != nil {
+		return fmt.Errorf("load layouts: %w", err)
+	}
+	g := query.New(design)
+	for _, key := range selected {
+		v := g.View(key)
+		if v == nil {
+			return fmt.Errorf("view %s: not found", key)
+		}
+		opts := svg.Options{
+			Direction: rankDirection(direction),
+			Compact:   cfg.compact,
+			Layout:    layouts[key],
+		}
+		var buf bytes.Buffer
+		if err := svg.Render(&buf, g, v, opts); err != nil {
+			return err
+		}
+		b, err := cache.stamp(key, buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("view %s: record SVG digest: %w", key, err)
+		}
+		if err := server.storeSVG(key, bytes.NewReader(b)); err != nil {
+			return fmt.Errorf("view %s: save SVG: %w", key, err)
+		}
+		if !cfg.quiet {
+			fmt.Println("Saved:", filepath.Join(outDir, key+".svg"))
+		}
+	}
+	return nil
+}
+
+// rankDirection returns the rank direction corresponding to a normalized
+// "-direction" flag value.
+func rankDirection(direction string) mdl.RankDirectionKind {
+	switch direction {
+	case "DOWN":
+		return mdl.RankTopBottom
+	case "UP":
+		return mdl.RankBottomTop
+	case "RIGHT":
+		return mdl.RankLeftRight
+	case "LEFT":
+		return mdl.RankRightLeft
+	default:
+		return mdl.RankUndefined
+	}
+}
@@ -233,6 +233,16 @@ func (s *Server) handleError(w http.ResponseWriter, err error) {
 
 // loadLayouts reads layout information from SVG files and fallback layout.json
 func (s *Server) loadLayouts() ([]byte, error) {
+	layouts, err := s.savedLayouts()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(layouts)
+}
+
+// savedLayouts returns the layouts saved in the output directory keyed by
+// view key.
+func (s *Server) savedLayouts() (map[string]Layout, error) {
 	layouts := make(map[string]Layout)
 
 	// Load fallback layout.json for backwards compatibility
@@ -245,7 +255,7 @@ func (s *Server) loadLayouts() ([]byte, error) {
 		return nil, err
 	}
 
-	return json.Marshal(layouts)
+	return layouts, nil
 }
 
 // loadLayoutJSON loads the fallback layout.json file
@@ -285,44 +295,62 @@ func (s *Server) loadLayoutsFromSVGs(layouts map[string]Layout) error {
 
 // loadLayoutFromSVG extracts layout information from a single SVG file
 func (s *Server) loadLayoutFromSVG(filename string, layouts map[string]Layout) error {
-	const (
-		beginMark = "<script type=\"application/json\"><![CDATA["
-		endMark   = "]]></script>"
-	)
-
 	b, err := os.ReadFile(path.Join(s.outDir, filename))
 	if err != nil {
 		return err
 	}
 
+	layout, err := svgLayout(b)
+	if err != nil {
+		return fmt.Errorf("%w in %s", err, filename)
+	}
+	if layout != nil {
+		id := strings.TrimSuffix(filename, ".svg")
+		layouts[id] = layout
+	}
+
+	return nil
+}
+
+// svgLayout returns the layout embedded in the SVG document b, nil if there is
+// none.
+func svgLayout(b []byte) (Layout, error) {
+	data, err := svgMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+	layout, _ := data["layout"].(map[string]any)
+	return layout, nil
+}
+
+// svgMetadata returns the metadata embedded in the SVG document b, nil if
+// there is none.
+func svgMetadata(b []byte) (map[string]any, error) {
+	const (
+		beginMark = "<script type=\"application/json\"><![CDATA["
+		endMark   = "]]></script>"
+	)
+
 	// Find the JSON script block
 	beginBytes := []byte(beginMark)
 	endBytes := []byte(endMark)
 
 	begin := bytes.Index(b, beginBytes)
 	if begin == -1 {
-		return nil // No layout data in this SVG
+		return nil, nil // No layout data in this SVG
 	}
 	begin += len(beginBytes)
 
 	end := bytes.Index(b, endBytes)
 	if end == -1 {
-		return fmt.Errorf("malformed SVG: missing end marker in %s", filename)
+		return nil, fmt.Errorf("malformed SVG: missing end marker")
 	}
 
-	layoutData := b[begin:end]
-
 	var data map[string]any
-	if err := json.Unmarshal(layoutData, &data); err != nil {
-		return fmt.Errorf("invalid JSON in SVG %s: %w", filename, err)
+	if err := json.Unmarshal(b[begin:end], &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON in SVG: %w", err)
 	}
-
-	if layout, ok := data["layout"].(map[string]any); ok {
-		id := strings.TrimSuffix(filename, ".svg")
-		layouts[id] = layout
-	}
-
-	return nil
+	return data, nil
 }
 
 // fileExists checks if a file exists and is not a directory
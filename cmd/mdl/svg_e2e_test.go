@@ -122,6 +122,46 @@ func TestSVGEndToEnd(t *testing.T) {
 	}
 }
 
+// TestSVGNative renders the basic example without a browser and checks that
+// the editor reads the layout back from the generated files.
+func TestSVGNative(t *testing.T) {
+	outDir := t.TempDir()
+	cfg := config{dir: outDir, renderer: "native", all: true}
+	if err := runSVG("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("runSVG failed: %v", err)
+	}
+	p := filepath.Join(outDir, "SystemContext.svg")
+	links := svgLinks(t, p)
+	if len(links) != 1 || links[0] != "Container%20View.svg" {
+		t.Fatalf("expected one container-view link, got %v", links)
+	}
+	layouts, err := (&Server{outDir: outDir}).savedLayouts()
+	if err != nil {
+		t.Fatalf("load layouts: %v", err)
+	}
+	for _, key := range []string{"SystemContext", "Container View"} {
+		if len(layouts[key]) == 0 {
+			t.Errorf("missing layout of view %q in %v", key, layouts)
+		}
+	}
+
+	// Rendering again skips the views that did not change.
+	before, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runSVG("goa.design/model/examples/basic/model", cfg); err != nil {
+		t.Fatalf("runSVG failed: %v", err)
+	}
+	after, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.ModTime().Equal(before.ModTime()) || !os.SameFile(before, after) {
+		t.Errorf("up to date view %s was rendered again", p)
+	}
+}
+
 // TestManualEditAfterAutoLayout proves editor changes enter the complete manual
 // contract instead of mixing changed nodes with stale automatic geometry.
 func TestManualEditAfterAutoLayout(t *testing.T) {
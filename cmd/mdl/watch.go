@@ -39,6 +39,37 @@ func watch(pkg string, reload func()) error {
 		}
 	}
 
+	return serveReload(watcher, func(name string) bool {
+		return !strings.HasPrefix(filepath.Base(name), codegen.TmpDirPrefix)
+	}, reload)
+}
+
+// watchFile listens to changes of the design file at path, reloads the model
+// and refreshes the editor page when it changes.
+func watchFile(path string, reload func()) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// Watch the directory as editors and build tools often replace files.
+	if err := watcher.Add(filepath.Dir(abs)); err != nil {
+		return err
+	}
+	fmt.Println("Watching:", abs)
+
+	return serveReload(watcher, func(name string) bool {
+		return filepath.Clean(name) == abs
+	}, reload)
+}
+
+// serveReload starts the live reload server and calls reload each time
+// watcher reports changes to files whose name matches.
+func serveReload(watcher *fsnotify.Watcher, match func(name string) bool, reload func()) error {
+
 	// Create live reload server and hookup to watcher
 	lr := lrserver.New(lrserver.DefaultName, lrserver.DefaultPort)
 	lr.SetStatusLog(nil)
@@ -52,7 +83,7 @@ func watch(pkg string, reload func()) error {
 		for {
 			select {
 			case ev := <-watcher.Events:
-				if strings.HasPrefix(filepath.Base(ev.Name), codegen.TmpDirPrefix) {
+				if !match(ev.Name) {
 					continue
 				}
 
@@ -42,11 +42,13 @@ interface Element {
 	technology?: string;
 	description?: string;
 	url?: string;
+	group?: string;
 	parent?: Element;
 	tags?: string;
 	location?: string;
 	containers?: Element[];
 	components?: Element[];
+	codeElements?: Element[];
 	relationships?: Relation[];
 	properties?: { [key: string]: string }
 	children?: Element[];
@@ -148,6 +150,13 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 						el2.parent = el1;
 						elements.set(el2.id, el2)
 						collectRels(el2)
+						if (Array.isArray(el2.codeElements)) {
+							el2.codeElements.forEach((el3: Element) => {
+								el3.parent = el2;
+								elements.set(el3.id, el3)
+								collectRels(el3)
+							})
+						}
 					})
 				}
 			})
@@ -164,12 +173,21 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 				collectRels(item)
 			})
 		}
+		const softwareSystemInstances = (el: any) => {
+			el.softwareSystemInstances && el.softwareSystemInstances.forEach((item: any) => {
+				const el1 = {...elements.get(item.softwareSystemId), id: item.id}
+				elements.set(el1.id, el1)
+				el1.parent = el
+				collectRels(item)
+			})
+		}
 
 		const recAddNodes = (el: Element, parent: Element) => {
 			el.parent = parent;
 			elements.set(el.id, el)
 			collectRels(el)
 			containerInstances(el)
+			softwareSystemInstances(el)
 			el.children && el.children.forEach((el1: Element) => recAddNodes(el1, el))
 			el.infrastructureNodes && el.infrastructureNodes.forEach((el1: Element) => recAddNodes(el1, el))
 		}
@@ -192,7 +210,7 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 
 	//grouping rules - elements that are groups will not be nodes
 	const groupingIDs: { [key: string]: boolean } = {}
-	if (section == 'deploymentViews' || section == 'containerViews') {
+	if (section == 'deploymentViews' || section == 'containerViews' || section == 'codeViews') {
 		view.elements.forEach(ref => {
 			const el = elements.get(ref.id)
 			if (el?.parent) {
@@ -212,6 +230,25 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 		groupingIDs[p.id] = true
 	}
 
+	// named groups - create a virtual element per group and parent so that the
+	// group is rendered as a boundary nested in the parent boundary if any
+	const virtualGroups = new Map<string, Element>()
+	const groupParents = new Map<string, Element>()
+	view.elements.forEach(ref => {
+		const el = elements.get(ref.id)
+		if (!el?.group) return
+		const id = `__group__:${el.parent?.id ?? ''}:${el.group}`
+		let g = virtualGroups.get(id)
+		if (!g) {
+			g = {id, name: el.group, tags: 'Group', parent: el.parent}
+			virtualGroups.set(id, g)
+			groupingIDs[id] = true
+		}
+		groupParents.set(el.id, g)
+	})
+	const lookup = (id: string) => virtualGroups.get(id) || elements.get(id)
+	const parentOf = (el: Element) => groupParents.get(el.id) || el.parent
+
 	const styles = model.views.styles
 
 	// Build color-to-variable mapping for CSS custom properties theming
@@ -249,7 +286,8 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 		let style = {}
 		if (el) {
 			const tags = el.tags.split(',')
-			sub = tags[tags.length - 1] // subtitle is [<last tag>]
+			// subtitle is [<last tag>], ignoring the tags added by mdl to highlight changes
+			sub = tags.filter(tag => !tag.trim().startsWith('Diff ')).pop() || 
 			if (el.technology)
 				sub += ': ' + el.technology // or [<technology>: <last tag>]
 
@@ -316,16 +354,16 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 	//sort by depth to solve dependency
 	const level = (el: Element) => {
 		let i = 0
-		for (let p = el.parent; p; p = p.parent) i++;
+		for (let p = parentOf(el); p; p = p.parent) i++;
 		return i
 	}
 	const gElements = Object.keys(groupingIDs)
-		.map(id => elements.get(id))
+		.map(id => lookup(id))
 		.sort((a, b) => level(a) > level(b) ? -1 : 1)
 
 	gElements.forEach(parent => {
 		let style = {}
-		if (section == 'deploymentViews') {
+		if (section == 'deploymentViews' && !virtualGroups.has(parent.id)) {
 			const el = elements.get(parent.id)
 			const tags = el.tags.split(',')
 			tags.forEach(tag => {
@@ -337,8 +375,9 @@ export const parseView = (model: Model, layouts: Layouts, viewKey: string) => {
 		// Filter group members more carefully to respect boundaries
 		const groupMembers = view.elements
 			.map(ref => elements.get(ref.id))
+			.concat(Array.from(virtualGroups.values()))
 			.filter(el => {
-				if (!el || el.parent !== parent) return false;
+				if (!el || parentOf(el) !== parent) return false;
 				
 				// For system landscape views, respect the location-based grouping
 				if (section === 'systemLandscapeViews' && parent.id === '__enterprise__') {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"goa.design/goa/v3/codegen"
+	"goa.design/model/layout"
 	model "goa.design/model/pkg"
 	"goa.design/model/stz"
 	"golang.org/x/tools/go/packages"
@@ -22,6 +23,7 @@ func main() {
 		wid    = fs.String("id", "", "Structurizr workspace ID [only needed for 'stz' command]")
 		key    = fs.String("key", "", "Structurizr API key [only needed for 'stz' command]")
 		secret = fs.String("secret", "", "Structurizr API secret [only needed for 'stz' command]")
+		adrDir = fs.String("adr", "", "Import architecture decision records from given directory [use with 'gen'].")
 		debug  = fs.Bool("debug", false, "Print debug information to stderr.")
 	)
 
@@ -64,7 +66,7 @@ done:
 			err = fmt.Errorf("missing Go import package path")
 			break
 		}
-		err = gen(path, *out, *debug)
+		err = gen(path, *out, *adrDir, *debug)
 	case "get":
 		err = get(pathOrDefault(*out), *wid, *key, *secret, *debug)
 	case "put":
@@ -82,7 +84,7 @@ done:
 	}
 }
 
-func gen(pkg, out string, debug bool) error {
+func gen(pkg, out, adrDir string, debug bool) error {
 	// Validate package import path
 	if _, err := packages.Load(&packages.Config{Mode: packages.NeedName}, pkg); err != nil {
 		return err
@@ -140,7 +142,31 @@ func gen(pkg, out string, debug bool) error {
 	if debug {
 		fmt.Fprintln(os.Stderr, o)
 	}
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Lay out the views and import architecture decision records
+	b, err := os.ReadFile(out)
+	if err != nil {
+		return err
+	}
+	w := &stz.Workspace{}
+	if err := json.Unmarshal(b, w); err != nil {
+		return err
+	}
+	if err := layout.Views(stz.DesignFromWorkspace(w)); err != nil {
+		return err
+	}
+	if adrDir != "" {
+		if err := w.ImportDecisions(adrDir); err != nil {
+			return err
+		}
+	}
+	if b, err = json.MarshalIndent(w, "", "    "); err != nil {
+		return err
+	}
+	return os.WriteFile(out, b, 0644)
 }
 
 func get(out, wid, key, secret string, debug bool) error {
@@ -188,11 +214,11 @@ func put(path, wid, key, secret string, debug bool) error {
 				fmt.Fprintf(os.Stderr, "failed to close layout file: %v\n", err)
 			}
 		}()
-		layout := make(stz.WorkspaceLayout)
-		if err := json.NewDecoder(llf).Decode(&layout); err != nil {
+		wl := make(stz.WorkspaceLayout)
+		if err := json.NewDecoder(llf).Decode(&wl); err != nil {
 			return err
 		}
-		local.ApplyLayout(layout)
+		local.ApplyLayout(wl)
 	}
 
 	// Get remote workspace
@@ -205,8 +231,12 @@ func put(path, wid, key, secret string, debug bool) error {
 		return err
 	}
 
-	// Merge layouts and persist result
+	// Merge layouts, lay out the views that have no layout yet and persist
+	// result
 	local.MergeLayout(remote)
+	if err := layout.Views(stz.DesignFromWorkspace(local)); err != nil {
+		return err
+	}
 	b, err := json.MarshalIndent(local.Layout(), "", "   ")
 	if err != nil {
 		return err
@@ -0,0 +1,69 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// cacheDir returns the directory where the generator programs are built and
+// kept between runs. It is a variable so that tests can override it.
+var cacheDir = func() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "goa-model"), nil
+}
+
+// programDir returns the directory where the program that imports pkg with
+// the given main source is built and kept between runs. The directory depends
+// on the module that contains dir as the module determines
+// the version of the packages the program is built with. programDir returns
+// an empty string if the program cannot be kept, this is the case for
+// modules stored in the temporary directory (e.g. the git worktrees created
+// by "mdl diff") as their programs could never be reused.
+func programDir(gobin, dir, pkg, mainSrc string) string {
+	root, err := cacheDir()
+	if err != nil {
+		return ""
+	}
+	gomod, err := runCmd(gobin, dir, nil, "env", "GOMOD")
+	if err != nil {
+		return ""
+	}
+	gomod = strings.TrimSpace(gomod)
+	if gomod == "" || gomod == os.DevNull || isTemp(gomod) {
+		return ""
+	}
+	h := sha256.New()
+	for _, s := range []string{gomod, pkg, mainSrc} {
+		fmt.Fprintf(h, "%d:%s;", len(s), s)
+	}
+	return filepath.Join(root, hex.EncodeToString(h.Sum(nil))[:32])
+}
+
+// isTemp returns true if path is in the temporary directory.
+func isTemp(path string) bool {
+	tmp, err := filepath.EvalSymlinks(os.TempDir())
+	if err != nil {
+		tmp = os.TempDir()
+	}
+	if p, err := filepath.EvalSymlinks(path); err == nil {
+		path = p
+	}
+	rel, err := filepath.Rel(tmp, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// exeSuffix returns the suffix of executable files on the current platform.
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJSONProgramReuse(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generator")
+	}
+	cache := t.TempDir()
+	orig := cacheDir
+	cacheDir = func() (string, error) { return cache, nil }
+	defer func() { cacheDir = orig }()
+
+	dir, err := os.MkdirTemp(".", "cachetest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+	write := func(system string) {
+		t.Helper()
+		src := "package model\n\nimport . \"goa.design/model/dsl\"\n\nvar _ = Design(\"Test\", func() {\n\tSoftwareSystem(\"" + system + "\")\n})\n"
+		if err := os.WriteFile(filepath.Join(dir, "model.go"), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkg := "goa.design/model/codegen/" + filepath.Base(dir)
+	programs := func() []string {
+		t.Helper()
+		bins, err := filepath.Glob(filepath.Join(cache, "*", "mdl"+exeSuffix()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return bins
+	}
+
+	write("Shop")
+	first, err := JSON(pkg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bins := programs()
+	if len(bins) != 1 {
+		t.Fatalf("got programs %v, want 1", bins)
+	}
+	info, err := os.Stat(bins[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := JSON(pkg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("got different output from kept program:\n%s\nwant:\n%s", second, first)
+	}
+	after, err := os.Stat(bins[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(info, after) {
+		t.Error("program was linked again without changes")
+	}
+
+	write("Store")
+	third, err := JSON(pkg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(third), `"Store"`) || strings.Contains(string(third), `"Shop"`) {
+		t.Errorf("change not picked up, got %s", third)
+	}
+	if got := programs(); len(got) != 1 || got[0] != bins[0] {
+		t.Errorf("got programs %v, want %v", got, bins)
+	}
+}
+
+func TestIsTemp(t *testing.T) {
+	tmp := os.TempDir()
+	cases := map[string]bool{
+		tmp: true,
+		filepath.Join(tmp, "mdl--1", "worktree", "go.mod"): true,
+		tmp + "-other": false,
+		filepath.Join(filepath.Dir(tmp), "home", "go.mod"): false,
+	}
+	for path, want := range cases {
+		if got := isTemp(path); got != want {
+			t.Errorf("isTemp(%q): got %v, want %v", path, got, want)
+		}
+	}
+}
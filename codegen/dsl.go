@@ -0,0 +1,984 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// DSL returns the Go source code of a package named pkg that describes d with
+// the model DSL. Running "mdl gen" on the generated package produces a design
+// equivalent to d: the element and relationship IDs differ and the
+// relationships between container and software system instances are derived
+// from the relationships between the corresponding containers and software
+// systems by the DSL engine. If d contains all the relationships implied by
+// the relationships between the children of elements the generated design
+// uses AddImpliedRelationships instead of listing them. The model DSL cannot
+// describe the relationships between deployment elements that are not derived
+// from other relationships, the documentation of people and deployment
+// elements and the links between decisions, these are listed as comments.
+func DSL(d *mdl.Design, pkg string) ([]byte, error) {
+	g := &dslGenerator{graph: query.New(d), doc: d.Documentation}
+	g.implied = impliedRelationships(g.graph)
+	g.design(d, pkg)
+	b, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return b, nil
+}
+
+// dslGenerator writes the model DSL corresponding to a design. The generated
+// code is not indented, DSL formats it once complete.
+type dslGenerator struct {
+	buf   bytes.Buffer
+	graph *query.Graph
+	doc   *mdl.Documentation
+	// implied lists the IDs of the relationships added by the DSL engine
+	// when AddImpliedRelationships is used.
+	implied map[string]bool
+}
+
+// design writes the package clause and the Design expression.
+func (g *dslGenerator) design(d *mdl.Design, pkg string) {
+	g.line("package %s", pkg)
+	g.line("")
+	g.line(`import . "goa.design/model/dsl"`)
+	g.line("")
+	var args []string
+	if d.Name != "" || d.Description != "" {
+		args = append(args, strconv.Quote(d.Name))
+	}
+	if d.Description != "" {
+		args = append(args, strconv.Quote(d.Description))
+	}
+	g.open("var _ = Design", args...)
+	if d.Version != "" {
+		g.line("Version(%q)", d.Version)
+	}
+	if m := d.Model; m != nil {
+		if m.Enterprise != nil {
+			g.line("Enterprise(%q)", m.Enterprise.Name)
+		}
+		if len(g.implied) > 0 {
+			g.line("AddImpliedRelationships()")
+		}
+		var tops []any
+		for _, p := range m.People {
+			tops = append(tops, p)
+		}
+		for _, s := range m.Systems {
+			tops = append(tops, s)
+		}
+		g.grouped(len(tops), func(i int) string { return elementGroup(tops[i]) }, func(i int) {
+			switch e := tops[i].(type) {
+			case *mdl.Person:
+				g.person(e)
+			case *mdl.SoftwareSystem:
+				g.system(e)
+			}
+		})
+		g.deployment(m.DeploymentNodes)
+	}
+	g.documentation("")
+	if d.Views != nil {
+		g.views(d.Views)
+	}
+	g.close(")")
+}
+
+// person writes the Person expression describing p.
+func (g *dslGenerator) person(p *mdl.Person) {
+	g.element("Person", []string{p.Name, p.Description}, func() {
+		g.tags(p.Tags, expr.PersonTags)
+		g.url(p.URL)
+		if p.Location == mdl.LocationExternal {
+			g.line("External()")
+		}
+		g.props(p.Properties)
+		g.relationships(p.ID)
+	})
+}
+
+// system writes the SoftwareSystem expression describing s.
+func (g *dslGenerator) system(s *mdl.SoftwareSystem) {
+	g.element("SoftwareSystem", []string{s.Name, s.Description}, func() {
+		g.tags(s.Tags, expr.SoftwareSystemTags)
+		g.url(s.URL)
+		if s.Location == mdl.LocationExternal {
+			g.line("External()")
+		}
+		g.props(s.Properties)
+		g.grouped(len(s.Containers), func(i int) string { return s.Containers[i].Group }, func(i int) {
+			g.container(s.Containers[i])
+		})
+		g.documentation(s.ID)
+		g.relationships(s.ID)
+	})
+}
+
+// container writes the Container expression describing c.
+func (g *dslGenerator) container(c *mdl.Container) {
+	g.element("Container", []string{c.Name, c.Description, c.Technology}, func() {
+		g.tags(c.Tags, expr.ContainerTags)
+		g.url(c.URL)
+		g.props(c.Properties)
+		g.grouped(len(c.Components), func(i int) string { return c.Components[i].Group }, func(i int) {
+			g.component(c.Components[i])
+		})
+		g.documentation(c.ID)
+		g.relationships(c.ID)
+	})
+}
+
+// component writes the Component expression describing c.
+func (g *dslGenerator) component(c *mdl.Component) {
+	g.element("Component", []string{c.Name, c.Description, c.Technology}, func() {
+		g.tags(c.Tags, expr.ComponentTags)
+		g.url(c.URL)
+		g.props(c.Properties)
+		for _, ce := range c.CodeElements {
+			g.element("CodeElement", []string{ce.Name, ce.Description, ce.Technology}, func() {
+				g.tags(ce.Tags, expr.CodeElementTags)
+				g.url(ce.URL)
+				g.props(ce.Properties)
+				g.relationships(ce.ID)
+			})
+		}
+		g.documentation(c.ID)
+		g.relationships(c.ID)
+	})
+}
+
+// relationships writes the relationships whose source is the element with the
+// given ID. Relationships derived by the DSL engine are skipped.
+func (g *dslGenerator) relationships(id string) {
+	src := g.graph.Element(id)
+	for _, r := range g.graph.Outgoing(id) {
+		if r.LinkedRelationshipID != "" || g.implied[r.ID] {
+			continue
+		}
+		dest := g.graph.Element(r.DestinationID)
+		if dest == nil {
+			continue
+		}
+		if src.Kind >= query.KindDeploymentNode || dest.Kind >= query.KindDeploymentNode {
+			g.line("// Relationship %q -> %q (%s) cannot be described with the model DSL.", src.Path, dest.Path, r.Description)
+			continue
+		}
+		fn, target := "Uses", dest.Path
+		switch {
+		case dest.Kind == query.KindPerson && src.Kind == query.KindPerson:
+			fn = "InteractsWith"
+		case dest.Kind == query.KindPerson && src.Kind != query.KindCodeElement:
+			fn = "Delivers"
+		}
+		args := []string{strconv.Quote(target), strconv.Quote(r.Description)}
+		if r.Technology != "" {
+			args = append(args, strconv.Quote(r.Technology))
+		}
+		// Only the "Relationship" and "Asynchronous" tags are added by the
+		// DSL engine, a "Synchronous" tag must be set explicitly.
+		defaults := expr.RelationshipTags[:1]
+		switch r.InteractionStyle {
+		case mdl.InteractionSynchronous:
+			args = append(args, "Synchronous")
+		case mdl.InteractionAsynchronous:
+			args = append(args, "Asynchronous")
+			defaults = expr.RelationshipTags[:2]
+		}
+		tags := customTags(r.Tags, defaults)
+		if len(tags) == 0 && r.URL == "" {
+			g.line("%s(%s)", fn, strings.Join(args, ", "))
+			continue
+		}
+		g.open(fn, args...)
+		g.tags(r.Tags, defaults)
+		g.url(r.URL)
+		g.close(")")
+	}
+}
+
+// documentation writes the Documentation and Decision expressions of the
+// software system, container or component with the given ID, of the design if
+// id is empty. The documentation of other elements is listed as comments in
+// the design.
+func (g *dslGenerator) documentation(id string) {
+	if g.doc == nil {
+		return
+	}
+	inScope := func(elemID string) bool {
+		if elemID == id {
+			return true
+		}
+		if id != "" {
+			return false
+		}
+		e := g.graph.Element(elemID)
+		return e == nil || e.Kind != query.KindSoftwareSystem && e.Kind != query.KindContainer && e.Kind != query.KindComponent
+	}
+	for _, s := range g.doc.Sections {
+		if !inScope(s.ElementID) {
+			continue
+		}
+		if s.ElementID != id {
+			g.line("// Documentation %q of %s cannot be described with the model DSL.", s.Title, g.path(s.ElementID))
+			continue
+		}
+		if s.Format != mdl.FormatASCIIDoc {
+			g.line("Documentation(%q, %s)", s.Title, text(s.Content))
+			continue
+		}
+		g.open("Documentation", strconv.Quote(s.Title))
+		g.line("DocContent(%s)", text(s.Content))
+		g.line("DocFormat(FormatASCIIDoc)")
+		g.close(")")
+	}
+	for _, d := range g.doc.Decisions {
+		if !inScope(d.ElementID) {
+			continue
+		}
+		if d.ElementID != id {
+			g.line("// Decision %q of %s cannot be described with the model DSL.", d.ID, g.path(d.ElementID))
+			continue
+		}
+		g.open("Decision", strconv.Quote(d.ID), strconv.Quote(d.Title))
+		g.stringProp("DecisionDate", d.Date)
+		if d.Status > mdl.DecisionProposed {
+			g.line("DecisionStatus(Decision%s)", enumString(d.Status))
+		}
+		if d.Format == mdl.FormatASCIIDoc {
+			g.line("DocFormat(FormatASCIIDoc)")
+		}
+		if d.Content != "" {
+			g.line("DocContent(%s)", text(d.Content))
+		}
+		for _, l := range d.Links {
+			g.line("// Link %q to decision %q cannot be described with the model DSL.", l.Description, l.ID)
+		}
+		g.close(")")
+	}
+}
+
+// deployment writes one DeploymentEnvironment expression per environment
+// defined by the given top level deployment nodes.
+func (g *dslGenerator) deployment(nodes []*mdl.DeploymentNode) {
+	var envs []string
+	byEnv := make(map[string][]*mdl.DeploymentNode)
+	for _, n := range nodes {
+		if _, ok := byEnv[n.Environment]; !ok {
+			envs = append(envs, n.Environment)
+		}
+		byEnv[n.Environment] = append(byEnv[n.Environment], n)
+	}
+	for _, env := range envs {
+		g.open("DeploymentEnvironment", strconv.Quote(env))
+		for _, grp := range deploymentGroups(byEnv[env]) {
+			g.line("DeploymentGroup(%q)", grp)
+		}
+		for _, n := range byEnv[env] {
+			g.deploymentNode(n)
+		}
+		g.close(")")
+	}
+}
+
+// deploymentNode writes the DeploymentNode expression describing n.
+func (g *dslGenerator) deploymentNode(n *mdl.DeploymentNode) {
+	g.element("DeploymentNode", []string{n.Name, n.Description, n.Technology}, func() {
+		g.tags(n.Tags, expr.DeploymentNodeTags)
+		g.url(n.URL)
+		if n.Instances != nil && *n.Instances != "1" {
+			g.line("Instances(%q)", *n.Instances)
+		}
+		g.props(n.Properties)
+		for _, in := range n.InfrastructureNodes {
+			g.element("InfrastructureNode", []string{in.Name, in.Description, in.Technology}, func() {
+				g.tags(in.Tags, expr.InfrastructureNodeTags)
+				g.url(in.URL)
+				g.props(in.Properties)
+				g.relationships(in.ID)
+			})
+		}
+		for _, c := range n.Children {
+			g.deploymentNode(c)
+		}
+		for _, ci := range n.ContainerInstances {
+			g.instance("ContainerInstance", ci.ID, ci.ContainerID, ci.Tags, expr.ContainerInstanceTags, ci.InstanceID, ci.DeploymentGroups, ci.HealthChecks, ci.Properties)
+		}
+		for _, si := range n.SoftwareSystemInstances {
+			g.instance("SoftwareSystemInstance", si.ID, si.SoftwareSystemID, si.Tags, expr.SoftwareSystemInstanceTags, si.InstanceID, si.DeploymentGroups, si.HealthChecks, si.Properties)
+		}
+		g.relationships(n.ID)
+	})
+}
+
+// instance writes a ContainerInstance or SoftwareSystemInstance expression.
+func (g *dslGenerator) instance(fn, id, elemID, tags string, defaults []string, instanceID int, groups []string, checks []*mdl.HealthCheck, props map[string]string) {
+	e := g.graph.Element(elemID)
+	if e == nil {
+		return
+	}
+	start := g.buf.Len()
+	g.open(fn, strconv.Quote(e.Path))
+	mark := g.buf.Len()
+	g.tags(tags, append([]string{"Element"}, defaults...))
+	if instanceID > 1 {
+		g.line("InstanceID(%d)", instanceID)
+	}
+	for _, grp := range groups {
+		g.line("DeploymentGroup(%q)", grp)
+	}
+	for _, hc := range checks {
+		g.open("HealthCheck", strconv.Quote(hc.Name))
+		g.url(hc.URL)
+		if hc.Interval > 0 {
+			g.line("Interval(%d)", hc.Interval)
+		}
+		if hc.Timeout > 0 {
+			g.line("Timeout(%d)", hc.Timeout)
+		}
+		for _, k := range sortedKeys(hc.Headers) {
+			g.line("Header(%q, %q)", k, hc.Headers[k])
+		}
+		g.close(")")
+	}
+	g.props(props)
+	g.relationships(id)
+	if g.buf.Len() == mark {
+		g.buf.Truncate(start)
+		g.line("%s(%q)", fn, e.Path)
+		return
+	}
+	g.close(")")
+}
+
+// views writes the Views expression.
+func (g *dslGenerator) views(v *mdl.Views) {
+	g.open("Views")
+	for _, lv := range v.LandscapeViews {
+		g.view("SystemLandscapeView", nil, lv.ViewProps, func() {
+			if lv.EnterpriseBoundaryVisible != nil && *lv.EnterpriseBoundaryVisible {
+				g.line("EnterpriseBoundaryVisible()")
+			}
+		})
+	}
+	for _, cv := range v.ContextViews {
+		g.view("SystemContextView", []string{g.path(cv.SoftwareSystemID)}, cv.ViewProps, func() {
+			if cv.EnterpriseBoundaryVisible != nil && *cv.EnterpriseBoundaryVisible {
+				g.line("EnterpriseBoundaryVisible()")
+			}
+		})
+	}
+	for _, cv := range v.ContainerViews {
+		g.view("ContainerView", []string{g.path(cv.SoftwareSystemID)}, cv.ViewProps, func() {
+			if cv.SystemBoundariesVisible != nil && *cv.SystemBoundariesVisible {
+				g.line("SystemBoundariesVisible()")
+			}
+		})
+	}
+	for _, cv := range v.ComponentViews {
+		g.view("ComponentView", []string{g.path(cv.ContainerID)}, cv.ViewProps, func() {
+			if cv.ContainerBoundariesVisible != nil && *cv.ContainerBoundariesVisible {
+				g.line("ContainerBoundariesVisible()")
+			}
+		})
+	}
+	for _, cv := range v.CodeViews {
+		g.view("CodeView", []string{g.path(cv.ComponentID)}, cv.ViewProps, nil)
+	}
+	for _, dv := range v.DynamicViews {
+		scope := "Global"
+		if dv.ElementID != "" {
+			scope = g.path(dv.ElementID)
+		}
+		g.view("DynamicView", []string{scope}, dv.ViewProps, nil)
+	}
+	for _, dv := range v.DeploymentViews {
+		scope := "Global"
+		if dv.SoftwareSystemID != "" {
+			scope = g.path(dv.SoftwareSystemID)
+		}
+		g.view("DeploymentView", []string{scope, strconv.Quote(dv.Environment)}, dv.ViewProps, nil)
+	}
+	for _, fv := range v.FilteredViews {
+		g.open("FilteredView", strconv.Quote(fv.BaseKey))
+		if len(fv.Tags) > 0 {
+			g.line("FilterTag(%s)", quoteAll(fv.Tags))
+		}
+		if fv.Mode == "Exclude" {
+			g.line("Exclude()")
+		}
+		g.close(")")
+	}
+	if v.Styles != nil {
+		g.styles(v.Styles)
+	}
+	g.close(")")
+}
+
+// view writes a view expression. args lists the arguments that precede the
+// view key and extra writes the view specific properties.
+func (g *dslGenerator) view(fn string, args []string, vp *mdl.ViewProps, extra func()) {
+	args = append(args, strconv.Quote(vp.Key))
+	if vp.Description != "" {
+		args = append(args, strconv.Quote(vp.Description))
+	}
+	g.open(fn, args...)
+	if vp.Title != "" {
+		g.line("Title(%q)", vp.Title)
+	}
+	if fn == "DynamicView" {
+		g.dynamicLinks(vp)
+	} else {
+		g.viewElements(vp, fn == "DeploymentView")
+	}
+	for _, s := range vp.Animations {
+		var paths []string
+		for _, id := range s.Elements {
+			paths = append(paths, g.viewPath(id))
+		}
+		if len(paths) > 0 {
+			g.line("AnimationStep(%s)", strings.Join(paths, ", "))
+		}
+	}
+	if al := vp.AutoLayout; al != nil {
+		rank := "RankTopBottom"
+		if al.RankDirection != mdl.RankUndefined {
+			rank = "Rank" + enumString(al.RankDirection)
+		}
+		var opts []string
+		if al.Implementation != mdl.ImplementationUndefined {
+			opts = append(opts, fmt.Sprintf("Implementation(Implementation%s)", enumString(al.Implementation)))
+		}
+		if al.RankSep != nil {
+			opts = append(opts, fmt.Sprintf("RankSeparation(%d)", *al.RankSep))
+		}
+		if al.NodeSep != nil {
+			opts = append(opts, fmt.Sprintf("NodeSeparation(%d)", *al.NodeSep))
+		}
+		if al.EdgeSep != nil {
+			opts = append(opts, fmt.Sprintf("EdgeSeparation(%d)", *al.EdgeSep))
+		}
+		if al.Vertices != nil && *al.Vertices {
+			opts = append(opts, "RenderVertices()")
+		}
+		if len(opts) == 0 {
+			g.line("AutoLayout(%s)", rank)
+		} else {
+			g.open("AutoLayout", rank)
+			for _, o := range opts {
+				g.line("%s", o)
+			}
+			g.close(")")
+		}
+	}
+	if vp.PaperSize > mdl.SizeUndefined && int(vp.PaperSize) < len(paperSizes) {
+		g.line("PaperSize(%s)", paperSizes[vp.PaperSize])
+	}
+	if extra != nil {
+		extra()
+	}
+	g.close(")")
+}
+
+// viewElements writes the Add, Link and Unlink expressions of a static or
+// deployment view. The DSL engine adds the relationships between the elements
+// of a view automatically (in deployment views only if the elements belong
+// to the same top level deployment node) so viewElements only links the
+// relationships that would not be added or that define layout properties and
+// unlinks the relationships that would be added but are not in the view.
+func (g *dslGenerator) viewElements(vp *mdl.ViewProps, deployment bool) {
+	inView := make(map[string]bool)
+	for _, ev := range vp.ElementViews {
+		if g.graph.Element(ev.ID) == nil {
+			continue
+		}
+		inView[ev.ID] = true
+		if ev.X != nil && ev.Y != nil {
+			g.open("Add", g.viewPath(ev.ID))
+			g.line("Coord(%d, %d)", *ev.X, *ev.Y)
+			g.close(")")
+			continue
+		}
+		g.line("Add(%s)", g.viewPath(ev.ID))
+	}
+	auto := make(map[string]*mdl.Relationship)
+	var autoIDs []string
+	for _, ev := range vp.ElementViews {
+		for _, r := range g.graph.Outgoing(ev.ID) {
+			if !inView[r.DestinationID] {
+				continue
+			}
+			if deployment && topNode(g.graph.Element(r.SourceID)) != topNode(g.graph.Element(r.DestinationID)) {
+				continue
+			}
+			auto[r.ID] = r
+			autoIDs = append(autoIDs, r.ID)
+		}
+	}
+	linked := make(map[string]bool)
+	for _, rv := range vp.RelationshipViews {
+		r := g.graph.Relationship(rv.ID)
+		if r == nil {
+			continue
+		}
+		linked[r.ID] = true
+		layout := len(rv.Vertices) > 0 || rv.Routing != mdl.RoutingUndefined || rv.Position != nil
+		if _, ok := auto[r.ID]; ok && !layout {
+			continue
+		}
+		g.link(r, "", func() {
+			if len(rv.Vertices) > 0 {
+				var coords []string
+				for _, v := range rv.Vertices {
+					coords = append(coords, strconv.Itoa(v.X), strconv.Itoa(v.Y))
+				}
+				g.line("Vertices(%s)", strings.Join(coords, ", "))
+			}
+			if rv.Routing != mdl.RoutingUndefined {
+				g.line("Routing(Routing%s)", enumString(rv.Routing))
+			}
+			if rv.Position != nil {
+				g.line("Position(%d)", *rv.Position)
+			}
+		})
+	}
+	for _, id := range autoIDs {
+		if !linked[id] {
+			g.link(auto[id], "Unlink", nil)
+		}
+	}
+}
+
+// dynamicLinks writes the Link expressions of a dynamic view in order.
+func (g *dslGenerator) dynamicLinks(vp *mdl.ViewProps) {
+	rvs := make([]*mdl.RelationshipView, len(vp.RelationshipViews))
+	copy(rvs, vp.RelationshipViews)
+	sort.SliceStable(rvs, func(i, j int) bool { return orderLess(rvs[i].Order, rvs[j].Order) })
+	for _, rv := range rvs {
+		if r := g.graph.Relationship(rv.ID); r != nil {
+			g.link(r, "", nil)
+		}
+	}
+}
+
+// link writes a Link (or Unlink if fn is "Unlink") expression for r. body
+// writes the content of the Link function if not nil.
+func (g *dslGenerator) link(r *mdl.Relationship, fn string, body func()) {
+	if fn == "" {
+		fn = "Link"
+	}
+	args := []string{g.viewPath(r.SourceID), g.viewPath(r.DestinationID)}
+	if r.Description != "" {
+		args = append(args, strconv.Quote(r.Description))
+	}
+	if body == nil {
+		g.line("%s(%s)", fn, strings.Join(args, ", "))
+		return
+	}
+	start := g.buf.Len()
+	g.open(fn, args...)
+	mark := g.buf.Len()
+	body()
+	if g.buf.Len() == mark {
+		g.buf.Truncate(start)
+		g.line("%s(%s)", fn, strings.Join(args, ", "))
+		return
+	}
+	g.close(")")
+}
+
+// styles writes the Styles expression.
+func (g *dslGenerator) styles(s *mdl.Styles) {
+	g.open("Styles")
+	for _, es := range s.Elements {
+		g.open("ElementStyle", strconv.Quote(es.Tag))
+		if es.Shape != mdl.ShapeUndefined {
+			g.line("Shape(Shape%s)", enumString(es.Shape))
+		}
+		if es.Icon != "" {
+			g.line("Icon(%q)", es.Icon)
+		}
+		g.intProp("Width", es.Width)
+		g.intProp("Height", es.Height)
+		g.stringProp("Background", es.Background)
+		g.stringProp("Color", es.Color)
+		g.stringProp("Stroke", es.Stroke)
+		g.intProp("FontSize", es.FontSize)
+		if es.Border != mdl.BorderUndefined {
+			g.line("Border(Border%s)", enumString(es.Border))
+		}
+		g.intProp("Opacity", es.Opacity)
+		if es.Metadata != nil && *es.Metadata {
+			g.line("ShowMetadata()")
+		}
+		if es.Description != nil && *es.Description {
+			g.line("ShowDescription()")
+		}
+		g.close(")")
+	}
+	for _, rs := range s.Relationships {
+		g.open("RelationshipStyle", strconv.Quote(rs.Tag))
+		g.intProp("Thickness", rs.Thickness)
+		g.stringProp("Color", rs.Color)
+		g.intProp("FontSize", rs.FontSize)
+		g.intProp("Width", rs.Width)
+		if rs.Dashed != nil {
+			if *rs.Dashed {
+				g.line("Dashed()")
+			} else {
+				g.line("Solid()")
+			}
+		}
+		if rs.Routing != mdl.RoutingUndefined {
+			g.line("Routing(Routing%s)", enumString(rs.Routing))
+		}
+		g.intProp("Position", rs.Position)
+		g.intProp("Opacity", rs.Opacity)
+		g.close(")")
+	}
+	g.close(")")
+}
+
+// element writes an element expression with the given name, description and
+// technology (args) omitting trailing empty arguments. body writes the
+// content of the element function, the function is omitted if empty.
+func (g *dslGenerator) element(fn string, args []string, body func()) {
+	for len(args) > 1 && args[len(args)-1] == "" {
+		args = args[:len(args)-1]
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = strconv.Quote(a)
+	}
+	start := g.buf.Len()
+	g.open(fn, quoted...)
+	mark := g.buf.Len()
+	body()
+	if g.buf.Len() == mark {
+		g.buf.Truncate(start)
+		g.line("%s(%s)", fn, strings.Join(quoted, ", "))
+		return
+	}
+	g.close(")")
+}
+
+// grouped calls write for the n items in order except that the items that
+// belong to a group are written together in a Group expression at the
+// position of the first item of the group.
+func (g *dslGenerator) grouped(n int, group func(int) string, write func(int)) {
+	done := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		name := group(i)
+		if name == "" {
+			write(i)
+			continue
+		}
+		if done[name] {
+			continue
+		}
+		done[name] = true
+		g.open("Group", strconv.Quote(name))
+		for j := i; j < n; j++ {
+			if group(j) == name {
+				write(j)
+			}
+		}
+		g.close(")")
+	}
+}
+
+// tags writes the Tag expression listing the tags that are not in defaults.
+func (g *dslGenerator) tags(tags string, defaults []string) {
+	if custom := customTags(tags, defaults); len(custom) > 0 {
+		g.line("Tag(%s)", quoteAll(custom))
+	}
+}
+
+// url writes the URL expression if u is not empty.
+func (g *dslGenerator) url(u string) {
+	g.stringProp("URL", u)
+}
+
+// props writes the Prop expressions sorted by property name.
+func (g *dslGenerator) props(props map[string]string) {
+	for _, k := range sortedKeys(props) {
+		g.line("Prop(%q, %q)", k, props[k])
+	}
+}
+
+// stringProp writes the expression fn(v) if v is not empty.
+func (g *dslGenerator) stringProp(fn, v string) {
+	if v != "" {
+		g.line("%s(%q)", fn, v)
+	}
+}
+
+// intProp writes the expression fn(*v) if v is not nil.
+func (g *dslGenerator) intProp(fn string, v *int) {
+	if v != nil {
+		g.line("%s(%d)", fn, *v)
+	}
+}
+
+// path returns the quoted path of the element with the given ID.
+func (g *dslGenerator) path(id string) string {
+	if e := g.graph.Element(id); e != nil {
+		return strconv.Quote(e.Path)
+	}
+	return strconv.Quote(id)
+}
+
+// viewPath returns the quoted path used to add the element with the given ID
+// to a view. The path of deployment elements omits the environment and the
+// path of container and software system instances ends with the instance ID
+// if greater than 1.
+func (g *dslGenerator) viewPath(id string) string {
+	e := g.graph.Element(id)
+	if e == nil {
+		return strconv.Quote(id)
+	}
+	if e.Kind < query.KindDeploymentNode {
+		return strconv.Quote(e.Path)
+	}
+	p := e.Path[strings.Index(e.Path, "/")+1:]
+	var instanceID int
+	switch i := e.Value.(type) {
+	case *mdl.ContainerInstance:
+		instanceID = i.InstanceID
+	case *mdl.SoftwareSystemInstance:
+		instanceID = i.InstanceID
+	}
+	if instanceID > 1 {
+		p += "/" + strconv.Itoa(instanceID)
+	}
+	return strconv.Quote(p)
+}
+
+// open writes the beginning of a call to fn whose last argument is a
+// function.
+func (g *dslGenerator) open(fn string, args ...string) {
+	args = append(args, "func() {")
+	g.line("%s(%s", fn, strings.Join(args, ", "))
+}
+
+// close writes the end of the function opened last followed by suffix.
+func (g *dslGenerator) close(suffix string) {
+	g.line("}%s", suffix)
+}
+
+// line writes a line of code.
+func (g *dslGenerator) line(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// impliedRelationships returns the IDs of the relationships of the model
+// described by g that the DSL engine adds when AddImpliedRelationships is
+// used: the relationships between the parents of the source and destination of
+// other relationships that have the same description, technology and
+// interaction style. It returns nil unless the model contains all the
+// relationships the DSL engine would add so that using
+// AddImpliedRelationships produces an equivalent model.
+func impliedRelationships(g *query.Graph) map[string]bool {
+	var rels []*mdl.Relationship
+	for _, e := range g.Elements() {
+		if e.Kind >= query.KindDeploymentNode {
+			continue
+		}
+		for _, r := range g.Outgoing(e.ID) {
+			if dest := g.Element(r.DestinationID); dest != nil && dest.Kind < query.KindDeploymentNode && r.LinkedRelationshipID == "" {
+				rels = append(rels, r)
+			}
+		}
+	}
+	implied := make(map[string]bool)
+	for _, r := range rels {
+		src, dest := g.Element(r.SourceID), g.Element(r.DestinationID)
+		for _, o := range rels {
+			if o == r || o.Description != r.Description || o.Technology != r.Technology || o.InteractionStyle != r.InteractionStyle {
+				continue
+			}
+			if src.Contains(g.Element(o.SourceID)) && dest.Contains(g.Element(o.DestinationID)) &&
+				(o.SourceID != r.SourceID || o.DestinationID != r.DestinationID) {
+				implied[r.ID] = true
+				break
+			}
+		}
+	}
+	if len(implied) == 0 {
+		return nil
+	}
+	exists := func(src, dest *query.Element, desc string) bool {
+		for _, r := range g.Outgoing(src.ID) {
+			if r.DestinationID == dest.ID && r.Description == desc {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range rels {
+		if implied[r.ID] {
+			continue
+		}
+		src, dest := g.Element(r.SourceID), g.Element(r.DestinationID)
+		for s := src; s != nil; s = s.Parent {
+			for d := dest; d != nil; d = d.Parent {
+				if s == src && d == dest || s.Contains(d) || d.Contains(s) {
+					continue
+				}
+				if !exists(s, d, r.Description) {
+					return nil
+				}
+			}
+		}
+	}
+	return implied
+}
+
+// text returns the Go string literal for s, a raw string literal if s spans
+// multiple lines.
+func text(s string) string {
+	if strings.Contains(s, "\n") && !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// elementGroup returns the group of a person or software system.
+func elementGroup(e any) string {
+	switch v := e.(type) {
+	case *mdl.Person:
+		return v.Group
+	case *mdl.SoftwareSystem:
+		return v.Group
+	}
+	return ""
+}
+
+// deploymentGroups returns the deployment groups of the instances deployed on
+// the given nodes in order of appearance.
+func deploymentGroups(nodes []*mdl.DeploymentNode) []string {
+	var groups []string
+	seen := make(map[string]bool)
+	add := func(gs []string) {
+		for _, grp := range gs {
+			if !seen[grp] {
+				seen[grp] = true
+				groups = append(groups, grp)
+			}
+		}
+	}
+	var walk func(n *mdl.DeploymentNode)
+	walk = func(n *mdl.DeploymentNode) {
+		for _, ci := range n.ContainerInstances {
+			add(ci.DeploymentGroups)
+		}
+		for _, si := range n.SoftwareSystemInstances {
+			add(si.DeploymentGroups)
+		}
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+	return groups
+}
+
+// topNode returns the ID of the top level deployment node that contains e,
+// the empty string if e is not a deployment element.
+func topNode(e *query.Element) string {
+	if e == nil || e.Kind < query.KindDeploymentNode {
+		return ""
+	}
+	for e.Parent != nil {
+		e = e.Parent
+	}
+	return e.ID
+}
+
+// customTags returns the tags in the comma separated list tags that are not
+// in defaults.
+func customTags(tags string, defaults []string) []string {
+	var res []string
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		isDefault := false
+		for _, d := range defaults {
+			if t == d {
+				isDefault = true
+				break
+			}
+		}
+		if !isDefault {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// quoteAll returns the comma separated list of the quoted values.
+func quoteAll(vals []string) string {
+	quoted := make([]string, len(vals))
+	for i, v := range vals {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// sortedKeys returns the keys of m in alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// enumString returns the JSON string representation of an mdl enum value,
+// e.g. "Cylinder" for mdl.ShapeCylinder. The DSL constant names are made of
+// a prefix followed by that string, e.g. dsl.ShapeCylinder.
+func enumString(v json.Marshaler) string {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(string(b), `"`)
+}
+
+// orderLess compares dynamic view relationship orders, numerically when
+// possible.
+func orderLess(a, b string) bool {
+	na, erra := strconv.Atoi(a)
+	nb, errb := strconv.Atoi(b)
+	if erra == nil && errb == nil {
+		return na < nb
+	}
+	return a < b
+}
+
+// paperSizes lists the DSL paper size constant names indexed by
+// mdl.PaperSizeKind.
+var paperSizes = []string{"",
+	"SizeA0Landscape", "SizeA0Portrait", "SizeA1Landscape", "SizeA1Portrait",
+	"SizeA2Landscape", "SizeA2Portrait", "SizeA3Landscape", "SizeA3Portrait",
+	"SizeA4Landscape", "SizeA4Portrait", "SizeA5Landscape", "SizeA5Portrait",
+	"SizeA6Landscape", "SizeA6Portrait", "SizeLegalLandscape", "SizeLegalPortrait",
+	"SizeLetterLandscape", "SizeLetterPortrait", "SizeSlide16X10", "SizeSlide16X9",
+	"SizeSlide4X3",
+}
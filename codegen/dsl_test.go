@@ -0,0 +1,189 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestDSL(t *testing.T) {
+	d := &mdl.Design{
+		Name:        "Shop",
+		Description: "The shop design",
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person,Customer", Location: mdl.LocationExternal,
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Description: "Sells things", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship,Asynchronous", InteractionStyle: mdl.InteractionAsynchronous},
+						},
+					},
+					{ID: "db", Name: "DB", Technology: "PostgreSQL", Tags: "Element,Container,Database", Properties: map[string]string{"owner": "dba"}},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID: "node", Name: "Server", Environment: "Production", Tags: "Element,Deployment Node",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "webInst", ContainerID: "web", InstanceID: 1, Environment: "Production", Tags: "Element,Container Instance"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					Title:             "Shop containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}},
+				},
+			}},
+			Styles: &mdl.Styles{
+				Elements: []*mdl.ElementStyle{{Tag: "Database", Shape: mdl.ShapeCylinder}},
+			},
+		},
+	}
+
+	got, err := DSL(d, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package shop
+
+import . "goa.design/model/dsl"
+
+var _ = Design("Shop", "The shop design", func() {
+	Person("User", func() {
+		Tag("Customer")
+		External()
+		Uses("Shop/Web", "Browses", "HTTPS")
+	})
+	SoftwareSystem("Shop", "Sells things", func() {
+		Container("Web", "", "Go", func() {
+			Uses("Shop/DB", "Reads", Asynchronous)
+		})
+		Container("DB", "", "PostgreSQL", func() {
+			Tag("Database")
+			Prop("owner", "dba")
+		})
+	})
+	DeploymentEnvironment("Production", func() {
+		DeploymentNode("Server", func() {
+			ContainerInstance("Shop/Web")
+		})
+	})
+	Views(func() {
+		ContainerView("Shop", "Containers", func() {
+			Title("Shop containers")
+			Add("User")
+			Add("Shop/Web")
+			Add("Shop/DB")
+			Unlink("Shop/Web", "Shop/DB", "Reads")
+		})
+		Styles(func() {
+			ElementStyle("Database", func() {
+				Shape(ShapeCylinder)
+			})
+		})
+	})
+})
+`
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDSLEvaluates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated package")
+	}
+	d := &mdl.Design{
+		Name: "Shop",
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person",
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", URL: "https://shop.example.com", Tags: "Relationship"},
+					// Implied by r1.
+					{ID: "r2", SourceID: "user", DestinationID: "shop", Description: "Browses", Technology: "HTTPS", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{ID: "web", Name: "Web", Tags: "Element,Container"},
+				},
+			}},
+		},
+		Documentation: &mdl.Documentation{
+			Sections: []*mdl.DocumentationSection{
+				{Title: "Overview", Content: "# Overview\n\nThe `shop`.", Format: mdl.FormatMarkdown, Order: 1},
+				{Title: "Context", Content: "= Context\n\nThe shop.\n", Format: mdl.FormatASCIIDoc, Order: 2, ElementID: "shop"},
+			},
+			Decisions: []*mdl.Decision{
+				{ID: "1", Title: "Use Go", Date: "2024-01-15", Status: mdl.DecisionAccepted, Content: "We use Go.\n", Format: mdl.FormatMarkdown, ElementID: "web"},
+			},
+		},
+	}
+	src, err := DSL(d, "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := os.MkdirTemp(".", "dsltest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir) // nolint: errcheck
+	if err := os.WriteFile(filepath.Join(dir, "model.go"), src, 0600); err != nil {
+		t.Fatal(err)
+	}
+	b, err := JSON("goa.design/model/codegen/"+filepath.Base(dir), false)
+	if err != nil {
+		t.Fatalf("generated DSL does not evaluate: %s\n%s", err, src)
+	}
+	var got mdl.Design
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	rels := got.Model.People[0].Relationships
+	if len(rels) != 2 {
+		t.Fatalf("got %d relationships, want 2:\n%s", len(rels), src)
+	}
+	for _, r := range rels {
+		if r.Description != "Browses" || r.Technology != "HTTPS" || r.URL != "https://shop.example.com" {
+			t.Errorf("got relationship %q [%s] with URL %q", r.Description, r.Technology, r.URL)
+		}
+	}
+	doc := got.Documentation
+	if doc == nil || len(doc.Sections) != 2 || len(doc.Decisions) != 1 {
+		t.Fatalf("got documentation %+v, want 2 sections and 1 decision:\n%s", doc, src)
+	}
+	shop, web := got.Model.Systems[0], got.Model.Systems[0].Containers[0]
+	for i, s := range d.Documentation.Sections {
+		g := doc.Sections[i]
+		want := ""
+		if s.ElementID != "" {
+			want = shop.ID
+		}
+		if g.Title != s.Title || g.Content != s.Content || g.Format != s.Format || g.ElementID != want {
+			t.Errorf("got section %+v, want %+v", g, s)
+		}
+	}
+	dec, want := doc.Decisions[0], d.Documentation.Decisions[0]
+	if dec.ID != want.ID || dec.Title != want.Title || dec.Date != want.Date || dec.Status != want.Status ||
+		dec.Content != want.Content || dec.Format != want.Format || dec.ElementID != web.ID {
+		t.Errorf("got decision %+v, want %+v", dec, want)
+	}
+}
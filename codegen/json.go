@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 
 	"goa.design/goa/v3/codegen"
 	"golang.org/x/tools/go/packages"
@@ -16,17 +17,45 @@ const TmpDirPrefix = "mdl--"
 // JSON generates a JSON representation of the model described in pkg.
 // pkg must be a valid Go package import path.
 func JSON(pkg string, debug bool) ([]byte, error) {
+	return JSONInDir("", pkg, debug)
+}
+
+// JSONInDir is like JSON but resolves pkg from dir instead of the current
+// directory so that pkg is loaded from the module that contains dir. dir
+// defaults to the current directory if empty.
+func JSONInDir(dir, pkg string, debug bool) ([]byte, error) {
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("goa.design/model/mdl"),
+		codegen.NewImport("_", pkg),
+	}
+	return run(dir, pkg, imports, mainT, debug)
+}
+
+// run generates, compiles and runs a program that imports pkg and whose
+// main function is given by mainSrc. pkg is resolved from dir or from the
+// current directory if dir is empty. The program must write its output to
+// the file whose path is given as first argument. run returns the content of
+// that file. The program is kept between runs so that only the packages that
+// changed since the previous run are compiled again.
+func run(dir, pkg string, imports []*codegen.ImportSpec, mainSrc string, debug bool) ([]byte, error) {
 	// Validate package import path
-	if _, err := packages.Load(&packages.Config{Mode: packages.NeedName}, pkg); err != nil {
+	if _, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: dir}, pkg); err != nil {
 		return nil, err
 	}
-
-	// Write program that generates JSON
-	cwd, err := os.Getwd()
+	gobin, err := exec.LookPath("go")
 	if err != nil {
-		cwd = "."
+		return nil, fmt.Errorf(`failed to find a go compiler, looked in "%s"`, os.Getenv("PATH"))
 	}
-	tmpDir, err := os.MkdirTemp(cwd, TmpDirPrefix)
+
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			dir = "."
+		}
+	}
+	tmpDir, err := os.MkdirTemp(dir, TmpDirPrefix)
 	if err != nil {
 		return nil, err
 	}
@@ -35,48 +64,85 @@ func JSON(pkg string, debug bool) ([]byte, error) {
 			fmt.Fprintf(os.Stderr, "failed to remove temp dir: %v\n", err)
 		}
 	}()
-	var sections []*codegen.SectionTemplate
-	{
-		imports := []*codegen.ImportSpec{
-			codegen.SimpleImport("fmt"),
-			codegen.SimpleImport("encoding/json"),
-			codegen.SimpleImport("os"),
-			codegen.SimpleImport("goa.design/model/mdl"),
-			codegen.NewImport("_", pkg),
-		}
-		sections = []*codegen.SectionTemplate{
-			codegen.Header("Code Generator", "main", imports),
-			{Name: "main", Source: mainT},
-		}
-	}
-	cf := &codegen.File{Path: "main.go", SectionTemplates: sections}
-	if _, err := cf.Render(tmpDir); err != nil {
-		return nil, err
-	}
 
 	// Compile program
-	gobin, err := exec.LookPath("go")
-	if err != nil {
-		return nil, fmt.Errorf(`failed to find a go compiler, looked in "%s"`, os.Getenv("PATH"))
+	progDir := programDir(gobin, dir, pkg, mainSrc)
+	if debug && progDir != "" {
+		fmt.Fprintf(os.Stderr, "building program in %s\n", progDir)
 	}
-	if _, err := runCmd(gobin, tmpDir, "build", "-o", "mdl"); err != nil {
+	bin, err := build(gobin, tmpDir, progDir, imports, mainSrc)
+	if err != nil {
 		return nil, err
 	}
 
 	// Run program
-	o, err := runCmd(path.Join(tmpDir, "mdl"), tmpDir, "model.json")
+	o, err := runCmd(bin, tmpDir, nil, "output.json")
 	if debug {
 		fmt.Fprintln(os.Stderr, o)
 	}
 	if err != nil {
 		return nil, err
 	}
-	return os.ReadFile(path.Join(tmpDir, "model.json"))
+	return os.ReadFile(path.Join(tmpDir, "output.json"))
+}
+
+// build writes the program that generates the output and compiles it. The
+// program is written and compiled in dir if not empty where it is kept for
+// the next runs: the go command then only compiles the packages that changed
+// and does not link the program again if none did. The program is written
+// and compiled in tmpDir otherwise. build returns the path of the compiled
+// program.
+func build(gobin, tmpDir, dir string, imports []*codegen.ImportSpec, mainSrc string) (string, error) {
+	if dir == "" {
+		if err := writeMain(tmpDir, "main.go", imports, mainSrc); err != nil {
+			return "", err
+		}
+		out := filepath.Join(tmpDir, "mdl"+exeSuffix())
+		if _, err := runCmd(gobin, tmpDir, nil, "build", "-o", out); err != nil {
+			return "", err
+		}
+		return out, nil
+	}
+	src := filepath.Join(dir, "main.go")
+	if _, err := os.Stat(src); err != nil {
+		// Rename the source so that concurrent runs never see a partial file.
+		tmp := fmt.Sprintf("main-%d.go", os.Getpid())
+		if err := writeMain(dir, tmp, imports, mainSrc); err != nil {
+			return "", err
+		}
+		if err := os.Rename(filepath.Join(dir, tmp), src); err != nil {
+			return "", err
+		}
+	}
+	// Build from tmpDir so that pkg is resolved using the current module.
+	// Setting GOTMPDIR to dir makes the go command rename the linked program
+	// into place so that concurrent runs never execute a partial file.
+	out := filepath.Join(dir, "mdl"+exeSuffix())
+	if _, err := runCmd(gobin, tmpDir, []string{"GOTMPDIR=" + dir}, "build", "-o", out, src); err != nil {
+		return "", err
+	}
+	return out, nil
 }
 
-func runCmd(path, dir string, args ...string) (string, error) {
+// writeMain renders the program main file with the given name in dir.
+func writeMain(dir, name string, imports []*codegen.ImportSpec, mainSrc string) error {
+	sections := []*codegen.SectionTemplate{
+		codegen.Header("Code Generator", "main", imports),
+		{Name: "main", Source: mainSrc},
+	}
+	cf := &codegen.File{Path: name, SectionTemplates: sections}
+	_, err := cf.Render(dir)
+	return err
+}
+
+// runCmd runs the command at path in dir with the given additional
+// environment variables and returns its output.
+func runCmd(path, dir string, env []string, args ...string) (string, error) {
 	args = append([]string{path}, args...) // args[0] becomes exec path
 	c := exec.Cmd{Path: path, Args: args, Dir: dir}
+	if len(env) > 0 {
+		c.Env = append(os.Environ(), env...)
+	}
 	b, err := c.CombinedOutput()
 	if err != nil {
 		if len(b) > 0 {
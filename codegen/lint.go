@@ -0,0 +1,43 @@
+package codegen
+
+import (
+	"goa.design/goa/v3/codegen"
+)
+
+// Lint evaluates the model described in pkg, checks it against the lint
+// rules and returns the JSON representation of the violations using the
+// rules default severities. pkg must be a valid Go package import path.
+func Lint(pkg string, debug bool) ([]byte, error) {
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("goa.design/model/expr"),
+		codegen.SimpleImport("goa.design/model/lint"),
+		codegen.SimpleImport("goa.design/model/mdl"),
+		codegen.NewImport("_", pkg),
+	}
+	return run("", pkg, imports, lintT, debug)
+}
+
+// lintT is the template for the lint program main.
+const lintT = `func main() {
+	// Retrieve output path
+	out := os.Args[1]
+
+	// Run the model DSL
+	if _, err := mdl.RunDSL(); err != nil {
+		fmt.Fprint(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	b, err := json.MarshalIndent(lint.Check(expr.Root), "", "    ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode into JSON: %s", err.Error())
+		os.Exit(1)
+	}
+	if err := os.WriteFile(out, b, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write file: %s", err.Error())
+		os.Exit(1)
+	}
+}
+`
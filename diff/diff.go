@@ -0,0 +1,290 @@
+/*
+Package diff computes the semantic differences between two versions of a
+design. Elements, relationships and deployment elements are matched by ID,
+views by key and styles by tag so that the result does not depend on the
+order in which they appear in the design JSON.
+
+Each change records the field level differences of modified entities. Tags
+and the elements and relationships included in views are compared as sets and
+report the added and removed values.
+*/
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// Change describes an entity that was added, removed or modified.
+	Change struct {
+		// Kind is the kind of change.
+		Kind ChangeKind `json:"kind"`
+		// Type is the type of the changed entity, e.g. "Container".
+		Type string `json:"type"`
+		// ID is the stable identifier of the changed entity: the ID of
+		// elements and relationships, the key of views and the tag of
+		// styles.
+		ID string `json:"id"`
+		// Name is a human friendly name for the changed entity, e.g. the
+		// path of an element.
+		Name string `json:"name"`
+		// Fields lists the field level changes of modified entities.
+		Fields []*FieldChange `json:"fields,omitempty"`
+	}
+
+	// FieldChange describes a change to a field of an entity.
+	FieldChange struct {
+		// Field is the name of the field as it appears in the design JSON.
+		Field string `json:"field"`
+		// Old is the old value of scalar fields.
+		Old any `json:"old,omitempty"`
+		// New is the new value of scalar fields.
+		New any `json:"new,omitempty"`
+		// Added lists the values added to set fields.
+		Added []string `json:"added,omitempty"`
+		// Removed lists the values removed from set fields.
+		Removed []string `json:"removed,omitempty"`
+	}
+
+	// ChangeKind is the enum for possible change kinds.
+	ChangeKind int
+
+	// entity is a comparable representation of a design entity.
+	entity struct {
+		typ    string
+		id     string
+		name   string
+		fields map[string]any
+	}
+)
+
+const (
+	// Added means the entity only exists in the new design.
+	Added ChangeKind = iota + 1
+	// Removed means the entity only exists in the old design.
+	Removed
+	// Modified means the entity exists in both designs with differences.
+	Modified
+)
+
+// Compare returns the changes between the old and new designs. Changes are
+// sorted by entity type then by name.
+func Compare(old, new *mdl.Design) []*Change {
+	olds, news := entities(old), entities(new)
+	oldNames, newNames := names(olds), names(news)
+	var changes []*Change
+	for key, n := range news {
+		o, ok := olds[key]
+		if !ok {
+			changes = append(changes, &Change{Kind: Added, Type: n.typ, ID: n.id, Name: n.name})
+			continue
+		}
+		if fields := compareFields(o, n, oldNames, newNames); len(fields) > 0 {
+			changes = append(changes, &Change{Kind: Modified, Type: n.typ, ID: n.id, Name: n.name, Fields: fields})
+		}
+	}
+	for key, o := range olds {
+		if _, ok := news[key]; !ok {
+			changes = append(changes, &Change{Kind: Removed, Type: o.typ, ID: o.id, Name: o.name})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		ti, tj := typeOrder(changes[i].Type), typeOrder(changes[j].Type)
+		if ti != tj {
+			return ti < tj
+		}
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+// String returns the name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return "undefined"
+	}
+}
+
+// MarshalJSON replaces the constant value with the proper string value.
+func (k ChangeKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON sets the constant from its JSON representation.
+func (k *ChangeKind) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	switch val {
+	case "added":
+		*k = Added
+	case "removed":
+		*k = Removed
+	case "modified":
+		*k = Modified
+	}
+	return nil
+}
+
+// compareFields returns the field level changes between o and n. The names
+// maps are used to describe the elements and relationships added to or
+// removed from views.
+func compareFields(o, n *entity, oldNames, newNames map[string]string) []*FieldChange {
+	keys := make(map[string]struct{})
+	for k := range o.fields {
+		keys[k] = struct{}{}
+	}
+	for k := range n.fields {
+		keys[k] = struct{}{}
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	var res []*FieldChange
+	for _, k := range sorted {
+		ov, nv := o.fields[k], n.fields[k]
+		if reflect.DeepEqual(ov, nv) {
+			continue
+		}
+		switch {
+		case k == "tags":
+			added, removed := setDiff(splitTags(ov), splitTags(nv))
+			res = append(res, &FieldChange{Field: k, Added: added, Removed: removed})
+		case strings.HasSuffix(o.typ, "View") && (k == "elements" || k == "relationships"):
+			added, removed := setDiff(idList(ov), idList(nv))
+			for i, id := range added {
+				added[i] = nameOf(id, newNames)
+			}
+			for i, id := range removed {
+				removed[i] = nameOf(id, oldNames)
+			}
+			res = append(res, &FieldChange{Field: k, Added: added, Removed: removed})
+		default:
+			res = append(res, &FieldChange{Field: k, Old: ov, New: nv})
+		}
+	}
+	return res
+}
+
+// setDiff returns the values of n that are not in o and the values of o that
+// are not in n.
+func setDiff(o, n []string) (added, removed []string) {
+	in := func(s string, l []string) bool {
+		for _, v := range l {
+			if v == s {
+				return true
+			}
+		}
+		return false
+	}
+	for _, v := range n {
+		if !in(v, o) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range o {
+		if !in(v, n) {
+			removed = append(removed, v)
+		}
+	}
+	return
+}
+
+// splitTags returns the tags in the given comma separated list or list of
+// strings.
+func splitTags(v any) []string {
+	if l, ok := v.([]any); ok {
+		return idList(l)
+	}
+	s, _ := v.(string)
+	if s == "" {
+		return nil
+	}
+	tags := strings.Split(s, ",")
+	for i, t := range tags {
+		tags[i] = strings.TrimSpace(t)
+	}
+	return tags
+}
+
+// idList returns the IDs stored in v which must be a list of strings.
+func idList(v any) []string {
+	l, _ := v.([]any)
+	res := make([]string, 0, len(l))
+	for _, id := range l {
+		if s, ok := id.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// nameOf returns the name of the element or relationship with the given ID,
+// the ID itself if not found.
+func nameOf(id string, names map[string]string) string {
+	if n, ok := names[id]; ok {
+		return n
+	}
+	return id
+}
+
+// names returns the names of the entities indexed by ID.
+func names(es map[string]*entity) map[string]string {
+	res := make(map[string]string, len(es))
+	for _, e := range es {
+		res[e.id] = e.name
+	}
+	return res
+}
+
+// typeOrder returns the position of the given entity type in the results.
+func typeOrder(typ string) int {
+	for i, t := range typeNames {
+		if t == typ {
+			return i
+		}
+	}
+	return len(typeNames)
+}
+
+// typeNames lists the entity types in the order they appear in the results.
+var typeNames = []string{
+	"Person",
+	"Software System",
+	"Container",
+	"Component",
+	"Code Element",
+	"Relationship",
+	"Deployment Node",
+	"Infrastructure Node",
+	"Container Instance",
+	"Software System Instance",
+	"System Landscape View",
+	"System Context View",
+	"Container View",
+	"Component View",
+	"Code View",
+	"Dynamic View",
+	"Deployment View",
+	"Filtered View",
+	"Element Style",
+	"Relationship Style",
+}
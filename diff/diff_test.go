@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+// testDesign returns a design with a system containing two containers, a
+// relationship between them, a deployment node, a view and a style.
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			Systems: []*mdl.SoftwareSystem{{
+				ID:   "shop",
+				Name: "Shop",
+				Containers: []*mdl.Container{
+					{ID: "api", Name: "API", Technology: "Go", Tags: "Element,Container", Relationships: []*mdl.Relationship{
+						{ID: "r1", SourceID: "api", DestinationID: "db", Description: "Reads from"},
+					}},
+					{ID: "db", Name: "DB", Technology: "MySQL", Tags: "Element,Container,Legacy"},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{ID: "node", Name: "Server", Environment: "Production"}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					ElementViews:      []*mdl.ElementView{{ID: "api"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}},
+				},
+			}},
+			Styles: &mdl.Styles{Elements: []*mdl.ElementStyle{{Tag: "Legacy", Background: "#999999"}}},
+		},
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	if changes := Compare(testDesign(), testDesign()); len(changes) != 0 {
+		t.Errorf("got %d changes, want none", len(changes))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old, new := testDesign(), testDesign()
+	cs := new.Model.Systems[0].Containers
+	cs[1].Technology = "PostgreSQL"
+	cs[1].Tags = "Element,Container,Database"
+	cs = append(cs, &mdl.Container{ID: "cache", Name: "Cache"})
+	cs[0].Relationships = nil
+	new.Model.Systems[0].Containers = cs
+	new.Model.DeploymentNodes[0].Technology = "Kubernetes"
+	new.Views.ContainerViews[0].ElementViews = []*mdl.ElementView{{ID: "api"}, {ID: "db"}, {ID: "cache"}}
+	new.Views.ContainerViews[0].RelationshipViews = nil
+	new.Views.Styles.Elements[0].Background = "#ff0000"
+
+	changes := Compare(old, new)
+
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.Kind.String()+" "+c.Type+" "+c.Name)
+	}
+	want := []string{
+		"added Container Shop/Cache",
+		"modified Container Shop/DB",
+		"removed Relationship Shop/API -> Shop/DB: Reads from",
+		"modified Deployment Node Production/Server",
+		"modified Container View Containers",
+		"modified Element Style Legacy",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("got changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	db := changes[1]
+	if len(db.Fields) != 2 {
+		t.Fatalf("got %d field changes, want 2", len(db.Fields))
+	}
+	if f := db.Fields[0]; f.Field != "tags" || strings.Join(f.Added, ",") != "Database" || strings.Join(f.Removed, ",") != "Legacy" {
+		t.Errorf("got tags change %+v", f)
+	}
+	if f := db.Fields[1]; f.Field != "technology" || f.Old != "MySQL" || f.New != "PostgreSQL" {
+		t.Errorf("got technology change %+v", f)
+	}
+	view := changes[4]
+	if len(view.Fields) != 2 {
+		t.Fatalf("got %d view field changes, want 2", len(view.Fields))
+	}
+	if f := view.Fields[0]; f.Field != "elements" || strings.Join(f.Added, ",") != "Shop/Cache" || f.Removed != nil {
+		t.Errorf("got view elements change %+v", f)
+	}
+	if f := view.Fields[1]; f.Field != "relationships" || strings.Join(f.Removed, ",") != "Shop/API -> Shop/DB: Reads from" {
+		t.Errorf("got view relationships change %+v", f)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	old, new := testDesign(), testDesign()
+	new.Model.Systems[0].Containers[1].Technology = "PostgreSQL"
+	new.Model.Systems[0].Containers[1].Tags = "Element,Container"
+	changes := Compare(old, new)
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	wantText := `~ Container "Shop/DB"
+    tags: removed "Legacy"
+    technology: "MySQL" -> "PostgreSQL"
+
+0 added, 0 removed, 1 modified
+`
+	if got := buf.String(); got != wantText {
+		t.Errorf("got text:\n%s\nwant:\n%s", got, wantText)
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	wantMarkdown := "### Design changes\n\n0 added, 0 removed, 1 modified\n\n" +
+		"| Change | Type | Name | Details |\n" +
+		"|--------|------|------|---------|\n" +
+		"| Modified | Container | Shop/DB | tags: removed `Legacy`<br>technology: `MySQL` → `PostgreSQL` |\n"
+	if got := buf.String(); got != wantMarkdown {
+		t.Errorf("got markdown:\n%s\nwant:\n%s", got, wantMarkdown)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, changes); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*Change
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 1 || decoded[0].Kind != Modified || decoded[0].ID != "db" || len(decoded[0].Fields) != 2 {
+		t.Errorf("got JSON %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteText(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "No changes.\n" {
+		t.Errorf("got %q for no changes", got)
+	}
+}
@@ -0,0 +1,126 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// childFields lists the JSON fields that hold nested entities. They are
+// compared separately and thus excluded from the fields of their parent.
+var childFields = []string{
+	"relationships",
+	"containers",
+	"components",
+	"codeElements",
+	"children",
+	"infrastructureNodes",
+	"containerInstances",
+	"softwareSystemInstances",
+}
+
+// entities returns the comparable entities of the design indexed by type and
+// identifier.
+func entities(d *mdl.Design) map[string]*entity {
+	res := make(map[string]*entity)
+	add := func(typ, id, name string, v any, omit ...string) {
+		res[typ+":"+id] = &entity{typ: typ, id: id, name: name, fields: fields(v, omit...)}
+	}
+	if d.Model != nil {
+		g := query.New(d)
+		for _, e := range g.Elements() {
+			add(e.Kind.String(), e.ID, e.Path, e.Value, childFields...)
+			for _, rel := range g.Outgoing(e.ID) {
+				add("Relationship", rel.ID, relationshipName(g, rel), rel)
+			}
+		}
+	}
+	if d.Views == nil {
+		return res
+	}
+	addView := func(typ string, v any, vp *mdl.ViewProps) {
+		e := &entity{typ: typ, id: vp.Key, name: vp.Key, fields: fields(v, "order")}
+		if vp.ElementViews != nil {
+			ids := make([]any, len(vp.ElementViews))
+			for i, ev := range vp.ElementViews {
+				ids[i] = ev.ID
+			}
+			e.fields["elements"] = ids
+		}
+		if vp.RelationshipViews != nil {
+			ids := make([]any, len(vp.RelationshipViews))
+			for i, rv := range vp.RelationshipViews {
+				ids[i] = rv.ID
+			}
+			e.fields["relationships"] = ids
+		}
+		res[typ+":"+vp.Key] = e
+	}
+	v := d.Views
+	for _, lv := range v.LandscapeViews {
+		addView("System Landscape View", lv, lv.ViewProps)
+	}
+	for _, cv := range v.ContextViews {
+		addView("System Context View", cv, cv.ViewProps)
+	}
+	for _, cv := range v.ContainerViews {
+		addView("Container View", cv, cv.ViewProps)
+	}
+	for _, cv := range v.ComponentViews {
+		addView("Component View", cv, cv.ViewProps)
+	}
+	for _, cv := range v.CodeViews {
+		addView("Code View", cv, cv.ViewProps)
+	}
+	for _, dv := range v.DynamicViews {
+		addView("Dynamic View", dv, dv.ViewProps)
+	}
+	for _, dv := range v.DeploymentViews {
+		addView("Deployment View", dv, dv.ViewProps)
+	}
+	for _, fv := range v.FilteredViews {
+		add("Filtered View", fv.Key, fv.Key, fv)
+	}
+	if v.Styles != nil {
+		for _, es := range v.Styles.Elements {
+			add("Element Style", es.Tag, es.Tag, es)
+		}
+		for _, rs := range v.Styles.Relationships {
+			add("Relationship Style", rs.Tag, rs.Tag, rs)
+		}
+	}
+	return res
+}
+
+// fields returns the JSON fields of v omitting the given fields.
+func fields(v any, omit ...string) map[string]any {
+	res := make(map[string]any)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return res
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return res
+	}
+	for _, f := range omit {
+		delete(res, f)
+	}
+	return res
+}
+
+// relationshipName returns a human friendly name for rel.
+func relationshipName(g *query.Graph, rel *mdl.Relationship) string {
+	src, dest := rel.SourceID, rel.DestinationID
+	if e := g.Element(src); e != nil {
+		src = e.Path
+	}
+	if e := g.Element(dest); e != nil {
+		dest = e.Path
+	}
+	if rel.Description == "" {
+		return fmt.Sprintf("%s -> %s", src, dest)
+	}
+	return fmt.Sprintf("%s -> %s: %s", src, dest, rel.Description)
+}
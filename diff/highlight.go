@@ -0,0 +1,335 @@
+package diff
+
+import (
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+const (
+	// TagPrefix is the prefix of the tags added by Highlight. Renderers
+	// ignore these tags when deriving the subtitle of an element from its
+	// last tag.
+	TagPrefix = "Diff "
+	// TagAdded is the tag added to the elements and relationships that only
+	// exist in the new design.
+	TagAdded = "Diff Added"
+	// TagRemoved is the tag added to the elements and relationships that only
+	// exist in the old design.
+	TagRemoved = "Diff Removed"
+	// TagModified is the tag added to the elements and relationships that
+	// were modified.
+	TagModified = "Diff Modified"
+)
+
+// Highlight updates design so that its views show the changes made since
+// base: added elements and relationships are tagged with TagAdded and
+// modified ones with TagModified. Removed elements and relationships are
+// copied from base into design, tagged with TagRemoved and added back to the
+// views that contained them. Highlight also adds the styles that render each
+// tag.
+//
+// The tags are appended last so that the styles that render them override the
+// styles of the other tags.
+func Highlight(base, design *mdl.Design) {
+	if design.Model == nil {
+		design.Model = &mdl.Model{}
+	}
+	changes := Compare(base, design)
+	restore(base, design, changes)
+
+	tags := tagFields(design)
+	for _, c := range changes {
+		t, ok := tags[c.ID]
+		if !ok {
+			continue
+		}
+		switch c.Kind {
+		case Added:
+			*t = appendTag(*t, TagAdded)
+		case Removed:
+			*t = appendTag(*t, TagRemoved)
+		case Modified:
+			*t = appendTag(*t, TagModified)
+		}
+	}
+	addStyles(design)
+}
+
+// restore copies the elements and relationships removed from base into design
+// and adds them back to the views of design that contain them in base.
+func restore(base, design *mdl.Design, changes []*Change) {
+	bg := query.New(base)
+	removed := make(map[string]bool)
+	var rels []*mdl.Relationship
+	for _, c := range changes {
+		if c.Kind != Removed {
+			continue
+		}
+		if c.Type == "Relationship" {
+			if rel := bg.Relationship(c.ID); rel != nil {
+				rels = append(rels, rel)
+			}
+			continue
+		}
+		e := bg.Element(c.ID)
+		if e == nil {
+			continue
+		}
+		var parentID string
+		if e.Parent != nil {
+			parentID = e.Parent.ID
+		}
+		// Changes are sorted by type then path so parents are restored
+		// before their children.
+		if restoreElement(design, e.Value, parentID) {
+			removed[c.ID] = true
+		}
+	}
+	values := elementValues(design)
+	for _, rel := range rels {
+		src, ok := values[rel.SourceID]
+		if !ok {
+			continue
+		}
+		if _, ok := values[rel.DestinationID]; !ok {
+			continue
+		}
+		cp := *rel
+		addRelationship(src, &cp)
+		removed[rel.ID] = true
+	}
+	if base.Views == nil || design.Views == nil {
+		return
+	}
+	baseViews := viewProps(base.Views)
+	for key, vp := range viewProps(design.Views) {
+		bvp, ok := baseViews[key]
+		if !ok {
+			continue
+		}
+		for _, ev := range bvp.ElementViews {
+			if removed[ev.ID] {
+				cp := *ev
+				vp.ElementViews = append(vp.ElementViews, &cp)
+			}
+		}
+		for _, rv := range bvp.RelationshipViews {
+			if removed[rv.ID] {
+				cp := *rv
+				vp.RelationshipViews = append(vp.RelationshipViews, &cp)
+			}
+		}
+	}
+}
+
+// restoreElement adds a copy of v, stripped of its children and
+// relationships, to design under the element with the given parent ID.
+// It returns false if the parent does not exist in design.
+func restoreElement(design *mdl.Design, v any, parentID string) bool {
+	m := design.Model
+	var parent any
+	if parentID != "" {
+		var ok bool
+		if parent, ok = elementValues(design)[parentID]; !ok {
+			return false
+		}
+	}
+	switch e := v.(type) {
+	case *mdl.Person:
+		cp := *e
+		cp.Relationships = nil
+		m.People = append(m.People, &cp)
+	case *mdl.SoftwareSystem:
+		cp := *e
+		cp.Relationships, cp.Containers = nil, nil
+		m.Systems = append(m.Systems, &cp)
+	case *mdl.Container:
+		s, ok := parent.(*mdl.SoftwareSystem)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships, cp.Components = nil, nil
+		s.Containers = append(s.Containers, &cp)
+	case *mdl.Component:
+		c, ok := parent.(*mdl.Container)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships, cp.CodeElements = nil, nil
+		c.Components = append(c.Components, &cp)
+	case *mdl.CodeElement:
+		c, ok := parent.(*mdl.Component)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships = nil
+		c.CodeElements = append(c.CodeElements, &cp)
+	case *mdl.DeploymentNode:
+		cp := *e
+		cp.Relationships, cp.Children, cp.InfrastructureNodes = nil, nil, nil
+		cp.ContainerInstances, cp.SoftwareSystemInstances = nil, nil
+		if parent == nil {
+			m.DeploymentNodes = append(m.DeploymentNodes, &cp)
+			return true
+		}
+		n, ok := parent.(*mdl.DeploymentNode)
+		if !ok {
+			return false
+		}
+		n.Children = append(n.Children, &cp)
+	case *mdl.InfrastructureNode:
+		n, ok := parent.(*mdl.DeploymentNode)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships = nil
+		n.InfrastructureNodes = append(n.InfrastructureNodes, &cp)
+	case *mdl.ContainerInstance:
+		n, ok := parent.(*mdl.DeploymentNode)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships = nil
+		n.ContainerInstances = append(n.ContainerInstances, &cp)
+	case *mdl.SoftwareSystemInstance:
+		n, ok := parent.(*mdl.DeploymentNode)
+		if !ok {
+			return false
+		}
+		cp := *e
+		cp.Relationships = nil
+		n.SoftwareSystemInstances = append(n.SoftwareSystemInstances, &cp)
+	default:
+		return false
+	}
+	return true
+}
+
+// addRelationship adds rel to the relationships of the element v.
+func addRelationship(v any, rel *mdl.Relationship) {
+	switch e := v.(type) {
+	case *mdl.Person:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.SoftwareSystem:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.Container:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.Component:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.CodeElement:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.DeploymentNode:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.InfrastructureNode:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.ContainerInstance:
+		e.Relationships = append(e.Relationships, rel)
+	case *mdl.SoftwareSystemInstance:
+		e.Relationships = append(e.Relationships, rel)
+	}
+}
+
+// elementValues returns the mdl values of the elements of design indexed by
+// ID.
+func elementValues(design *mdl.Design) map[string]any {
+	res := make(map[string]any)
+	for _, e := range query.New(design).Elements() {
+		res[e.ID] = e.Value
+	}
+	return res
+}
+
+// tagFields returns pointers to the tags of the elements and relationships of
+// design indexed by ID.
+func tagFields(design *mdl.Design) map[string]*string {
+	res := make(map[string]*string)
+	g := query.New(design)
+	for _, e := range g.Elements() {
+		switch v := e.Value.(type) {
+		case *mdl.Person:
+			res[v.ID] = &v.Tags
+		case *mdl.SoftwareSystem:
+			res[v.ID] = &v.Tags
+		case *mdl.Container:
+			res[v.ID] = &v.Tags
+		case *mdl.Component:
+			res[v.ID] = &v.Tags
+		case *mdl.CodeElement:
+			res[v.ID] = &v.Tags
+		case *mdl.DeploymentNode:
+			res[v.ID] = &v.Tags
+		case *mdl.InfrastructureNode:
+			res[v.ID] = &v.Tags
+		case *mdl.ContainerInstance:
+			res[v.ID] = &v.Tags
+		case *mdl.SoftwareSystemInstance:
+			res[v.ID] = &v.Tags
+		}
+		for _, rel := range g.Outgoing(e.ID) {
+			res[rel.ID] = &rel.Tags
+		}
+	}
+	return res
+}
+
+// appendTag appends tag to the comma separated list tags.
+func appendTag(tags, tag string) string {
+	if tags == "" {
+		return tag
+	}
+	return tags + "," + tag
+}
+
+// viewProps returns the properties of the views indexed by key.
+func viewProps(v *mdl.Views) map[string]*mdl.ViewProps {
+	res := make(map[string]*mdl.ViewProps)
+	for _, lv := range v.LandscapeViews {
+		res[lv.Key] = lv.ViewProps
+	}
+	for _, cv := range v.ContextViews {
+		res[cv.Key] = cv.ViewProps
+	}
+	for _, cv := range v.ContainerViews {
+		res[cv.Key] = cv.ViewProps
+	}
+	for _, cv := range v.ComponentViews {
+		res[cv.Key] = cv.ViewProps
+	}
+	for _, cv := range v.CodeViews {
+		res[cv.Key] = cv.ViewProps
+	}
+	for _, dv := range v.DynamicViews {
+		res[dv.Key] = dv.ViewProps
+	}
+	for _, dv := range v.DeploymentViews {
+		res[dv.Key] = dv.ViewProps
+	}
+	return res
+}
+
+// addStyles adds the styles used to render the highlight tags.
+func addStyles(design *mdl.Design) {
+	if design.Views == nil {
+		design.Views = &mdl.Views{}
+	}
+	if design.Views.Styles == nil {
+		design.Views.Styles = &mdl.Styles{}
+	}
+	s := design.Views.Styles
+	thick, dashed := 4, true
+	s.Elements = append(s.Elements,
+		&mdl.ElementStyle{Tag: TagAdded, Background: "#d4edda", Stroke: "#28a745", Color: "#155724", Border: mdl.BorderSolid},
+		&mdl.ElementStyle{Tag: TagRemoved, Background: "#f8f9fa", Stroke: "#adb5bd", Color: "#adb5bd", Border: mdl.BorderDashed},
+		&mdl.ElementStyle{Tag: TagModified, Stroke: "#fd7e14", Border: mdl.BorderSolid},
+	)
+	s.Relationships = append(s.Relationships,
+		&mdl.RelationshipStyle{Tag: TagAdded, Color: "#28a745", Thickness: &thick},
+		&mdl.RelationshipStyle{Tag: TagRemoved, Color: "#adb5bd", Dashed: &dashed},
+		&mdl.RelationshipStyle{Tag: TagModified, Color: "#fd7e14", Thickness: &thick},
+	)
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestHighlight(t *testing.T) {
+	base, design := testDesign(), testDesign()
+	s := design.Model.Systems[0]
+	s.Containers = []*mdl.Container{
+		{ID: "api", Name: "API", Technology: "Go, gRPC", Tags: "Element,Container"},
+		{ID: "cache", Name: "Cache", Tags: "Element,Container"},
+	}
+	vp := design.Views.ContainerViews[0].ViewProps
+	vp.ElementViews = []*mdl.ElementView{{ID: "api"}, {ID: "cache"}}
+	vp.RelationshipViews = nil
+
+	Highlight(base, design)
+
+	tags := make(map[string]string)
+	for _, c := range s.Containers {
+		tags[c.ID] = c.Tags
+	}
+	cases := map[string]string{
+		"api":   "Element,Container," + TagModified,
+		"cache": "Element,Container," + TagAdded,
+		"db":    "Element,Container,Legacy," + TagRemoved,
+	}
+	for id, want := range cases {
+		if got := tags[id]; got != want {
+			t.Errorf("%s: got tags %q, want %q", id, got, want)
+		}
+	}
+	rels := s.Containers[0].Relationships
+	if len(rels) != 1 || rels[0].ID != "r1" || rels[0].Tags != TagRemoved {
+		t.Errorf("got restored relationships %+v", rels)
+	}
+	if len(base.Model.Systems[0].Containers[0].Relationships[0].Tags) != 0 {
+		t.Errorf("base design modified")
+	}
+
+	var ids []string
+	for _, ev := range vp.ElementViews {
+		ids = append(ids, ev.ID)
+	}
+	if !equal(ids, []string{"api", "cache", "db"}) {
+		t.Errorf("got view elements %v", ids)
+	}
+	if len(vp.RelationshipViews) != 1 || vp.RelationshipViews[0].ID != "r1" {
+		t.Errorf("got view relationships %+v", vp.RelationshipViews)
+	}
+
+	styles := make(map[string]bool)
+	for _, es := range design.Views.Styles.Elements {
+		styles[es.Tag] = true
+	}
+	for _, tag := range []string{"Legacy", TagAdded, TagRemoved, TagModified} {
+		if !styles[tag] {
+			t.Errorf("missing element style %q", tag)
+		}
+	}
+}
+
+func TestHighlightStyle(t *testing.T) {
+	base, design := testDesign(), testDesign()
+	s := design.Model.Systems[0]
+	s.Containers = append(s.Containers, &mdl.Container{ID: "cache", Name: "Cache", Tags: "Element,Container,Cache"})
+	design.Views.Styles.Elements = append(design.Views.Styles.Elements,
+		&mdl.ElementStyle{Tag: "Container", Background: "#438dd5", Color: "#ffffff"},
+		&mdl.ElementStyle{Tag: "Cache", Background: "#85bbf0"},
+	)
+
+	Highlight(base, design)
+
+	added := design.Model.Systems[0].Containers[2]
+	var background, color string
+	// Renderers merge the styles of the tags in order, later tags override
+	// earlier ones.
+	for _, tag := range strings.Split(added.Tags, ",") {
+		for _, es := range design.Views.Styles.Elements {
+			if es.Tag != tag {
+				continue
+			}
+			if es.Background != "" {
+				background = es.Background
+			}
+			if es.Color != "" {
+				color = es.Color
+			}
+		}
+	}
+	if background != "#d4edda" || color != "#155724" {
+		t.Errorf("got added element background %q and color %q, want highlight style", background, color)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
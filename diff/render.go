@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteText writes a human readable version of the changes to w.
+func WriteText(w io.Writer, changes []*Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+	var b strings.Builder
+	for _, c := range changes {
+		fmt.Fprintf(&b, "%s %s %q\n", kindSymbols[c.Kind], c.Type, c.Name)
+		for _, f := range c.Fields {
+			fmt.Fprintf(&b, "    %s\n", describeField(f, false))
+		}
+	}
+	fmt.Fprintf(&b, "\n%s\n", Summary(changes))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes a Markdown version of the changes to w, suitable for
+// pull request comments.
+func WriteMarkdown(w io.Writer, changes []*Change) error {
+	var b strings.Builder
+	b.WriteString("### Design changes\n\n")
+	if len(changes) == 0 {
+		b.WriteString("No changes.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "%s\n\n", Summary(changes))
+	b.WriteString("| Change | Type | Name | Details |\n")
+	b.WriteString("|--------|------|------|---------|\n")
+	for _, c := range changes {
+		details := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			details[i] = describeField(f, true)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			kindTitles[c.Kind], c.Type, escapeCell(c.Name), escapeCell(strings.Join(details, "<br>")))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes a JSON version of the changes to w.
+func WriteJSON(w io.Writer, changes []*Change) error {
+	if changes == nil {
+		changes = []*Change{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(changes)
+}
+
+// Summary returns a one line summary of the changes, e.g. "2 added, 1
+// removed, 3 modified".
+func Summary(changes []*Change) string {
+	counts := make(map[ChangeKind]int)
+	for _, c := range changes {
+		counts[c.Kind]++
+	}
+	return fmt.Sprintf("%d added, %d removed, %d modified", counts[Added], counts[Removed], counts[Modified])
+}
+
+// kindSymbols maps change kinds to the prefix used in the text output.
+var kindSymbols = map[ChangeKind]string{Added: "+", Removed: "-", Modified: "~"}
+
+// kindTitles maps change kinds to the title used in the Markdown output.
+var kindTitles = map[ChangeKind]string{Added: "Added", Removed: "Removed", Modified: "Modified"}
+
+// describeField returns a one line description of f. Values are enclosed in
+// backticks if markdown is true, strings are quoted otherwise.
+func describeField(f *FieldChange, markdown bool) string {
+	if f.Added != nil || f.Removed != nil {
+		var parts []string
+		if len(f.Added) > 0 {
+			parts = append(parts, "added "+formatValues(f.Added, markdown))
+		}
+		if len(f.Removed) > 0 {
+			parts = append(parts, "removed "+formatValues(f.Removed, markdown))
+		}
+		return f.Field + ": " + strings.Join(parts, ", ")
+	}
+	arrow := " -> "
+	if markdown {
+		arrow = " → "
+	}
+	return f.Field + ": " + formatValue(f.Old, markdown) + arrow + formatValue(f.New, markdown)
+}
+
+// formatValues returns the comma separated list of the formatted values.
+func formatValues(vals []string, markdown bool) string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = formatValue(v, markdown)
+	}
+	return strings.Join(res, ", ")
+}
+
+// formatValue returns a string representation of a field value.
+func formatValue(v any, markdown bool) string {
+	var s string
+	switch val := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if !markdown {
+			return strconv.Quote(val)
+		}
+		s = val
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			b = []byte(fmt.Sprint(val))
+		}
+		s = string(b)
+	}
+	if markdown {
+		return "`" + s + "`"
+	}
+	return s
+}
+
+// escapeCell escapes characters that would break a Markdown table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
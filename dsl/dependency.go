@@ -0,0 +1,139 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+// MustNotUse declares an architectural constraint that forbids relationships
+// from the source elements to the destination elements. The constraint is
+// checked once the DSL has executed and each relationship that violates it
+// causes a validation error.
+//
+// MustNotUse must appear in a Design expression.
+//
+// MustNotUse takes two arguments: the source and the destination elements.
+// Each argument is either an element (person, software system, container,
+// component or code element), the path to an element (e.g.
+// "SoftwareSystem/Container") or the result of Tagged. An element selects
+// itself and all its descendants.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    var Backend = SoftwareSystem("Backend", func() {
+//	        Container("Database")
+//	    })
+//	    MustNotUse(Tagged("Frontend"), Tagged("Database"))
+//	    MustNotUse("Web", "Backend/Database")
+//	    MustNotUse(Tagged("External"), Backend)
+//	})
+func MustNotUse(source, destination any) {
+	addDependencyRule("MustNotUse", expr.DependencyRuleMustNotUse, source, destination)
+}
+
+// MayOnlyUse declares an architectural constraint that restricts the
+// elements that the source elements may use. Relationships from the source
+// elements to elements other than the destination elements or the source
+// elements themselves cause a validation error.
+//
+// MayOnlyUse must appear in a Design expression.
+//
+// MayOnlyUse takes the source elements as first argument followed by one or
+// more destinations. The arguments accept the same values as MustNotUse.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    SoftwareSystem("System", func() {
+//	        Container("API", func() {
+//	            Component("Handlers")
+//	        })
+//	        Container("Core")
+//	        Container("Database")
+//	    })
+//	    // Components of API may only use components of Core.
+//	    MayOnlyUse("System/API", "System/Core")
+//	})
+func MayOnlyUse(source any, destinations ...any) {
+	if len(destinations) == 0 {
+		eval.ReportError("MayOnlyUse: missing destination")
+		return
+	}
+	addDependencyRule("MayOnlyUse", expr.DependencyRuleMayOnlyUse, source, destinations...)
+}
+
+// Tagged selects the elements that have the given tag for use in MustNotUse
+// and MayOnlyUse. Only tags set with Tag are taken into account.
+//
+// Tagged may appear as argument of MustNotUse or MayOnlyUse.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    MustNotUse(Tagged("Frontend"), Tagged("Database"))
+//	})
+func Tagged(tag string) *expr.ElementSelector {
+	if tag == "" {
+		eval.ReportError("Tagged: tag cannot be empty")
+	}
+	return &expr.ElementSelector{Tag: tag}
+}
+
+// addDependencyRule adds a dependency rule of the given kind to the model.
+func addDependencyRule(name string, kind expr.DependencyRuleKind, source any, destinations ...any) {
+	d, ok := eval.Current().(*expr.Design)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	src := elementSelector(name, source)
+	if src == nil {
+		return
+	}
+	rule := &expr.DependencyRule{Kind: kind, Source: src}
+	for _, dest := range destinations {
+		sel := elementSelector(name, dest)
+		if sel == nil {
+			return
+		}
+		rule.Destinations = append(rule.Destinations, sel)
+	}
+	d.Model.DependencyRules = append(d.Model.DependencyRules, rule)
+}
+
+// elementSelector returns the selector corresponding to the given dependency
+// rule argument. It reports an error and returns nil if the argument is
+// invalid.
+func elementSelector(name string, arg any) *expr.ElementSelector {
+	switch a := arg.(type) {
+	case *expr.ElementSelector:
+		if a != nil {
+			return a
+		}
+	case string:
+		return &expr.ElementSelector{Path: a}
+	case *expr.Person:
+		if a != nil {
+			return &expr.ElementSelector{Element: a}
+		}
+	case *expr.SoftwareSystem:
+		if a != nil {
+			return &expr.ElementSelector{Element: a}
+		}
+	case *expr.Container:
+		if a != nil {
+			return &expr.ElementSelector{Element: a}
+		}
+	case *expr.Component:
+		if a != nil {
+			return &expr.ElementSelector{Element: a}
+		}
+	case *expr.CodeElement:
+		if a != nil {
+			return &expr.ElementSelector{Element: a}
+		}
+	}
+	eval.ReportError("%s: expected element, element path or Tagged, got %#v", name, arg)
+	return nil
+}
@@ -1,6 +1,7 @@
 package dsl
 
 import (
+	"slices"
 	"strings"
 
 	"goa.design/goa/v3/eval"
@@ -35,10 +36,172 @@ func DeploymentEnvironment(name string, dsl func()) {
 	eval.Execute(dsl, env)
 }
 
+// DeploymentTemplate defines a reusable deployment topology. The template
+// DSL may use any function that can appear in a DeploymentEnvironment
+// expression (DeploymentNode, DeploymentGroup etc.). The template is
+// instantiated in a deployment environment with UseDeploymentTemplate which
+// runs the template DSL in the context of the environment. The IDs of the
+// resulting deployment nodes and instances are thus computed as if they had
+// been defined directly in the environment.
+//
+// Deployment nodes that are defined both in a template and in the
+// environment that uses the template are merged: the description and
+// technology defined in the environment override the template values and the
+// environment DSL runs after the template DSL so that it may override the
+// number of instances, properties etc. or add deployment nodes and instances
+// that are specific to the environment.
+//
+// DeploymentTemplate may appear at the package level or in a Design
+// expression.
+//
+// DeploymentTemplate takes two arguments: the name of the template and the
+// DSL function describing the deployment nodes.
+//
+// Example:
+//
+//	var WebTopology = DeploymentTemplate("Web", func() {
+//	    DeploymentNode("AWS", "Amazon Web Services", func() {
+//	        DeploymentNode("Web", "Web servers", "EC2 t3.small", func() {
+//	            Instances("2")
+//	            ContainerInstance("System/API")
+//	        })
+//	    })
+//	})
+//
+//	var _ = Design(func() {
+//	    // ...
+//	    DeploymentEnvironment("Staging", func() {
+//	        UseDeploymentTemplate(WebTopology)
+//	    })
+//	    DeploymentEnvironment("Production", func() {
+//	        UseDeploymentTemplate(WebTopology)
+//	        // Override the technology and number of instances.
+//	        DeploymentNode("AWS", func() {
+//	            DeploymentNode("Web", "Web servers", "EC2 m5.large", func() {
+//	                Instances("10")
+//	                Prop("autoscaling", "true")
+//	            })
+//	        })
+//	    })
+//	})
+func DeploymentTemplate(name string, dsl func()) *expr.DeploymentTemplate {
+	if _, ok := eval.Current().(*expr.Design); !ok && eval.Current() != eval.Top {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if name == "" {
+		eval.ReportError("DeploymentTemplate: name cannot be empty")
+		return nil
+	}
+	return &expr.DeploymentTemplate{Name: name, DSLFunc: dsl}
+}
+
+// UseDeploymentTemplate instantiates the given deployment template in the
+// current deployment environment, see DeploymentTemplate.
+//
+// UseDeploymentTemplate must appear in a DeploymentEnvironment expression.
+//
+// UseDeploymentTemplate takes one argument: the template defined with
+// DeploymentTemplate.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    DeploymentEnvironment("Production", func() {
+//	        UseDeploymentTemplate(WebTopology)
+//	    })
+//	})
+func UseDeploymentTemplate(t *expr.DeploymentTemplate) {
+	if _, ok := eval.Current().(*expr.DeploymentEnvironment); !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if t == nil {
+		eval.ReportError("UseDeploymentTemplate: template cannot be nil")
+		return
+	}
+	if t.DSLFunc != nil {
+		t.DSLFunc()
+	}
+}
+
+// DeploymentGroup defines a deployment group or adds the current container or
+// software system instance to a deployment group. Deployment groups scope the
+// relationships that are implied between instances from the relationships
+// between the corresponding containers and software systems: instances that
+// belong to deployment groups are only related to instances that share at
+// least one of these groups. This makes it possible to model multi-region or
+// blue/green deployments without creating relationships between instances of
+// the different regions or colors. Instances that do not belong to any group
+// are only related to other instances that do not belong to any group.
+//
+// DeploymentGroup must appear in a DeploymentEnvironment, ContainerInstance or
+// SoftwareSystemInstance expression. When used in ContainerInstance or
+// SoftwareSystemInstance the group must be defined in the corresponding
+// deployment environment. DeploymentGroup may appear multiple times in the same
+// instance in which case the instance belongs to all the groups.
+//
+// DeploymentGroup takes one argument: the name of the group.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    DeploymentEnvironment("Production", func() {
+//	        DeploymentGroup("Blue")
+//	        DeploymentGroup("Green")
+//	        DeploymentNode("Server 1", func() {
+//	            ContainerInstance("System/API", func() {
+//	                DeploymentGroup("Blue")
+//	            })
+//	            ContainerInstance("System/Database", func() {
+//	                DeploymentGroup("Blue")
+//	            })
+//	        })
+//	        DeploymentNode("Server 2", func() {
+//	            ContainerInstance("System/API", func() {
+//	                DeploymentGroup("Green")
+//	            })
+//	            ContainerInstance("System/Database", func() {
+//	                DeploymentGroup("Green")
+//	            })
+//	        })
+//	    })
+//	})
+func DeploymentGroup(name string) *expr.DeploymentGroup {
+	if name == "" {
+		eval.ReportError("DeploymentGroup: name cannot be empty")
+		return nil
+	}
+	var (
+		env    string
+		groups *[]string
+	)
+	switch e := eval.Current().(type) {
+	case *expr.DeploymentEnvironment:
+		return expr.Root.Model.AddDeploymentGroup(&expr.DeploymentGroup{Name: name, Environment: e.Name})
+	case *expr.ContainerInstance:
+		env, groups = e.Environment, &e.DeploymentGroups
+	case *expr.SoftwareSystemInstance:
+		env, groups = e.Environment, &e.DeploymentGroups
+	default:
+		eval.IncompatibleDSL()
+		return nil
+	}
+	g := expr.Root.Model.DeploymentGroup(env, name)
+	if g == nil {
+		eval.ReportError("DeploymentGroup: no deployment group named %q in deployment environment %q", name, env)
+		return nil
+	}
+	if !slices.Contains(*groups, name) {
+		*groups = append(*groups, name)
+	}
+	return g
+}
+
 // DeploymentNode defines a deployment node. Deployment nodes can be
 // nested, so a deployment node can contain other deployment nodes.
-// A deployment node can also contain InfrastructureNode and
-// ContainerInstance elements.
+// A deployment node can also contain InfrastructureNode, ContainerInstance and
+// SoftwareSystemInstance elements.
 //
 // DeploymentNode must appear in a DeploymentEnvironment or DeploymentNode
 // expression.
@@ -289,6 +452,94 @@ func ContainerInstance(container any, dsl ...func()) *expr.ContainerInstance {
 	return d.AddContainerInstance(ci)
 }
 
+// SoftwareSystemInstance defines an instance of the specified software system
+// that is deployed on the parent deployment node. Software system instances
+// make it possible to represent the deployment of software systems that are
+// not broken down into containers, for example external systems.
+//
+// SoftwareSystemInstance must appear in a DeploymentNode expression.
+//
+// SoftwareSystemInstance takes one or two arguments: the first argument
+// identifies the software system by reference or by name. The second optional
+// argument is a func() that defines additional properties on the software
+// system instance including the instance ID.
+//
+// Usage:
+//
+//	SoftwareSystemInstance(SoftwareSystem)
+//
+//	SoftwareSystemInstance(SoftwareSystem, func())
+//
+//	SoftwareSystemInstance("<Software System>")
+//
+//	SoftwareSystemInstance("<Software System>", func())
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    var PaymentGateway = SoftwareSystem("Payment Gateway", func() {
+//	        External()
+//	    })
+//	    SoftwareSystem("Mainframe", func() {
+//	        External()
+//	    })
+//	    DeploymentEnvironment("Production", func() {
+//	        DeploymentNode("Payment Provider", func() {
+//	            SoftwareSystemInstance(PaymentGateway, func() {
+//	                Tag("saas")
+//	                HealthCheck("check", func() {
+//	                    URL("https://payments.example.com/health")
+//	                })
+//	            })
+//	        })
+//	        DeploymentNode("Data Center", func() {
+//	            // Using the name instead:
+//	            SoftwareSystemInstance("Mainframe")
+//	        })
+//	    })
+//	})
+func SoftwareSystemInstance(system any, dsl ...func()) *expr.SoftwareSystemInstance {
+	d, ok := eval.Current().(*expr.DeploymentNode)
+	if !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	var sys *expr.SoftwareSystem
+	switch s := system.(type) {
+	case *expr.SoftwareSystem:
+		sys = s
+	case string:
+		sys = expr.Root.Model.SoftwareSystem(s)
+		if sys == nil {
+			eval.ReportError("SoftwareSystemInstance: could not find software system %q", s)
+			return nil
+		}
+	default:
+		eval.ReportError("SoftwareSystemInstance: expected software system or software system name, got %T", system)
+		return nil
+	}
+	var f func()
+	if len(dsl) > 0 {
+		f = dsl[0]
+		if len(dsl) > 1 {
+			eval.ReportError("SoftwareSystemInstance: too many arguments")
+		}
+	}
+	si := &expr.SoftwareSystemInstance{
+		Element: &expr.Element{
+			Name:        sys.Name,
+			Description: sys.Description,
+			URL:         sys.URL,
+			DSLFunc:     f,
+		},
+		Parent:           d,
+		Environment:      d.Environment,
+		SoftwareSystemID: sys.ID,
+		InstanceID:       1,
+	}
+	return d.AddSoftwareSystemInstance(si)
+}
+
 // Instances sets the number of instances of the deployment node.
 //
 // Instances must appear in a DeploymentNode expression.
@@ -315,9 +566,11 @@ func Instances(n string) {
 	node.Instances = &n
 }
 
-// InstanceID sets the instance number or index of a container instance.
+// InstanceID sets the instance number or index of a container or software
+// system instance.
 //
-// InstanceID must appear in a ContainerInstance expression.
+// InstanceID must appear in a ContainerInstance or SoftwareSystemInstance
+// expression.
 //
 // InstanceID accepts a single argument which is the number.
 //
@@ -331,17 +584,21 @@ func Instances(n string) {
 //	    })
 //	})
 func InstanceID(n int) {
-	node, ok := eval.Current().(*expr.ContainerInstance)
-	if !ok {
+	switch e := eval.Current().(type) {
+	case *expr.ContainerInstance:
+		e.InstanceID = n
+	case *expr.SoftwareSystemInstance:
+		e.InstanceID = n
+	default:
 		eval.IncompatibleDSL()
-		return
 	}
-	node.InstanceID = n
 }
 
-// HealthCheck defines a HTTP-based health check for a container instance.
+// HealthCheck defines a HTTP-based health check for a container or software
+// system instance.
 //
-// HealthCheck must appear in a ContainerInstance expression.
+// HealthCheck must appear in a ContainerInstance or SoftwareSystemInstance
+// expression.
 //
 // HealthCheck accepts two arguments: the health check name and a function used
 // to define additional required properties.
@@ -361,14 +618,17 @@ func InstanceID(n int) {
 //	    })
 //	})
 func HealthCheck(name string, dsl func()) {
-	c, ok := eval.Current().(*expr.ContainerInstance)
-	if !ok {
+	hc := &expr.HealthCheck{Name: name}
+	switch e := eval.Current().(type) {
+	case *expr.ContainerInstance:
+		eval.Execute(dsl, hc)
+		e.HealthChecks = append(e.HealthChecks, hc)
+	case *expr.SoftwareSystemInstance:
+		eval.Execute(dsl, hc)
+		e.HealthChecks = append(e.HealthChecks, hc)
+	default:
 		eval.IncompatibleDSL()
-		return
 	}
-	hc := &expr.HealthCheck{Name: name}
-	eval.Execute(dsl, hc)
-	c.HealthChecks = append(c.HealthChecks, hc)
 }
 
 // Interval defines a health check polling interval in seconds.
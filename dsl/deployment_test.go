@@ -0,0 +1,104 @@
+package dsl
+
+import (
+	"testing"
+
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+func TestUseDeploymentTemplate(t *testing.T) {
+	var web = DeploymentTemplate("Web", func() {
+		DeploymentGroup("Blue")
+		DeploymentNode("AWS", func() {
+			DeploymentNode("Web", "Web servers", "EC2", func() {
+				Instances("2")
+				ContainerInstance("Shop/API", func() {
+					DeploymentGroup("Blue")
+				})
+				ContainerInstance("Shop/DB", func() {
+					DeploymentGroup("Blue")
+				})
+			})
+		})
+	})
+	d, err := runDSL(t, func() {
+		SoftwareSystem("Shop", func() {
+			Container("API", func() {
+				Uses("DB", "Reads")
+			})
+			Container("DB")
+		})
+		DeploymentEnvironment("Staging", func() {
+			UseDeploymentTemplate(web)
+		})
+		DeploymentEnvironment("Production", func() {
+			UseDeploymentTemplate(web)
+			DeploymentNode("AWS", func() {
+				DeploymentNode("Web", "Web servers", "EC2 large", func() {
+					Instances("10")
+				})
+			})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := d.Model.DeploymentNodes
+	if len(nodes) != 2 {
+		t.Fatalf("got %d deployment nodes, want 2", len(nodes))
+	}
+	want := []struct {
+		env, technology, instances string
+	}{
+		{"Staging", "EC2", "2"},
+		{"Production", "EC2 large", "10"},
+	}
+	ids := make(map[string]string)
+	for i, w := range want {
+		root := nodes[i]
+		if root.Environment != w.env || len(root.Children) != 1 {
+			t.Fatalf("node %d: got environment %q with %d children, want %q with 1", i, root.Environment, len(root.Children), w.env)
+		}
+		n := root.Children[0]
+		if n.Environment != w.env || n.Technology != w.technology || n.Instances == nil || *n.Instances != w.instances {
+			t.Errorf("%s: got node %q in %q with technology %q and %v instances, want technology %q and %s instances",
+				w.env, n.Name, n.Environment, n.Technology, n.Instances, w.technology, w.instances)
+		}
+		if len(n.ContainerInstances) != 2 {
+			t.Fatalf("%s: got %d container instances, want 2", w.env, len(n.ContainerInstances))
+		}
+		instances := make(map[string]*mdl.ContainerInstance)
+		for _, ci := range n.ContainerInstances {
+			instances[ci.ID] = ci
+		}
+		for _, id := range []string{root.ID, n.ID, n.ContainerInstances[0].ID, n.ContainerInstances[1].ID} {
+			if env, ok := ids[id]; ok {
+				t.Errorf("%s: ID %s already used in %s", w.env, id, env)
+			}
+			ids[id] = w.env
+		}
+		var rels int
+		for _, ci := range n.ContainerInstances {
+			rels += len(ci.Relationships)
+			if ci.Environment != w.env || len(ci.DeploymentGroups) != 1 || ci.DeploymentGroups[0] != "Blue" {
+				t.Errorf("%s: got instance in %q with groups %v, want groups [Blue]", w.env, ci.Environment, ci.DeploymentGroups)
+			}
+			for _, r := range ci.Relationships {
+				if _, ok := instances[r.DestinationID]; !ok {
+					t.Errorf("%s: relationship %q targets instance %s of another environment", w.env, r.Description, r.DestinationID)
+				}
+			}
+		}
+		if rels != 1 {
+			t.Errorf("%s: got %d relationships between instances, want 1", w.env, rels)
+		}
+	}
+
+	staging := expr.Root.Model.DeploymentGroup("Staging", "Blue")
+	production := expr.Root.Model.DeploymentGroup("Production", "Blue")
+	if staging == nil || production == nil || staging == production {
+		t.Errorf("got deployment groups %v and %v, want one group per environment", staging, production)
+	}
+}
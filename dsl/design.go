@@ -150,9 +150,9 @@ func AddImpliedRelationships() {
 // Tag defines a set of tags on the given element. Tags are used in views to
 // identify group of elements that should be rendered together for example.
 //
-// Tag may appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode, ContainerInstance, Uses, InteractsWith or
-// Delivers.
+// Tag may appear in Person, SoftwareSystem, Container, Component, CodeElement,
+// DeploymentNode, InfrastructureNode, ContainerInstance, SoftwareSystemInstance,
+// Uses, InteractsWith or Delivers.
 //
 // Tag accepts the set of tag values as argument. Tag may appear multiple times
 // in the same expression in which case the tags accumulate.
@@ -178,10 +178,12 @@ func Tag(first string, t ...string) {
 }
 
 // URL where more information about this element can be found.
-// Or URL of health check when used within a HealthCheck expression.
+// Or URL of health check when used within a HealthCheck expression or of the
+// relationship when used within a Uses, InteractsWith or Delivers expression.
 //
-// URL may appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode or HealthCheck.
+// URL may appear in Person, SoftwareSystem, Container, Component, CodeElement,
+// DeploymentNode, InfrastructureNode, HealthCheck, Uses, InteractsWith or
+// Delivers.
 //
 // URL takes exactly one argument: a valid URL.
 //
@@ -205,12 +207,16 @@ func URL(u string) {
 		e.URL = u
 	case *expr.Component:
 		e.URL = u
+	case *expr.CodeElement:
+		e.URL = u
 	case *expr.DeploymentNode:
 		e.URL = u
 	case *expr.InfrastructureNode:
 		e.URL = u
 	case *expr.HealthCheck:
 		e.URL = u
+	case *expr.Relationship:
+		e.URL = u
 	default:
 		eval.IncompatibleDSL()
 	}
@@ -243,7 +249,8 @@ func External() {
 // tooltip and can be used to store metadata (e.g. team name).
 //
 // Prop must appear in Person, SoftwareSystem, Container, Component,
-// DeploymentNode, InfrastructureNode or ContainerInstance.
+// CodeElement, DeploymentNode, InfrastructureNode, ContainerInstance or
+// SoftwareSystemInstance.
 //
 // Prop accepts two arguments: the name and value of a property.
 //
@@ -277,6 +284,11 @@ func Prop(name, value string) {
 			e.Properties = make(map[string]string)
 		}
 		props = e.Properties
+	case *expr.CodeElement:
+		if e.Properties == nil {
+			e.Properties = make(map[string]string)
+		}
+		props = e.Properties
 	case *expr.DeploymentNode:
 		if e.Properties == nil {
 			e.Properties = make(map[string]string)
@@ -292,6 +304,11 @@ func Prop(name, value string) {
 			e.Properties = make(map[string]string)
 		}
 		props = e.Properties
+	case *expr.SoftwareSystemInstance:
+		if e.Properties == nil {
+			e.Properties = make(map[string]string)
+		}
+		props = e.Properties
 	default:
 		eval.IncompatibleDSL()
 		return
@@ -20,47 +20,75 @@ general shape of the DSL is:
 	Design                              Design
 	├── Version                         └── Views
 	├── Enterprise                          ├── SystemLandscapeView
-	├── Person                              │   ├── Title
-	│   ├── Tag                             │   ├── AddDefault
-	│   ├── URL                             │   ├── Add
-	│   ├── External                        │   ├── AddAll
-	│   ├── Prop                            │   ├── AddNeighbors
-	│   ├── Uses                            │   ├── Link
-	│   └── InteractsWith                   │   ├── Remove
-	├── SoftwareSystem                      │   ├── RemoveTagged
-	│   ├── Tag                             │   ├── RemoveUnreachable
-	│   ├── URL                             │   ├── RemoveUnrelated
-	│   ├── External                        │   ├── Unlink
-	│   ├── Prop                            │   ├── AutoLayout
-	│   ├── Uses                            │   ├── AnimationStep
-	│   ├── Delivers                        │   ├── PaperSize
-	│   └── Container                       │   └── EnterpriseBoundaryVisible
-	│       ├── Tag                         ├── SystemContextView
-	│       ├── URL                         │   └──  ... (same as SystemLandsapeView)
-	│       ├── Prop                        ├── ContainerView
-	│       ├── Uses                        │   ├── AddContainers
-	│       ├── Delivers                    │   ├── AddInfluencers
-	│       └── Component                   │   ├── SystemBoundariesVisible
-	│           ├── Tag                     │   └── ... (same as SystemLandscapeView*)
-	│           ├── URL                     ├── ComponentView
-	│           ├── Prop                    │   ├── AddContainers
-	│           ├── Uses                    │   ├── AddComponents
-	│           └── Delivers                │   ├── ContainerBoundariesVisible
-	└── DeploymentEnvironment               │   └── ... (same as SystemLandscapeView*)
-	    ├── DeploymentNode                  ├── FilteredView
-	    │   ├── Tag                         │   ├── FilterTag
-	    │   ├── Instances                   │   └── Exclude
-	    │   ├── URL                         ├── DynamicView
-	    │   ├── Prop                        │   ├── Title
-	    │   └── DeploymentNode              │   ├── AutoLayout
-	    │       └── ...                     │   ├── PaperSize
-	    ├── InfrastructureNode              │   ├── Add
-	    │   ├── Tag                         ├── DeploymentView
-	    │   ├── URL                         │   └── ... (same as SystemLandscapeView*)
-	    │   └── Prop                        └── Style
-	    └── ContainerInstance                   ├── ElementStyle
-	        ├── Tag                             └── RelationshipStyle
+	├── Group                               │   ├── Title
+	├── Documentation                       │   ├── AddDefault
+	├── Decision                            │   ├── Add
+	├── Person                              │   ├── AddAll
+	│   ├── Tag                             │   ├── AddNeighbors
+	│   ├── URL                             │   ├── Link
+	│   ├── External                        │   ├── Remove
+	│   ├── Prop                            │   ├── RemoveTagged
+	│   ├── Uses                            │   ├── RemoveUnreachable
+	│   └── InteractsWith                   │   ├── RemoveUnrelated
+	├── SoftwareSystem                      │   ├── Unlink
+	│   ├── Tag                             │   ├── AutoLayout
+	│   ├── URL                             │   ├── AnimationStep
+	│   ├── External                        │   ├── PaperSize
+	│   ├── Prop                            │   └── EnterpriseBoundaryVisible
+	│   ├── Uses                            ├── SystemContextView
+	│   ├── Delivers                        │   └──  ... (same as SystemLandsapeView)
+	│   ├── Documentation                   ├── ContainerView
+	│   ├── Decision                        │   ├── AddContainers
+	│   ├── Group                           │   ├── AddInfluencers
+	│   └── Container                       │   ├── SystemBoundariesVisible
+	│       ├── Tag                         │   └── ... (same as SystemLandscapeView*)
+	│       ├── URL                         ├── ComponentView
+	│       ├── Prop                        │   ├── AddContainers
+	│       ├── Uses                        │   ├── AddComponents
+	│       ├── Delivers                    │   ├── ContainerBoundariesVisible
+	│       ├── Documentation               │   └── ... (same as SystemLandscapeView*)
+	│       ├── Decision                    ├── CodeView
+	│       ├── Group                       │   └── ... (same as SystemLandscapeView*)
+	│       └── Component                   ├── FilteredView
+	│           ├── Tag                     │   ├── FilterTag
+	│           ├── URL                     │   └── Exclude
+	│           ├── Prop                    ├── DynamicView
+	│           ├── Uses                    │   ├── Title
+	│           ├── Delivers                │   ├── AutoLayout
+	│           ├── Documentation           │   ├── PaperSize
+	│           ├── Decision                │   ├── Add
+	│           └── CodeElement             ├── DeploymentView
+	│               ├── Tag                 │   └── ... (same as SystemLandscapeView*)
+	│               ├── URL                 └── Style
+	│               ├── Prop                    ├── ElementStyle
+	│               └── Uses                    └── RelationshipStyle
+	├── MustNotUse
+	├── MayOnlyUse
+	├── DeploymentTemplate
+	│   └── ... (same as DeploymentEnvironment)
+	└── DeploymentEnvironment               (* minus EnterpriseBoundaryVisible)
+	    ├── UseDeploymentTemplate
+	    ├── DeploymentGroup
+	    ├── DeploymentNode
+	    │   ├── Tag
+	    │   ├── Instances
+	    │   ├── URL
+	    │   ├── Prop
+	    │   └── DeploymentNode
+	    │       └── ...
+	    ├── InfrastructureNode
+	    │   ├── Tag
+	    │   ├── URL
+	    │   └── Prop
+	    ├── ContainerInstance
+	    │   ├── Tag
+	    │   ├── HealthCheck
+	    │   ├── DeploymentGroup
+	    │   └── Prop
+	    └── SoftwareSystemInstance
+	        ├── Tag
 	        ├── HealthCheck
-	        └── Prop                        (* minus EnterpriseBoundaryVisible)
+	        ├── DeploymentGroup
+	        └── Prop
 */
 package dsl
@@ -0,0 +1,298 @@
+package dsl
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+type (
+	// DocFormatKind is the enum used to represent documentation formats.
+	DocFormatKind int
+
+	// DecisionStatusKind is the enum used to represent decision statuses.
+	DecisionStatusKind int
+)
+
+const (
+	// FormatMarkdown indicates the content uses Markdown.
+	FormatMarkdown DocFormatKind = iota + 1
+	// FormatASCIIDoc indicates the content uses AsciiDoc.
+	FormatASCIIDoc
+)
+
+const (
+	// DecisionProposed indicates the decision is proposed.
+	DecisionProposed DecisionStatusKind = iota + 1
+	// DecisionAccepted indicates the decision is accepted.
+	DecisionAccepted
+	// DecisionSuperseded indicates the decision is superseded by another.
+	DecisionSuperseded
+	// DecisionDeprecated indicates the decision is deprecated.
+	DecisionDeprecated
+	// DecisionRejected indicates the decision is rejected.
+	DecisionRejected
+)
+
+// Documentation adds a documentation section to the design or to an element.
+//
+// Documentation must appear in Design, SoftwareSystem, Container or Component.
+//
+// Documentation takes one to three arguments. The first argument is the
+// section title. The title may be followed by the section content and/or by a
+// function that defines the content using DocContent or DocContentFile and
+// optionally its format using DocFormat. The format defaults to Markdown.
+//
+// Usage:
+//
+//	Documentation("<title>", "<content>")
+//
+//	Documentation("<title>", func())
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    Documentation("Overview", "# Overview\n...")
+//	    SoftwareSystem("My System", func() {
+//	        Documentation("Context", func() {
+//	            DocContentFile("docs/context.adoc")
+//	            DocFormat(FormatASCIIDoc)
+//	        })
+//	    })
+//	})
+func Documentation(title string, args ...any) {
+	elem, ok := documentationScope()
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	s := &expr.DocumentationSection{Title: title, Element: elem}
+	var dsl func()
+	if len(args) > 0 {
+		switch a := args[0].(type) {
+		case string:
+			s.Content = a
+		case func():
+			dsl = a
+		default:
+			eval.InvalidArgError("content or DSL function", args[0])
+			return
+		}
+		if len(args) > 1 {
+			if dsl != nil {
+				eval.ReportError("Documentation: DSL function must be last argument")
+				return
+			}
+			if dsl, ok = args[1].(func()); !ok {
+				eval.InvalidArgError("DSL function", args[1])
+				return
+			}
+			if len(args) > 2 {
+				eval.ReportError("Documentation: too many arguments")
+				return
+			}
+		}
+	}
+	if dsl != nil && !eval.Execute(dsl, s) {
+		return
+	}
+	doc := expr.Root.Documentation
+	doc.Sections = append(doc.Sections, s)
+}
+
+// Decision adds an architecture decision record (ADR) to the design or to an
+// element.
+//
+// Decision must appear in Design, SoftwareSystem, Container or Component.
+//
+// Decision takes three arguments: a unique ID (e.g. "1"), the decision title
+// and a function that defines the content of the decision using DocContent
+// or DocContentFile, its format using DocFormat, its date using DecisionDate
+// and its status using DecisionStatus. The status defaults to
+// DecisionProposed.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    SoftwareSystem("My System", func() {
+//	        Decision("1", "Use PostgreSQL", func() {
+//	            DecisionDate("2024-01-15")
+//	            DecisionStatus(DecisionAccepted)
+//	            DocContentFile("docs/adr/0001-use-postgresql.md")
+//	        })
+//	    })
+//	})
+func Decision(id, title string, dsl func()) {
+	elem, ok := documentationScope()
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	if id == "" {
+		eval.ReportError("Decision: ID cannot be empty")
+		return
+	}
+	d := &expr.Decision{ID: id, Title: title, Element: elem}
+	if !eval.Execute(dsl, d) {
+		return
+	}
+	doc := expr.Root.Documentation
+	doc.Decisions = append(doc.Decisions, d)
+}
+
+// DocContent sets the content of a documentation section or decision.
+//
+// DocContent must appear in Documentation or Decision.
+//
+// DocContent takes exactly one argument: the content in Markdown or AsciiDoc.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    Documentation("Overview", func() {
+//	        DocContent("# Overview\nThis is the overview.")
+//	    })
+//	})
+func DocContent(content string) {
+	switch e := eval.Current().(type) {
+	case *expr.DocumentationSection:
+		e.Content = content
+	case *expr.Decision:
+		e.Content = content
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// DocContentFile loads the content of a documentation section or decision
+// from a file. Relative paths are relative to the directory containing the Go
+// file that calls DocContentFile, that is the model package. This directory is
+// retrieved from the debug information of the program evaluating the DSL so
+// relative paths cannot be used when the program is built with -trimpath (e.g.
+// when GOFLAGS contains -trimpath), use absolute paths instead.
+// DocContentFile also sets the format to AsciiDoc if the file extension is
+// ".adoc" or ".asciidoc" unless DocFormat is used to set it explicitly.
+//
+// DocContentFile must appear in Documentation or Decision.
+//
+// DocContentFile takes exactly one argument: the path to the file.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    Documentation("Overview", func() {
+//	        DocContentFile("docs/overview.md")
+//	    })
+//	})
+func DocContentFile(path string) {
+	if !filepath.IsAbs(path) {
+		_, file, _, ok := runtime.Caller(1)
+		if !ok || !filepath.IsAbs(file) {
+			eval.ReportError("DocContentFile: cannot resolve relative path %q, the program was built with -trimpath, use an absolute path", path)
+			return
+		}
+		path = filepath.Join(filepath.Dir(file), path)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		eval.ReportError("DocContentFile: %s", err.Error())
+		return
+	}
+	format := expr.FormatUndefined
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".adoc", ".asciidoc":
+		format = expr.FormatASCIIDoc
+	case ".md", ".markdown":
+		format = expr.FormatMarkdown
+	}
+	switch e := eval.Current().(type) {
+	case *expr.DocumentationSection:
+		e.Content = string(b)
+		if e.Format == expr.FormatUndefined {
+			e.Format = format
+		}
+	case *expr.Decision:
+		e.Content = string(b)
+		if e.Format == expr.FormatUndefined {
+			e.Format = format
+		}
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// DocFormat sets the format of the content of a documentation section or
+// decision. The default format is Markdown.
+//
+// DocFormat must appear in Documentation or Decision.
+//
+// DocFormat takes exactly one argument: FormatMarkdown or FormatASCIIDoc.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    Documentation("Overview", func() {
+//	        DocContent("= Overview")
+//	        DocFormat(FormatASCIIDoc)
+//	    })
+//	})
+func DocFormat(f DocFormatKind) {
+	switch e := eval.Current().(type) {
+	case *expr.DocumentationSection:
+		e.Format = expr.DocFormatKind(f)
+	case *expr.Decision:
+		e.Format = expr.DocFormatKind(f)
+	default:
+		eval.IncompatibleDSL()
+	}
+}
+
+// DecisionDate sets the date of a decision.
+//
+// DecisionDate must appear in Decision.
+//
+// DecisionDate takes exactly one argument: the date in ISO 8601 format (e.g.
+// "2024-01-15").
+func DecisionDate(date string) {
+	if d, ok := eval.Current().(*expr.Decision); ok {
+		d.Date = date
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// DecisionStatus sets the status of a decision.
+//
+// DecisionStatus must appear in Decision.
+//
+// DecisionStatus takes exactly one argument: one of DecisionProposed,
+// DecisionAccepted, DecisionSuperseded, DecisionDeprecated or
+// DecisionRejected.
+func DecisionStatus(s DecisionStatusKind) {
+	if d, ok := eval.Current().(*expr.Decision); ok {
+		d.Status = expr.DecisionStatusKind(s)
+		return
+	}
+	eval.IncompatibleDSL()
+}
+
+// documentationScope returns the element documentation defined in the current
+// expression applies to, nil if the documentation applies to the design. It
+// returns false if documentation cannot be defined in the current expression.
+func documentationScope() (*expr.Element, bool) {
+	switch e := eval.Current().(type) {
+	case *expr.Design:
+		return nil, true
+	case *expr.SoftwareSystem:
+		return e.Element, true
+	case *expr.Container:
+		return e.Element, true
+	case *expr.Component:
+		return e.Element, true
+	}
+	return nil, false
+}
@@ -0,0 +1,51 @@
+package dsl
+
+import (
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestDocumentation(t *testing.T) {
+	d, err := runDSL(t, func() {
+		Documentation("Overview", "# Overview")
+		SoftwareSystem("Shop", func() {
+			Documentation("Context", func() {
+				DocContentFile("testdata/context.adoc")
+			})
+			Documentation("Notes", func() {
+				DocContent("= Notes")
+				DocFormat(FormatASCIIDoc)
+			})
+			Decision("1", "Use Go", func() {
+				DecisionDate("2024-01-15")
+				DecisionStatus(DecisionAccepted)
+				DocContent("We use Go.")
+			})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sections := d.Documentation.Sections
+	if len(sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(sections))
+	}
+	shop := d.Model.Systems[0].ID
+	if s := sections[0]; s.Title != "Overview" || s.Content != "# Overview" || s.ElementID != "" {
+		t.Errorf("got section %+v", s)
+	}
+	if s := sections[1]; s.Content != "= Context\nThe context.\n" || s.Format != mdl.FormatASCIIDoc || s.ElementID != shop {
+		t.Errorf("got section %+v", s)
+	}
+	if s := sections[2]; s.Content != "= Notes" || s.Format != mdl.FormatASCIIDoc {
+		t.Errorf("got section %+v", s)
+	}
+	decisions := d.Documentation.Decisions
+	if len(decisions) != 1 {
+		t.Fatalf("got %d decisions, want 1", len(decisions))
+	}
+	if dec := decisions[0]; dec.Date != "2024-01-15" || dec.Status != mdl.DecisionAccepted || dec.Content != "We use Go." || dec.ElementID != shop {
+		t.Errorf("got decision %+v", dec)
+	}
+}
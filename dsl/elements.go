@@ -2,6 +2,7 @@ package dsl
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 
 	"goa.design/goa/v3/eval"
@@ -40,7 +41,8 @@ import (
 //	    })
 //	})
 func SoftwareSystem(name string, args ...any) *expr.SoftwareSystem {
-	w, ok := eval.Current().(*expr.Design)
+	scope, group := currentScope()
+	w, ok := scope.(*expr.Design)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -58,8 +60,10 @@ func SoftwareSystem(name string, args ...any) *expr.SoftwareSystem {
 			DSLFunc:     dsl,
 			Name:        name,
 			Description: description,
+			Group:       group,
 		},
 	}
+	s.File, s.Line = sourceLocation()
 	return w.Model.AddSystem(s)
 }
 
@@ -113,7 +117,8 @@ func SoftwareSystem(name string, args ...any) *expr.SoftwareSystem {
 //	    })
 //	})
 func Container(args ...any) *expr.Container {
-	system, ok := eval.Current().(*expr.SoftwareSystem)
+	scope, group := currentScope()
+	system, ok := scope.(*expr.SoftwareSystem)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -165,9 +170,11 @@ func Container(args ...any) *expr.Container {
 			Name:        name,
 			Description: description,
 			Technology:  technology,
+			Group:       group,
 		},
 		System: system,
 	}
+	c.File, c.Line = sourceLocation()
 	return system.AddContainer(c)
 }
 
@@ -210,7 +217,8 @@ func Container(args ...any) *expr.Container {
 //	    })
 //	})
 func Component(name string, args ...any) *expr.Component {
-	container, ok := eval.Current().(*expr.Container)
+	scope, group := currentScope()
+	container, ok := scope.(*expr.Container)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -228,13 +236,84 @@ func Component(name string, args ...any) *expr.Component {
 			Name:        name,
 			Description: description,
 			Technology:  technology,
+			Group:       group,
 			DSLFunc:     dsl,
 		},
 		Container: container,
 	}
+	c.File, c.Line = sourceLocation()
 	return container.AddComponent(c)
 }
 
+// CodeElement defines a code element (e.g. a class, interface, type or
+// package) that implements a component. Code elements make it possible to
+// describe the key types of a component and the dependencies between them.
+//
+// CodeElement must appear in a Component expression.
+//
+// CodeElement takes 1 to 4 arguments. The first argument is the code element
+// name. The name may be optionally followed by a description. If a description
+// is set then it may be followed by the technology details (e.g. "Go struct").
+// Finally CodeElement may take a func() as last argument to define additional
+// properties of the code element.
+//
+// The valid syntax for CodeElement is thus:
+//
+//	CodeElement("<name>")
+//
+//	CodeElement("<name>", "[description]")
+//
+//	CodeElement("<name>", "[description]", "[technology]")
+//
+//	CodeElement("<name>", func())
+//
+//	CodeElement("<name>", "[description]", func())
+//
+//	CodeElement("<name>", "[description]", "[technology]", func())
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    SoftwareSystem("My system", func() {
+//	        Container("My container", func() {
+//	            Component("My component", func() {
+//	                CodeElement("Service", "Implements the service", "Go struct", func() {
+//	                    Tag("core")
+//	                    URL("https://goa.design/mysystem/service.go")
+//	                    Uses("Repository", "Loads data with")
+//	                })
+//	                CodeElement("Repository", "Persists data", "Go interface")
+//	            })
+//	        })
+//	    })
+//	})
+func CodeElement(name string, args ...any) *expr.CodeElement {
+	component, ok := eval.Current().(*expr.Component)
+	if !ok {
+		eval.IncompatibleDSL()
+		return nil
+	}
+	if strings.Contains(name, "/") {
+		eval.ReportError("CodeElement: name cannot include slashes")
+	}
+	description, technology, dsl, err := parseElementArgs(args...)
+	if err != nil {
+		eval.ReportError("CodeElement: " + err.Error())
+		return nil
+	}
+	c := &expr.CodeElement{
+		Element: &expr.Element{
+			Name:        name,
+			Description: description,
+			Technology:  technology,
+			DSLFunc:     dsl,
+		},
+		Component: component,
+	}
+	c.File, c.Line = sourceLocation()
+	return component.AddCodeElement(c)
+}
+
 // parseElement is a helper function that parses the given element DSL
 // arguments. Accepted syntax are:
 //
@@ -288,3 +367,20 @@ func parseElementArgs(args ...any) (description, technology string, dsl func(),
 	}
 	return
 }
+
+// sourceLocation returns the file and line of the user code that called the
+// DSL function being executed.
+func sourceLocation() (string, int) {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "goa.design/model/dsl.") {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return "", 0
+		}
+	}
+}
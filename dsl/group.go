@@ -0,0 +1,63 @@
+package dsl
+
+import (
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+)
+
+// Group defines a named group of elements. Groups have no semantic meaning in
+// the model, they are rendered as dashed boundaries around their elements in
+// views.
+//
+// Group must appear in a Design, SoftwareSystem or Container expression.
+//
+// Group takes two arguments: the name of the group and a function that
+// defines the elements in the group. People and software systems may be
+// grouped in a Design expression, containers in a SoftwareSystem expression
+// and components in a Container expression. Groups cannot be nested.
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    Group("Customers", func() {
+//	        Person("Customer")
+//	        Person("Partner")
+//	    })
+//	    SoftwareSystem("System", func() {
+//	        Group("Backend", func() {
+//	            Container("API")
+//	            Container("Database")
+//	        })
+//	        Container("Web App", func() {
+//	            Group("Controllers", func() {
+//	                Component("Users Controller")
+//	            })
+//	        })
+//	    })
+//	})
+func Group(name string, dsl func()) {
+	switch eval.Current().(type) {
+	case *expr.Design, *expr.SoftwareSystem, *expr.Container:
+	case *expr.Group:
+		eval.ReportError("Group: groups cannot be nested")
+		return
+	default:
+		eval.IncompatibleDSL()
+		return
+	}
+	if name == "" {
+		eval.ReportError("Group: name cannot be empty")
+		return
+	}
+	eval.Execute(dsl, &expr.Group{Name: name, Scope: eval.Current()})
+}
+
+// currentScope returns the current expression and the name of the enclosing
+// group if any. Elements defined in a group are added to the expression the
+// group is defined in.
+func currentScope() (eval.Expression, string) {
+	if g, ok := eval.Current().(*expr.Group); ok {
+		return g.Scope, g.Name
+	}
+	return eval.Current(), ""
+}
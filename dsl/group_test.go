@@ -0,0 +1,126 @@
+package dsl
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"goa.design/goa/v3/eval"
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+// runDSL evaluates the design defined by fn and returns its JSON serializable
+// version.
+func runDSL(t *testing.T, fn func()) (*mdl.Design, error) {
+	t.Helper()
+	eval.Reset()
+	expr.Root = &expr.Design{Model: &expr.Model{}, Views: &expr.Views{}, Documentation: &expr.Documentation{}}
+	expr.Registry = make(map[string]any)
+	if err := eval.Register(expr.Root); err != nil {
+		t.Fatal(err)
+	}
+	Design("Test", fn)
+	return mdl.RunDSL()
+}
+
+func TestGroup(t *testing.T) {
+	d, err := runDSL(t, func() {
+		Group("Customers", func() {
+			Person("Customer")
+		})
+		Person("Staff")
+		Group("Internal", func() {
+			SoftwareSystem("Shop", func() {
+				Group("Backend", func() {
+					Container("API", func() {
+						Group("Controllers", func() {
+							Component("Users")
+						})
+						Component("Logger")
+					})
+				})
+				Container("Web")
+			})
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys := d.Model.Systems[0]
+	groups := map[string]string{
+		"Customer": d.Model.People[0].Group,
+		"Staff":    d.Model.People[1].Group,
+		"Shop":     sys.Group,
+		"API":      sys.Containers[0].Group,
+		"Web":      sys.Containers[1].Group,
+		"Users":    sys.Containers[0].Components[0].Group,
+		"Logger":   sys.Containers[0].Components[1].Group,
+	}
+	want := map[string]string{
+		"Customer": "Customers",
+		"Staff":    "",
+		"Shop":     "Internal",
+		"API":      "Backend",
+		"Web":      "",
+		"Users":    "Controllers",
+		"Logger":   "",
+	}
+	for k, v := range want {
+		if groups[k] != v {
+			t.Errorf("%s: got group %q, want %q", k, groups[k], v)
+		}
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range []string{"Customers", "Internal", "Backend", "Controllers"} {
+		if !strings.Contains(string(b), `"group":"`+g+`"`) {
+			t.Errorf("group %q not found in JSON %s", g, b)
+		}
+	}
+}
+
+func TestGroupInvalidScope(t *testing.T) {
+	cases := []struct {
+		Name string
+		DSL  func()
+		Want string
+	}{
+		{"person", func() {
+			Person("Customer", func() {
+				Group("Customers", func() {})
+			})
+		}, `in person "Customer"`},
+		{"component", func() {
+			SoftwareSystem("Shop", func() {
+				Container("API", func() {
+					Component("Users", func() {
+						Group("Controllers", func() {})
+					})
+				})
+			})
+		}, `in component "Users"`},
+		{"nested", func() {
+			Group("Outer", func() {
+				Group("Inner", func() {})
+			})
+		}, "groups cannot be nested"},
+		{"empty name", func() {
+			Group("", func() {})
+		}, "name cannot be empty"},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := runDSL(t, c.DSL)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), c.Want) {
+				t.Errorf("got error %q, want it to contain %q", err, c.Want)
+			}
+		})
+	}
+}
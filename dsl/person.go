@@ -38,7 +38,8 @@ import (
 //	    })
 //	})
 func Person(name string, args ...any) *expr.Person {
-	w, ok := eval.Current().(*expr.Design)
+	scope, group := currentScope()
+	w, ok := scope.(*expr.Design)
 	if !ok {
 		eval.IncompatibleDSL()
 		return nil
@@ -76,8 +77,10 @@ func Person(name string, args ...any) *expr.Person {
 		Element: &expr.Element{
 			Name:        name,
 			Description: desc,
+			Group:       group,
 			DSLFunc:     dsl,
 		},
 	}
+	p.File, p.Line = sourceLocation()
 	return w.Model.AddPerson(p)
 }
@@ -19,7 +19,8 @@ const (
 
 // Uses adds a uni-directional relationship between two elements.
 //
-// Uses may appear in Person, SoftwareSystem, Container or Component.
+// Uses may appear in Person, SoftwareSystem, Container, Component or
+// CodeElement.
 //
 // Uses takes 2 to 5 arguments. The first argument identifies the target of the
 // relationship. The following argument is a short description for the
@@ -29,10 +30,11 @@ const (
 // define additional properties on the relationship.
 //
 // The target of the relationship is identified by providing an element (person,
-// software system, container or component) or the path of an element. The path
-// consists of the element name if a top level element (person or software
-// system) or if the element is in scope (container in the same software system
-// as the source or component in the same container as the source). When the
+// software system, container, component or code element) or the path of an
+// element. The path consists of the element name if a top level element (person
+// or software system) or if the element is in scope (container in the same
+// software system as the source, component in the same container as the source
+// or code element in the same component as the source). When the
 // element is not in scope the path specifies the parent element name followed
 // by a slash and the element name. If the parent itself is not in scope (i.e. a
 // component that is a child of a different software system than the source)
@@ -59,11 +61,14 @@ const (
 //
 // Where Element is one of:
 //
-//   - Person, SoftwareSystem, Container or Component
-//   - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>" or "<SoftwareSystem>/<Container>/<Component>"
+//   - Person, SoftwareSystem, Container, Component or CodeElement
+//   - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>", "<SoftwareSystem>/<Container>/<Component>"
+//     or "<SoftwareSystem>/<Container>/<Component>/<CodeElement>"
 //   - "<Container>" (if container is a sibling of the source)
 //   - "<Component>" (if component is a sibling of the source)
+//   - "<CodeElement>" (if code element is a sibling of the source)
 //   - "<Container>/<Component>" (if container is a sibling of the source)
+//   - "<Component>/<CodeElement>" (if component is a sibling of the source)
 //
 // Example:
 //
@@ -95,6 +100,8 @@ func Uses(element any, description string, args ...any) {
 		src = e.Element
 	case *expr.Component:
 		src = e.Element
+	case *expr.CodeElement:
+		src = e.Element
 	default:
 		eval.IncompatibleDSL()
 		return
@@ -305,6 +312,7 @@ func addrel(src *expr.Element, dest any, desc string, args ...any) error {
 		Technology:       technology,
 		InteractionStyle: expr.InteractionStyleKind(style),
 	}
+	rel.File, rel.Line = sourceLocation()
 	// Note: we need to check the types explicitly below because
 	// (*expr.Person)(nil) != (expr.ElementHolder)(nil) for example.
 	switch d := dest.(type) {
@@ -328,6 +336,11 @@ func addrel(src *expr.Element, dest any, desc string, args ...any) error {
 			return fmt.Errorf("Component reference is nil")
 		}
 		rel.Destination = d.Element
+	case *expr.CodeElement:
+		if d == nil {
+			return fmt.Errorf("CodeElement reference is nil")
+		}
+		rel.Destination = d.Element
 	case string:
 		rel.DestinationPath = d
 	default:
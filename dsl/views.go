@@ -421,6 +421,94 @@ func ComponentView(container any, key string, args ...any) {
 	vs.ComponentViews = append(vs.ComponentViews, v)
 }
 
+// CodeView defines a code view. Code views show the code elements of a
+// component and the relationships between them.
+//
+// CodeView must appear in Views.
+//
+// CodeView accepts 3 to 4 arguments: the first argument is the component or the
+// path to the component being described by the code view. The path consists of
+// the name of the software system that contains the component followed by a
+// slash, the name of the container, another slash and the name of the
+// component. The following argument is a unique key which can be used to
+// reference the view when creating a filtered views. Next is an optional
+// description. The last argument must be a function describing the properties
+// of the view.
+//
+// Usage:
+//
+//	CodeView(Component, "<key>", func())
+//
+//	CodeView("<Software System>/<Container>/<Component>", "<key>", func())
+//
+//	CodeView(Component, "<key>", "[description]", func())
+//
+//	CodeView("<Software System>/<Container>/<Component>", "<key>", "[description]", func())
+//
+// Example:
+//
+//	var _ = Design(func() {
+//	    SoftwareSystem("Software System", "My software system.", func() {
+//	        Container("Container", func() {
+//	            Component("Component", func() {
+//	                CodeElement("Service", func() {
+//	                    Uses("Repository", "Loads data with")
+//	                })
+//	                CodeElement("Repository")
+//	            })
+//	        })
+//	    })
+//	    Views(func() {
+//	        CodeView("Software System/Container/Component", "code", "An overview diagram.", func() {
+//	            Title("Overview of component")
+//	            AddAll()
+//	            AutoLayout(RankTopBottom)
+//	        })
+//	    })
+//	})
+func CodeView(component any, key string, args ...any) {
+	vs, ok := eval.Current().(*expr.Views)
+	if !ok {
+		eval.IncompatibleDSL()
+		return
+	}
+	var c *expr.Component
+	switch a := component.(type) {
+	case *expr.Component:
+		c = a
+	case string:
+		cmp, err := expr.Root.Model.FindElement(nil, a)
+		if err != nil {
+			eval.ReportError("CodeView: " + err.Error())
+			return
+		}
+		c, ok = cmp.(*expr.Component)
+		if !ok {
+			eval.ReportError("CodeView: %q is not a component", a)
+			return
+		}
+	default:
+		eval.InvalidArgError("component or component path", component)
+		return
+	}
+	description, dsl, err := parseView(args...)
+	if err != nil {
+		eval.ReportError("CodeView: " + err.Error())
+		return
+	}
+	v := &expr.CodeView{
+		ViewProps: &expr.ViewProps{
+			Key:         key,
+			Description: description,
+		},
+		ComponentID: c.GetElement().ID,
+	}
+	if dsl != nil {
+		eval.Execute(dsl, v)
+	}
+	vs.CodeViews = append(vs.CodeViews, v)
+}
+
 // FilteredView defines a filtered view on top of the specified view.
 // The base key specifies the key of the System Landscape, System
 // Context, Container, or Component view on which this filtered view
@@ -737,14 +825,14 @@ func Title(t string) {
 // Add adds a person or an element to a view.
 //
 // Add must appear in SystemLandscapeView, SystemContextView, ContainerView,
-// ComponentView or DeploymentView.
+// ComponentView, CodeView or DeploymentView.
 //
 // Usage depends on the view Add is used in. In all cases Add supports an
 // optional DSL function as last argument that can be used to specify rendering
 // details.
 //
-//   - In SystemLandscapeView, SystemContextView, ContainerView and ComponentView
-//     Add accepts a person, a software system or their names.
+//   - In SystemLandscapeView, SystemContextView, ContainerView, ComponentView
+//     and CodeView Add accepts a person, a software system or their names.
 //
 //   - In ContainerView Add also accepts a container or the path to a container.
 //     The path to a container is either its name if it is a child of the the
@@ -757,6 +845,11 @@ func Title(t string) {
 //     by a slash, the name of the parent container, another slash and the name of
 //     the component.
 //
+//   - In CodeView Add also accepts a code element or the path to a code element.
+//     The path of a code element is either its name if it is a child of the
+//     component the code view is for or the path of the parent component
+//     followed by a slash and the name of the code element.
+//
 //   - In DeploymentView, Add accepts a deployment node, a container instance, an
 //     infrastructure node or their paths. The path is constructed by appending the
 //     top level deployment node name with the child deployment name recursively
@@ -764,7 +857,8 @@ func Title(t string) {
 //     container instance. The names must be separated by slashes in the path. For
 //     container instances the path may end with the container instance ID.
 //
-// Usage (SystemLandscapeView, SystemContextView, ContainerView and ComponentView):
+// Usage (SystemLandscapeView, SystemContextView, ContainerView, ComponentView
+// and CodeView):
 //
 //	Add(Person|"<Person>"[, func()])
 //
@@ -789,6 +883,16 @@ func Title(t string) {
 //	                                       // container the component view is for.
 //	Add("<Software System/Container/Component>"[, func()])
 //
+// Additionally for CodeView:
+//
+//	Add(CodeElement[, func()])
+//
+//	Add("<CodeElement>"[, func()]) // If code element is a child of the component
+//	                               // the code view is for.
+//	Add("<Component/CodeElement>"[, func()]) // if component is a sibling of the
+//	                                         // component the code view is for.
+//	Add("<Software System/Container/Component/CodeElement>"[, func()])
+//
 // Usage (DeploymentView):
 //
 //	Add(DeploymentNode[, func()])
@@ -860,7 +964,7 @@ func Add(element any, dsl ...func()) {
 		view expr.View
 	)
 	switch v := eval.Current().(type) {
-	case *expr.LandscapeView, *expr.ContextView, *expr.ContainerView, *expr.ComponentView:
+	case *expr.LandscapeView, *expr.ContextView, *expr.ContainerView, *expr.ComponentView, *expr.CodeView:
 		view = v.(expr.View)
 		eh, err = findViewElement(view, element)
 	case *expr.DeploymentView:
@@ -2215,6 +2319,9 @@ func findViewElement(view expr.View, element any) (expr.ElementHolder, error) {
 		scope := expr.Registry[v.ContainerID].(expr.ElementHolder)
 		res, err := expr.Root.Model.FindElement(scope, name)
 		return res, err
+	case *expr.CodeView:
+		scope := expr.Registry[v.ComponentID].(expr.ElementHolder)
+		return expr.Root.Model.FindElement(scope, name)
 	case *expr.DeploymentView:
 		return findDeploymentViewElement(v.Environment, name)
 	case *expr.DynamicView:
@@ -2233,6 +2340,7 @@ func findViewElement(view expr.View, element any) (expr.ElementHolder, error) {
 //   - DeploymentNode: returns the given deployment node.
 //   - InfrastructureNode: returns the given infrastructure node.
 //   - ContainerInstance: returns the given container instance.
+//   - SoftwareSystemInstance: returns the given software system instance.
 //   - "DeploymentNode/.../Child DeploymentNode": returns the deployment node with
 //     the given path (top level deployemnt node name to child deployment node name
 //     all separated with slashes).
@@ -2241,12 +2349,18 @@ func findViewElement(view expr.View, element any) (expr.ElementHolder, error) {
 //   - "DeploymentNode/.../Container:InstanceID": returns the container instance
 //     in the given deployment node path and with the given container name and
 //     instance ID.
+//   - "DeploymentNode/.../SoftwareSystem:InstanceID": returns the software
+//     system instance in the given deployment node path and with the given
+//     software system name and instance ID.
 func findDeploymentViewElement(env, path string) (expr.ElementHolder, error) {
 	elems := strings.Split(path, "/")
 	parent := expr.Root.Model.DeploymentNode(env, elems[0])
 	if parent == nil {
 		return nil, fmt.Errorf("no top level deployment node named %q", path)
 	}
+	if len(elems) == 1 {
+		return parent, nil
+	}
 	cid := 1
 	if len(elems) > 2 {
 		last := elems[len(elems)-1]
@@ -2271,6 +2385,9 @@ func findDeploymentViewElement(env, path string) (expr.ElementHolder, error) {
 	if ci := parent.ContainerInstanceByName(name, cid); ci != nil {
 		return ci, nil
 	}
+	if si := parent.SoftwareSystemInstanceByName(name, cid); si != nil {
+		return si, nil
+	}
 	return nil, fmt.Errorf("could not find %q in path %q", name, path)
 }
 
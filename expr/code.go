@@ -0,0 +1,44 @@
+package expr
+
+import (
+	"fmt"
+)
+
+type (
+	// CodeElement represents a code element (e.g. a class, interface, type or
+	// package) that implements a component.
+	CodeElement struct {
+		*Element
+		Component *Component
+	}
+
+	// CodeElements is a slice of code elements that can be easily converted
+	// into a slice of ElementHolder.
+	CodeElements []*CodeElement
+)
+
+// CodeElementTags lists the tags that are added to all code elements.
+var CodeElementTags = []string{"Element", "Code"}
+
+// EvalName returns the generic expression name used in error messages.
+func (c *CodeElement) EvalName() string {
+	if c.Name == "" {
+		return "unnamed code element"
+	}
+	return fmt.Sprintf("code element %q", c.Name)
+}
+
+// Finalize adds the 'Code' tag ands finalizes relationships.
+func (c *CodeElement) Finalize() {
+	c.PrefixTags(CodeElementTags...)
+	c.Element.Finalize()
+}
+
+// Elements returns a slice of ElementHolder that contains the elements of c.
+func (cs CodeElements) Elements() []ElementHolder {
+	res := make([]ElementHolder, len(cs))
+	for i, cc := range cs {
+		res[i] = cc
+	}
+	return res
+}
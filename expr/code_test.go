@@ -0,0 +1,118 @@
+package expr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCodeElementEvalName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{name: "", want: "unnamed code element"},
+		{name: "foo", want: `code element "foo"`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			code := CodeElement{
+				Element: &Element{
+					Name: tt.name,
+				},
+			}
+			if got := code.EvalName(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeElementFinalize(t *testing.T) {
+	t.Parallel()
+	code := CodeElement{
+		Element: &Element{
+			Name: "foo",
+			Tags: "foo",
+		},
+	}
+	code.Finalize()
+	if got, want := code.Tags, "Element,Code,foo"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCodeElementsElements(t *testing.T) {
+	t.Parallel()
+	codes := CodeElements{
+		{Element: &Element{Name: "foo"}},
+		{Element: &Element{Name: "bar"}},
+	}
+	if got := codes.Elements(); len(got) != len(codes) {
+		t.Errorf("got %d, want %d", len(got), len(codes))
+	}
+}
+
+func TestComponentCodeElement(t *testing.T) {
+	component := Component{
+		CodeElements: CodeElements{
+			{Element: &Element{Name: "foo"}},
+			{Element: &Element{Name: "bar"}},
+		},
+	}
+	tests := []struct {
+		name string
+		want *CodeElement
+	}{
+		{name: "thud", want: nil},
+		{name: "bar", want: component.CodeElements[1]},
+	}
+	for i, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			if got := component.CodeElement(tt.name); got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddCodeElement(t *testing.T) {
+	component := Component{Element: &Element{ID: "1", Name: "component"}}
+	codeFoo := CodeElement{
+		Element:   &Element{Name: "foo", DSLFunc: func() {}},
+		Component: &component,
+	}
+	codeBar := CodeElement{
+		Element:   &Element{Name: "bar"},
+		Component: &component,
+	}
+	codeFooPlus := CodeElement{
+		Element:   &Element{Name: "foo", Description: "Description", Technology: "Go struct", DSLFunc: func() {}},
+		Component: &component,
+	}
+
+	seq := []struct {
+		code2Add *CodeElement
+		want     *CodeElement
+	}{
+		{code2Add: &codeFoo, want: &codeFoo},
+		{code2Add: &codeBar, want: &codeBar},
+		{code2Add: &codeFooPlus, want: &codeFoo},
+	}
+	for i, tt := range seq {
+		if got := component.AddCodeElement(tt.code2Add); got != tt.want {
+			t.Errorf("%d: got %#v, want %#v", i, got.Element, tt.want.Element)
+		}
+	}
+	if len(component.CodeElements) != 2 {
+		t.Errorf("got %d code elements, want 2", len(component.CodeElements))
+	}
+	if codeFoo.Technology != "Go struct" {
+		t.Errorf("got technology %q, want %q", codeFoo.Technology, "Go struct")
+	}
+	if codeFoo.ID == "" || codeFoo.ID == codeBar.ID {
+		t.Errorf("invalid code element IDs %q and %q", codeFoo.ID, codeBar.ID)
+	}
+}
@@ -2,13 +2,15 @@ package expr
 
 import (
 	"fmt"
+	"strings"
 )
 
 type (
 	// Component represents a component.
 	Component struct {
 		*Element
-		Container *Container
+		Container    *Container
+		CodeElements CodeElements
 	}
 
 	// Components is a slice of components that can be easily converted into
@@ -41,3 +43,45 @@ func (cs Components) Elements() []ElementHolder {
 	}
 	return res
 }
+
+// CodeElement returns the code element with the given name if any, nil
+// otherwise.
+func (c *Component) CodeElement(name string) *CodeElement {
+	for _, ce := range c.CodeElements {
+		if ce.Name == name {
+			return ce
+		}
+	}
+	return nil
+}
+
+// AddCodeElement adds the given code element to the component. If there is
+// already a code element with the given name then AddCodeElement merges both
+// definitions. The merge algorithm:
+//
+//   - overrides the description, technology and URL if provided,
+//   - merges any new tag or propery into the existing tags and properties,
+//
+// AddCodeElement returns the new or merged code element.
+func (c *Component) AddCodeElement(ce *CodeElement) *CodeElement {
+	existing := c.CodeElement(ce.Name)
+	if existing == nil {
+		Identify(ce)
+		c.CodeElements = append(c.CodeElements, ce)
+		return ce
+	}
+	if ce.Description != "" {
+		existing.Description = ce.Description
+	}
+	if ce.Technology != "" {
+		existing.Technology = ce.Technology
+	}
+	if ce.URL != "" {
+		existing.URL = ce.URL
+	}
+	existing.MergeTags(strings.Split(ce.Tags, ",")...)
+	if olddsl := existing.DSLFunc; olddsl != nil {
+		existing.DSLFunc = func() { olddsl(); ce.DSLFunc() }
+	}
+	return existing
+}
@@ -78,6 +78,9 @@ func (c *Container) AddComponent(cmp *Component) *Component {
 	if cmp.URL != "" {
 		existing.URL = cmp.URL
 	}
+	if cmp.Group != "" {
+		existing.Group = cmp.Group
+	}
 	existing.MergeTags(strings.Split(cmp.Tags, ",")...)
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); cmp.DSLFunc() }
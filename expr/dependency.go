@@ -0,0 +1,158 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// DependencyRule describes an architectural constraint on the
+	// relationships between the elements of the model.
+	DependencyRule struct {
+		// Kind is the kind of constraint.
+		Kind DependencyRuleKind
+		// Source selects the elements whose relationships are constrained.
+		Source *ElementSelector
+		// Destinations selects the elements that the source elements must
+		// not use or may only use depending on Kind.
+		Destinations []*ElementSelector
+	}
+
+	// ElementSelector selects elements of the model. It selects either the
+	// elements that have a given tag or an element and all its descendants.
+	ElementSelector struct {
+		// Tag is the tag of the selected elements if any.
+		Tag string
+		// Path is the path to the selected element if the element was
+		// given by name, it is resolved once the DSL has executed.
+		Path string
+		// Element is the selected element if any.
+		Element ElementHolder
+	}
+
+	// DependencyRuleKind is the enum for possible dependency rule kinds.
+	DependencyRuleKind int
+)
+
+const (
+	// DependencyRuleUndefined means no kind specified in design.
+	DependencyRuleUndefined DependencyRuleKind = iota
+	// DependencyRuleMustNotUse forbids relationships from the source
+	// elements to the destination elements.
+	DependencyRuleMustNotUse
+	// DependencyRuleMayOnlyUse forbids relationships from the source
+	// elements to elements other than the destination elements or the
+	// source elements themselves.
+	DependencyRuleMayOnlyUse
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (r *DependencyRule) EvalName() string {
+	return "dependency rule " + r.String()
+}
+
+// String returns a human friendly description of the rule, e.g. "elements
+// tagged "Frontend" must not use elements tagged "Database"".
+func (r *DependencyRule) String() string {
+	dests := make([]string, len(r.Destinations))
+	for i, d := range r.Destinations {
+		dests[i] = d.String()
+	}
+	verb := "must not use"
+	if r.Kind == DependencyRuleMayOnlyUse {
+		verb = "may only use"
+	}
+	return fmt.Sprintf("%s %s %s", r.Source, verb, strings.Join(dests, " or "))
+}
+
+// Resolve looks up the elements selected by path.
+func (r *DependencyRule) Resolve(m *Model) error {
+	for _, s := range append([]*ElementSelector{r.Source}, r.Destinations...) {
+		if s.Element != nil || s.Path == "" {
+			continue
+		}
+		eh, err := m.FindElement(nil, s.Path)
+		if err != nil {
+			return err
+		}
+		s.Element = eh
+	}
+	return nil
+}
+
+// Violated returns true if rel violates the rule.
+func (r *DependencyRule) Violated(rel *Relationship) bool {
+	src, ok := Registry[rel.Source.ID].(ElementHolder)
+	if !ok || !isModelElement(src) || !r.Source.Matches(src) {
+		return false
+	}
+	dest, ok := Registry[rel.Destination.ID].(ElementHolder)
+	if !ok || !isModelElement(dest) {
+		return false
+	}
+	var matched bool
+	for _, d := range r.Destinations {
+		if d.Matches(dest) {
+			matched = true
+			break
+		}
+	}
+	if r.Kind == DependencyRuleMustNotUse {
+		return matched
+	}
+	return !matched && !r.Source.Matches(dest)
+}
+
+// Matches returns true if the selector selects eh. Only tags set explicitly
+// in the DSL are taken into account as default tags are added after
+// validation.
+func (s *ElementSelector) Matches(eh ElementHolder) bool {
+	if s.Tag != "" {
+		for _, t := range strings.Split(eh.GetElement().Tags, ",") {
+			if strings.TrimSpace(t) == s.Tag {
+				return true
+			}
+		}
+		return false
+	}
+	if s.Element == nil {
+		return false
+	}
+	for e := eh; e != nil; e = Parent(e) {
+		if e.GetElement().ID == s.Element.GetElement().ID {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a human friendly description of the selected elements.
+func (s *ElementSelector) String() string {
+	if s.Tag != "" {
+		return fmt.Sprintf("elements tagged %q", s.Tag)
+	}
+	if s.Element != nil {
+		return fmt.Sprintf("%q", elementPath(s.Element))
+	}
+	return fmt.Sprintf("%q", s.Path)
+}
+
+// isModelElement returns true if eh is a person, software system,
+// container, component or code element.
+func isModelElement(eh ElementHolder) bool {
+	switch eh.(type) {
+	case *Person, *SoftwareSystem, *Container, *Component, *CodeElement:
+		return true
+	}
+	return false
+}
+
+// elementPath returns the path of the given model element, e.g.
+// "System/Container".
+func elementPath(eh ElementHolder) string {
+	path := eh.GetElement().Name
+	for p := Parent(eh); p != nil; p = Parent(p) {
+		path = p.GetElement().Name + "/" + path
+	}
+	return path
+}
@@ -0,0 +1,73 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestDependencyRuleViolated(t *testing.T) {
+	web := &SoftwareSystem{Element: &Element{Name: "DepWeb", Tags: "Frontend"}}
+	Identify(web)
+	backend := &SoftwareSystem{Element: &Element{Name: "DepBackend"}}
+	Identify(backend)
+	api := &Container{Element: &Element{Name: "API"}, System: backend}
+	Identify(api)
+	handlers := &Component{Element: &Element{Name: "Handlers"}, Container: api}
+	Identify(handlers)
+	views := &Component{Element: &Element{Name: "Views"}, Container: api}
+	Identify(views)
+	core := &Container{Element: &Element{Name: "Core"}, System: backend}
+	Identify(core)
+	db := &Container{Element: &Element{Name: "DB", Tags: "Storage,Database"}, System: backend}
+	Identify(db)
+	backend.Containers = Containers{api, core, db}
+	api.Components = Components{handlers, views}
+	m := &Model{Systems: SoftwareSystems{web, backend}}
+
+	rel := func(src, dest ElementHolder) *Relationship {
+		return &Relationship{Source: src.GetElement(), Destination: dest.GetElement()}
+	}
+	mustNotUse := &DependencyRule{
+		Kind:         DependencyRuleMustNotUse,
+		Source:       &ElementSelector{Tag: "Frontend"},
+		Destinations: []*ElementSelector{{Tag: "Database"}},
+	}
+	mayOnlyUse := &DependencyRule{
+		Kind:         DependencyRuleMayOnlyUse,
+		Source:       &ElementSelector{Path: "DepBackend/API"},
+		Destinations: []*ElementSelector{{Element: core}},
+	}
+	if err := mayOnlyUse.Resolve(m); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if mayOnlyUse.Source.Element != api {
+		t.Fatalf("got source %v, want API", mayOnlyUse.Source.Element)
+	}
+
+	tests := []struct {
+		name string
+		rule *DependencyRule
+		rel  *Relationship
+		want bool
+	}{
+		{"tagged to tagged", mustNotUse, rel(web, db), true},
+		{"tagged to other", mustNotUse, rel(web, core), false},
+		{"untagged to tagged", mustNotUse, rel(core, db), false},
+		{"descendant to allowed", mayOnlyUse, rel(handlers, core), false},
+		{"descendant to sibling", mayOnlyUse, rel(handlers, views), false},
+		{"descendant to other", mayOnlyUse, rel(handlers, db), true},
+		{"other source", mayOnlyUse, rel(core, db), false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Violated(tt.rel); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got, want := mayOnlyUse.String(), `"DepBackend/API" may only use "DepBackend/Core"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	unknown := &DependencyRule{Source: &ElementSelector{Path: "Unknown/Container"}}
+	if err := unknown.Resolve(m); err == nil {
+		t.Errorf("expected error for unknown element")
+	}
+}
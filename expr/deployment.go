@@ -2,6 +2,7 @@ package expr
 
 import (
 	"fmt"
+	"slices"
 )
 
 type (
@@ -11,15 +12,35 @@ type (
 		Name string
 	}
 
+	// DeploymentTemplate describes a reusable set of deployment nodes that can
+	// be instantiated in multiple deployment environments.
+	DeploymentTemplate struct {
+		// Name of template.
+		Name string
+		// DSLFunc is the DSL defining the template deployment nodes.
+		DSLFunc func()
+	}
+
+	// DeploymentGroup describes a deployment group. Deployment groups scope
+	// the relationships implied between container and software system
+	// instances: instances are only related if they share a group.
+	DeploymentGroup struct {
+		// Name of group.
+		Name string
+		// Environment the group belongs to.
+		Environment string
+	}
+
 	// DeploymentNode describes a single deployment node.
 	DeploymentNode struct {
 		*Element
-		Parent              *DeploymentNode
-		Children            []*DeploymentNode
-		InfrastructureNodes []*InfrastructureNode
-		ContainerInstances  []*ContainerInstance
-		Instances           *string
-		Environment         string
+		Parent                  *DeploymentNode
+		Children                []*DeploymentNode
+		InfrastructureNodes     []*InfrastructureNode
+		ContainerInstances      []*ContainerInstance
+		SoftwareSystemInstances []*SoftwareSystemInstance
+		Instances               *string
+		Environment             string
 	}
 
 	// InfrastructureNode describes an infrastructure node.
@@ -40,6 +61,24 @@ type (
 		ContainerID  string
 		InstanceID   int
 		Environment  string
+		// DeploymentGroups lists the names of the deployment groups the
+		// instance belongs to.
+		DeploymentGroups []string
+	}
+
+	// SoftwareSystemInstance describes an instance of a software system.
+	SoftwareSystemInstance struct {
+		// cheating a bit: a SoftwareSystemInstance does not have a name,
+		// description, technology or URL.
+		*Element
+		Parent           *DeploymentNode
+		HealthChecks     []*HealthCheck
+		SoftwareSystemID string
+		InstanceID       int
+		Environment      string
+		// DeploymentGroups lists the names of the deployment groups the
+		// instance belongs to.
+		DeploymentGroups []string
 	}
 
 	// InfrastructureNodes is a slice of infrastructure nodes that can be
@@ -50,6 +89,10 @@ type (
 	// converted into a slice of ElementHolder.
 	ContainerInstances []*ContainerInstance
 
+	// SoftwareSystemInstances is a slice of software system instances that
+	// can be converted into a slice of ElementHolder.
+	SoftwareSystemInstances []*SoftwareSystemInstance
+
 	// HealthCheck is a HTTP-based health check.
 	HealthCheck struct {
 		Name     string
@@ -72,11 +115,25 @@ var InfrastructureNodeTags = []string{"Element", "Infrastructure Node"}
 // container instances.
 var ContainerInstanceTags = []string{"Container Instance"}
 
+// SoftwareSystemInstanceTags list the tags that are automatically added to all
+// software system instances.
+var SoftwareSystemInstanceTags = []string{"Software System Instance"}
+
 // EvalName returns the generic expression name used in error messages.
 func (d *DeploymentEnvironment) EvalName() string {
 	return fmt.Sprintf("deployment environment %q", d.Name)
 }
 
+// EvalName returns the generic expression name used in error messages.
+func (t *DeploymentTemplate) EvalName() string {
+	return fmt.Sprintf("deployment template %q", t.Name)
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (g *DeploymentGroup) EvalName() string {
+	return fmt.Sprintf("deployment group %q", g.Name)
+}
+
 // EvalName returns the generic expression name used in error messages.
 func (d *DeploymentNode) EvalName() string { return fmt.Sprintf("deployment node %q", d.Name) }
 
@@ -135,6 +192,28 @@ func (d *DeploymentNode) ContainerInstanceByName(name string, instanceID int) *C
 	return nil
 }
 
+// SoftwareSystemInstanceByID returns the software system instance for the
+// given software system with the given instance ID if any, nil otherwise.
+func (d *DeploymentNode) SoftwareSystemInstanceByID(softwareSystemID string, instanceID int) *SoftwareSystemInstance {
+	for _, si := range d.SoftwareSystemInstances {
+		if si.SoftwareSystemID == softwareSystemID && si.InstanceID == instanceID {
+			return si
+		}
+	}
+	return nil
+}
+
+// SoftwareSystemInstanceByName returns the software system instance for the
+// given software system with the given name if any, nil otherwise.
+func (d *DeploymentNode) SoftwareSystemInstanceByName(name string, instanceID int) *SoftwareSystemInstance {
+	for _, si := range d.SoftwareSystemInstances {
+		if si.Name == name && si.InstanceID == instanceID {
+			return si
+		}
+	}
+	return nil
+}
+
 // AddChild adds the given child deployment node to the parent. If
 // there is already a deployment node with the given name then AddChild
 // merges both definitions. The merge algorithm:
@@ -142,8 +221,8 @@ func (d *DeploymentNode) ContainerInstanceByName(name string, instanceID int) *C
 //   - overrides the description, technology and URL if provided,
 //   - merges any new tag or propery into the existing tags and properties,
 //   - merges any new child deployment node into the existing children,
-//   - merges any new container instance or infrastructure nodes into existing
-//     ones.
+//   - merges any new container instance, software system instance or
+//     infrastructure nodes into existing ones.
 //
 // AddChild returns the new or merged deployment node.
 func (d *DeploymentNode) AddChild(n *DeploymentNode) *DeploymentNode {
@@ -159,9 +238,7 @@ func (d *DeploymentNode) AddChild(n *DeploymentNode) *DeploymentNode {
 	if n.Technology != "" {
 		existing.Technology = n.Technology
 	}
-	if olddsl := existing.DSLFunc; olddsl != nil {
-		existing.DSLFunc = func() { olddsl(); n.DSLFunc() }
-	}
+	existing.DSLFunc = mergeDSL(existing.DSLFunc, n.DSLFunc)
 	return existing
 }
 
@@ -186,9 +263,7 @@ func (d *DeploymentNode) AddInfrastructureNode(n *InfrastructureNode) *Infrastru
 	if n.Technology != "" {
 		existing.Technology = n.Technology
 	}
-	if olddsl := existing.DSLFunc; olddsl != nil {
-		existing.DSLFunc = func() { olddsl(); n.DSLFunc() }
-	}
+	existing.DSLFunc = mergeDSL(existing.DSLFunc, n.DSLFunc)
 	return existing
 }
 
@@ -199,7 +274,8 @@ func (d *DeploymentNode) AddInfrastructureNode(n *InfrastructureNode) *Infrastru
 //
 //   - overrides the description, technology and URL if provided,
 //   - merges any new tag or propery into the existing tags and properties,
-//   - merges any new health check into the existing health checks.
+//   - merges any new health check into the existing health checks,
+//   - merges any new deployment group into the existing deployment groups.
 //
 // AddContainerInstance returns the new or merged container instance.
 func (d *DeploymentNode) AddContainerInstance(ci *ContainerInstance) *ContainerInstance {
@@ -217,9 +293,39 @@ func (d *DeploymentNode) AddContainerInstance(ci *ContainerInstance) *ContainerI
 		existing.Technology = ci.Technology
 	}
 	existing.HealthChecks = append(existing.HealthChecks, ci.HealthChecks...)
-	if olddsl := existing.DSLFunc; olddsl != nil {
-		existing.DSLFunc = func() { olddsl(); ci.DSLFunc() }
+	existing.DeploymentGroups = mergeGroups(existing.DeploymentGroups, ci.DeploymentGroups)
+	existing.DSLFunc = mergeDSL(existing.DSLFunc, ci.DSLFunc)
+	return existing
+}
+
+// AddSoftwareSystemInstance adds the given software system instance to the
+// deployment node. If there is already a software system instance with the
+// given software system and instance ID then AddSoftwareSystemInstance merges
+// both definitions. The merge algorithm:
+//
+//   - overrides the description, technology and URL if provided,
+//   - merges any new tag or propery into the existing tags and properties,
+//   - merges any new health check into the existing health checks,
+//   - merges any new deployment group into the existing deployment groups.
+//
+// AddSoftwareSystemInstance returns the new or merged software system
+// instance.
+func (d *DeploymentNode) AddSoftwareSystemInstance(si *SoftwareSystemInstance) *SoftwareSystemInstance {
+	existing := d.SoftwareSystemInstanceByID(si.SoftwareSystemID, si.InstanceID)
+	if existing == nil {
+		Identify(si)
+		d.SoftwareSystemInstances = append(d.SoftwareSystemInstances, si)
+		return si
+	}
+	if si.Description != "" {
+		existing.Description = si.Description
+	}
+	if si.Technology != "" {
+		existing.Technology = si.Technology
 	}
+	existing.HealthChecks = append(existing.HealthChecks, si.HealthChecks...)
+	existing.DeploymentGroups = mergeGroups(existing.DeploymentGroups, si.DeploymentGroups)
+	existing.DSLFunc = mergeDSL(existing.DSLFunc, si.DSLFunc)
 	return existing
 }
 
@@ -249,6 +355,21 @@ func (ci *ContainerInstance) Finalize() {
 	ci.Element.Finalize()
 }
 
+// EvalName returns the generic expression name used in error messages.
+func (si *SoftwareSystemInstance) EvalName() string {
+	n := "unknown software system"
+	if ss, ok := Registry[si.SoftwareSystemID]; ok {
+		n = fmt.Sprintf("software system %q", ss.(*SoftwareSystem).Name)
+	}
+	return fmt.Sprintf("instance %d of %s", si.InstanceID, n)
+}
+
+// Finalize adds the "Software System Instance" tag if not present.
+func (si *SoftwareSystemInstance) Finalize() {
+	si.PrefixTags(SoftwareSystemInstanceTags...)
+	si.Element.Finalize()
+}
+
 // EvalName returns the generic expression name used in error messages.
 func (hc *HealthCheck) EvalName() string {
 	return fmt.Sprintf("health check %q", hc.Name)
@@ -271,3 +392,79 @@ func (ci ContainerInstances) Elements() []ElementHolder {
 	}
 	return res
 }
+
+// Elements returns a slice of ElementHolder that contains the elements of si.
+func (si SoftwareSystemInstances) Elements() []ElementHolder {
+	res := make([]ElementHolder, len(si))
+	for i, ss := range si {
+		res[i] = ss
+	}
+	return res
+}
+
+// instanceOf returns the element and the ID of the container or software
+// system that eh is an instance of. It returns nil and the empty string if eh
+// is neither a container instance nor a software system instance.
+func instanceOf(eh any) (*Element, string) {
+	switch i := eh.(type) {
+	case *ContainerInstance:
+		return i.Element, i.ContainerID
+	case *SoftwareSystemInstance:
+		return i.Element, i.SoftwareSystemID
+	}
+	return nil, ""
+}
+
+// shareDeploymentGroup returns true if the container or software system
+// instances a and b belong to a common deployment group. Instances that do
+// not belong to any group are in an implicit default group.
+func shareDeploymentGroup(a, b any) bool {
+	envA, groupsA := deploymentGroups(a)
+	envB, groupsB := deploymentGroups(b)
+	if len(groupsA) == 0 && len(groupsB) == 0 {
+		return true
+	}
+	if envA != envB {
+		return false
+	}
+	for _, g := range groupsA {
+		if slices.Contains(groupsB, g) {
+			return true
+		}
+	}
+	return false
+}
+
+// deploymentGroups returns the environment and deployment groups of the given
+// container or software system instance.
+func deploymentGroups(eh any) (string, []string) {
+	switch i := eh.(type) {
+	case *ContainerInstance:
+		return i.Environment, i.DeploymentGroups
+	case *SoftwareSystemInstance:
+		return i.Environment, i.DeploymentGroups
+	}
+	return "", nil
+}
+
+// mergeGroups appends the groups in b that are not already in a to a.
+func mergeGroups(a, b []string) []string {
+	for _, g := range b {
+		if !slices.Contains(a, g) {
+			a = append(a, g)
+		}
+	}
+	return a
+}
+
+// mergeDSL returns a function that runs existing then added, either may be
+// nil.
+func mergeDSL(existing, added func()) func() {
+	if existing == nil {
+		return added
+	}
+	if added == nil {
+		return existing
+	}
+	return func() { existing(); added() }
+}
@@ -0,0 +1,178 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestSoftwareSystemInstanceFinalize(t *testing.T) {
+	t.Parallel()
+	si := SoftwareSystemInstance{
+		Element: &Element{
+			Name: "foo",
+			Tags: "foo",
+		},
+	}
+	si.Finalize()
+	if got, want := si.Tags, "Software System Instance,foo"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestSoftwareSystemInstancesElements(t *testing.T) {
+	t.Parallel()
+	sis := SoftwareSystemInstances{
+		{Element: &Element{Name: "foo"}},
+		{Element: &Element{Name: "bar"}},
+	}
+	if got := sis.Elements(); len(got) != len(sis) {
+		t.Errorf("got %d, want %d", len(got), len(sis))
+	}
+}
+
+func TestAddSoftwareSystemInstance(t *testing.T) {
+	node := DeploymentNode{Element: &Element{ID: "1", Name: "node"}, Environment: "Production"}
+	siFoo := SoftwareSystemInstance{
+		Element:          &Element{Name: "foo", DSLFunc: func() {}},
+		Parent:           &node,
+		SoftwareSystemID: "foo",
+		InstanceID:       1,
+	}
+	siFoo2 := SoftwareSystemInstance{
+		Element:          &Element{Name: "foo"},
+		Parent:           &node,
+		SoftwareSystemID: "foo",
+		InstanceID:       2,
+	}
+	siFooPlus := SoftwareSystemInstance{
+		Element:          &Element{Name: "foo", Description: "Description", DSLFunc: func() {}},
+		Parent:           &node,
+		SoftwareSystemID: "foo",
+		InstanceID:       1,
+		HealthChecks:     []*HealthCheck{{Name: "check"}},
+	}
+
+	seq := []struct {
+		si2Add *SoftwareSystemInstance
+		want   *SoftwareSystemInstance
+	}{
+		{si2Add: &siFoo, want: &siFoo},
+		{si2Add: &siFoo2, want: &siFoo2},
+		{si2Add: &siFooPlus, want: &siFoo},
+	}
+	for i, tt := range seq {
+		if got := node.AddSoftwareSystemInstance(tt.si2Add); got != tt.want {
+			t.Errorf("%d: got %#v, want %#v", i, got.Element, tt.want.Element)
+		}
+	}
+	if len(node.SoftwareSystemInstances) != 2 {
+		t.Errorf("got %d software system instances, want 2", len(node.SoftwareSystemInstances))
+	}
+	if siFoo.Description != "Description" {
+		t.Errorf("got description %q, want %q", siFoo.Description, "Description")
+	}
+	if len(siFoo.HealthChecks) != 1 {
+		t.Errorf("got %d health checks, want 1", len(siFoo.HealthChecks))
+	}
+	if got := node.SoftwareSystemInstanceByName("foo", 2); got != &siFoo2 {
+		t.Errorf("got %#v, want %#v", got, &siFoo2)
+	}
+}
+
+func TestModelFinalizeSoftwareSystemInstances(t *testing.T) {
+	gateway := &SoftwareSystem{Element: &Element{Name: "Gateway"}}
+	Identify(gateway)
+	system := &SoftwareSystem{Element: &Element{Name: "Shop"}}
+	Identify(system)
+	api := &Container{Element: &Element{Name: "API"}, System: system}
+	Identify(api)
+	rel := &Relationship{Source: api.Element, Destination: gateway.Element, Description: "Charges cards"}
+	Identify(rel)
+	api.Relationships = []*Relationship{rel}
+
+	node := &DeploymentNode{Element: &Element{Name: "Cloud"}, Environment: "Production"}
+	Identify(node)
+	ci := node.AddContainerInstance(&ContainerInstance{
+		Element:     &Element{Name: "API"},
+		Parent:      node,
+		ContainerID: api.ID,
+		Environment: "Production",
+		InstanceID:  1,
+	})
+	si := node.AddSoftwareSystemInstance(&SoftwareSystemInstance{
+		Element:          &Element{Name: "Gateway"},
+		Parent:           node,
+		SoftwareSystemID: gateway.ID,
+		Environment:      "Production",
+		InstanceID:       1,
+	})
+
+	m := &Model{}
+	m.Finalize()
+
+	var found bool
+	for _, r := range ci.Relationships {
+		if r.Destination.ID == si.ID && r.LinkedRelationshipID == rel.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("missing implied relationship between container instance and software system instance")
+	}
+}
+
+func TestShareDeploymentGroup(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+		want bool
+	}{
+		{"no groups", &ContainerInstance{Environment: "prod"}, &ContainerInstance{Environment: "prod"}, true},
+		{"same group", &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"blue"}}, &SoftwareSystemInstance{Environment: "prod", DeploymentGroups: []string{"blue"}}, true},
+		{"one common group", &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"blue", "green"}}, &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"green"}}, true},
+		{"different groups", &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"blue"}}, &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"green"}}, false},
+		{"one without group", &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"blue"}}, &ContainerInstance{Environment: "prod"}, false},
+		{"different environments", &ContainerInstance{Environment: "prod", DeploymentGroups: []string{"blue"}}, &ContainerInstance{Environment: "dev", DeploymentGroups: []string{"blue"}}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := shareDeploymentGroup(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddChildOverride(t *testing.T) {
+	var calls []string
+	parent := DeploymentNode{Element: &Element{ID: "1", Name: "parent"}, Environment: "Production"}
+	template := DeploymentNode{
+		Element:     &Element{Name: "web", Technology: "t3.small", DSLFunc: func() { calls = append(calls, "template") }},
+		Parent:      &parent,
+		Environment: "Production",
+	}
+	override := DeploymentNode{
+		Element:     &Element{Name: "web", Technology: "m5.large", DSLFunc: func() { calls = append(calls, "override") }},
+		Parent:      &parent,
+		Environment: "Production",
+	}
+	noDSL := DeploymentNode{
+		Element:     &Element{Name: "web"},
+		Parent:      &parent,
+		Environment: "Production",
+	}
+
+	parent.AddChild(&template)
+	parent.AddChild(&override)
+	if got := parent.AddChild(&noDSL); got != &template {
+		t.Fatalf("got %#v, want %#v", got, &template)
+	}
+	if template.Technology != "m5.large" {
+		t.Errorf("got technology %q, want %q", template.Technology, "m5.large")
+	}
+	template.DSLFunc()
+	if len(calls) != 2 || calls[0] != "template" || calls[1] != "override" {
+		t.Errorf("got calls %v, want [template override]", calls)
+	}
+}
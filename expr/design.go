@@ -11,16 +11,17 @@ import (
 type (
 	// Design contains the AST generated from the DSL.
 	Design struct {
-		Name        string
-		Description string
-		Version     string
-		Model       *Model
-		Views       *Views
+		Name          string
+		Description   string
+		Version       string
+		Model         *Model
+		Views         *Views
+		Documentation *Documentation
 	}
 )
 
 // Root is the design root expression.
-var Root = &Design{Model: &Model{}, Views: &Views{}}
+var Root = &Design{Model: &Model{}, Views: &Views{}, Documentation: &Documentation{}}
 
 // Register design root with eval engine.
 func init() {
@@ -48,9 +49,21 @@ func (d *Design) WalkSets(walk eval.SetWalker) {
 			walk(eval.ToExpressionSet(c.Components))
 		}
 	}
-	// 6. Deployment environments
+	// 6. Code elements
+	for _, s := range d.Model.Systems {
+		for _, c := range s.Containers {
+			for _, cmp := range c.Components {
+				walk(eval.ToExpressionSet(cmp.CodeElements))
+			}
+		}
+	}
+	// 7. Deployment environments
 	walkDeploymentNodes(d.Model.DeploymentNodes, walk)
-	// 7. Views
+	// 8. Documentation
+	if d.Documentation != nil {
+		walk([]eval.Expression{d.Documentation})
+	}
+	// 9. Views
 	walk([]eval.Expression{d.Views})
 }
 
@@ -80,6 +93,7 @@ func walkDeploymentNodes(n []*DeploymentNode, walk eval.SetWalker) {
 	for _, d := range n {
 		walk(eval.ToExpressionSet(d.InfrastructureNodes))
 		walk(eval.ToExpressionSet(d.ContainerInstances))
+		walk(eval.ToExpressionSet(d.SoftwareSystemInstances))
 		walkDeploymentNodes(d.Children, walk)
 	}
 }
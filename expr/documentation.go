@@ -0,0 +1,120 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// Documentation contains the documentation sections and the architecture
+	// decision records of the design.
+	Documentation struct {
+		Sections  []*DocumentationSection
+		Decisions []*Decision
+	}
+
+	// DocumentationSection describes a documentation section.
+	DocumentationSection struct {
+		Title   string
+		Content string
+		Format  DocFormatKind
+		// Element the section applies to, nil if the section applies to the
+		// whole design.
+		Element *Element
+	}
+
+	// Decision describes an architecture decision record.
+	Decision struct {
+		ID      string
+		Title   string
+		Date    string
+		Status  DecisionStatusKind
+		Content string
+		Format  DocFormatKind
+		// Element the decision applies to, nil if the decision applies to
+		// the whole design.
+		Element *Element
+	}
+
+	// DocFormatKind is the enum used to represent documentation formats.
+	DocFormatKind int
+
+	// DecisionStatusKind is the enum used to represent decision statuses.
+	DecisionStatusKind int
+)
+
+const (
+	FormatUndefined DocFormatKind = iota
+	FormatMarkdown
+	FormatASCIIDoc
+)
+
+const (
+	DecisionUndefined DecisionStatusKind = iota
+	DecisionProposed
+	DecisionAccepted
+	DecisionSuperseded
+	DecisionDeprecated
+	DecisionRejected
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (*Documentation) EvalName() string { return "documentation" }
+
+// Validate makes sure sections and decisions have content and that decision
+// IDs are unique and dates valid.
+func (d *Documentation) Validate() error {
+	verr := new(eval.ValidationErrors)
+	for _, s := range d.Sections {
+		if s.Content == "" {
+			verr.Add(s, "content is empty")
+		}
+	}
+	ids := make(map[string]struct{})
+	for _, dec := range d.Decisions {
+		if _, ok := ids[dec.ID]; ok {
+			verr.Add(dec, "ID already in use")
+		}
+		ids[dec.ID] = struct{}{}
+		if dec.Content == "" {
+			verr.Add(dec, "content is empty")
+		}
+		if dec.Date != "" {
+			if _, err := time.Parse(time.DateOnly, dec.Date); err != nil {
+				if _, err := time.Parse(time.RFC3339, dec.Date); err != nil {
+					verr.Add(dec, "invalid date %q, must be in ISO 8601 format (e.g. 2006-01-02)", dec.Date)
+				}
+			}
+		}
+	}
+	return verr
+}
+
+// Finalize defaults the format of sections and decisions to Markdown.
+func (d *Documentation) Finalize() {
+	for _, s := range d.Sections {
+		if s.Format == FormatUndefined {
+			s.Format = FormatMarkdown
+		}
+	}
+	for _, dec := range d.Decisions {
+		if dec.Format == FormatUndefined {
+			dec.Format = FormatMarkdown
+		}
+		if dec.Status == DecisionUndefined {
+			dec.Status = DecisionProposed
+		}
+	}
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (s *DocumentationSection) EvalName() string {
+	return fmt.Sprintf("documentation section %q", s.Title)
+}
+
+// EvalName returns the generic expression name used in error messages.
+func (d *Decision) EvalName() string {
+	return fmt.Sprintf("decision %q", d.ID)
+}
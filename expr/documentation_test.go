@@ -0,0 +1,59 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDocumentationValidate(t *testing.T) {
+	tests := []struct {
+		doc  *Documentation
+		want string
+	}{
+		{doc: &Documentation{}, want: ""},
+		{doc: &Documentation{Sections: []*DocumentationSection{{Title: "foo", Content: "bar"}}}, want: ""},
+		{doc: &Documentation{Sections: []*DocumentationSection{{Title: "foo"}}}, want: "content is empty"},
+		{doc: &Documentation{Decisions: []*Decision{{ID: "1", Content: "foo", Date: "2024-01-15"}}}, want: ""},
+		{doc: &Documentation{Decisions: []*Decision{{ID: "1", Content: "foo", Date: "2024-01-15T10:00:00Z"}}}, want: ""},
+		{doc: &Documentation{Decisions: []*Decision{{ID: "1", Content: "foo", Date: "15/01/2024"}}}, want: "invalid date"},
+		{doc: &Documentation{Decisions: []*Decision{{ID: "1", Content: "foo"}, {ID: "1", Content: "bar"}}}, want: "ID already in use"},
+	}
+	for i, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			err := tt.doc.Validate()
+			if tt.want == "" {
+				if err.Error() != "" {
+					t.Errorf("got error %q, want none", err.Error())
+				}
+				return
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentationFinalize(t *testing.T) {
+	t.Parallel()
+	doc := &Documentation{
+		Sections:  []*DocumentationSection{{Title: "foo"}, {Title: "bar", Format: FormatASCIIDoc}},
+		Decisions: []*Decision{{ID: "1"}, {ID: "2", Status: DecisionAccepted}},
+	}
+	doc.Finalize()
+	if doc.Sections[0].Format != FormatMarkdown {
+		t.Errorf("got format %d, want %d", doc.Sections[0].Format, FormatMarkdown)
+	}
+	if doc.Sections[1].Format != FormatASCIIDoc {
+		t.Errorf("got format %d, want %d", doc.Sections[1].Format, FormatASCIIDoc)
+	}
+	if doc.Decisions[0].Status != DecisionProposed {
+		t.Errorf("got status %d, want %d", doc.Decisions[0].Status, DecisionProposed)
+	}
+	if doc.Decisions[1].Status != DecisionAccepted {
+		t.Errorf("got status %d, want %d", doc.Decisions[1].Status, DecisionAccepted)
+	}
+}
@@ -13,9 +13,14 @@ type (
 		Technology    string
 		Tags          string
 		URL           string
+		Group         string
 		Properties    map[string]string
 		Relationships []*Relationship
 		DSLFunc       func()
+		// File and Line record where the element is declared in the DSL.
+		// They are empty for elements that are not declared explicitly.
+		File string
+		Line int
 	}
 
 	// ElementHolder provides access to the underlying element.
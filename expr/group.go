@@ -0,0 +1,28 @@
+package expr
+
+import (
+	"fmt"
+
+	"goa.design/goa/v3/eval"
+)
+
+type (
+	// Group describes a named group of elements. Groups are not elements:
+	// they only exist in the DSL to record the name of the group on the
+	// people, software systems, containers and components defined in them.
+	Group struct {
+		// Name of group.
+		Name string
+		// Scope is the expression the group is defined in: the design, a
+		// software system or a container.
+		Scope eval.Expression
+	}
+)
+
+// EvalName returns the generic expression name used in error messages.
+func (g *Group) EvalName() string {
+	if g.Name == "" {
+		return "unnamed group"
+	}
+	return fmt.Sprintf("group %q", g.Name)
+}
@@ -0,0 +1,24 @@
+package expr
+
+import (
+	"testing"
+)
+
+func TestGroupEvalName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{name: "", want: "unnamed group"},
+		{name: "foo", want: `group "foo"`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			group := Group{Name: tt.name}
+			if got := group.EvalName(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
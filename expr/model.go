@@ -14,6 +14,8 @@ type (
 		People                  People
 		Systems                 SoftwareSystems
 		DeploymentNodes         []*DeploymentNode
+		DeploymentGroups        []*DeploymentGroup
+		DependencyRules         []*DependencyRule
 		AddImpliedRelationships bool
 	}
 )
@@ -28,6 +30,8 @@ func Parent(eh ElementHolder) ElementHolder {
 		return e.System
 	case *Component:
 		return e.Container
+	case *CodeElement:
+		return e.Component
 	default:
 		panic(fmt.Sprintf("unknown element type %T", e)) // bug
 	}
@@ -36,7 +40,8 @@ func Parent(eh ElementHolder) ElementHolder {
 // EvalName is the qualified name of the DSL expression.
 func (*Model) EvalName() string { return "model" }
 
-// Validate makes sure all element names are unique.
+// Validate makes sure all element names are unique and that the
+// relationships comply with the dependency rules.
 func (m *Model) Validate() error {
 	verr := new(eval.ValidationErrors)
 	known := make(map[string]struct{})
@@ -63,6 +68,13 @@ func (m *Model) Validate() error {
 					verr.Add(cm, "name already in use")
 				}
 				components[cm.Name] = struct{}{}
+				codes := make(map[string]struct{})
+				for _, ce := range cm.CodeElements {
+					if _, ok := codes[ce.Name]; ok {
+						verr.Add(ce, "name already in use")
+					}
+					codes[ce.Name] = struct{}{}
+				}
 			}
 		}
 	}
@@ -86,32 +98,52 @@ func (m *Model) Validate() error {
 		r.Destination = eh.GetElement()
 	})
 
+	// Check dependency rules now that all relationship destinations are known.
+	var rules []*DependencyRule
+	for _, dr := range m.DependencyRules {
+		if err := dr.Resolve(m); err != nil {
+			verr.AddError(dr, err)
+			continue
+		}
+		rules = append(rules, dr)
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Destination == nil {
+			return
+		}
+		for _, dr := range rules {
+			if dr.Violated(r) {
+				verr.Add(r, "violates dependency rule: %s", dr)
+			}
+		}
+	})
+
 	return verr
 }
 
 // Finalize adds all implied relationships if needed.
 func (m *Model) Finalize() {
-	// Add relationships between container instances.
+	// Add relationships between container and software system instances
+	// that share a deployment group.
 	Iterate(func(e any) {
-		if ci, ok := e.(*ContainerInstance); ok {
-			c := Registry[ci.ContainerID].(*Container)
-			for _, r := range c.Relationships {
-				dc, ok := Registry[r.Destination.ID].(*Container)
-				if !ok {
-					continue
+		inst, id := instanceOf(e)
+		if inst == nil {
+			return
+		}
+		eh, ok := Registry[id].(ElementHolder)
+		if !ok {
+			return
+		}
+		for _, r := range eh.GetElement().Relationships {
+			Iterate(func(d any) {
+				dinst, did := instanceOf(d)
+				if dinst == nil || did != r.Destination.ID || !shareDeploymentGroup(e, d) {
+					return
 				}
-				Iterate(func(e any) {
-					eci, ok := e.(*ContainerInstance)
-					if !ok {
-						return
-					}
-					if eci.ContainerID == dc.ID {
-						rc := r.Dup(ci.Element, eci.Element)
-						rc.LinkedRelationshipID = r.ID
-						ci.Relationships = append(ci.Relationships, rc)
-					}
-				})
-			}
+				rc := r.Dup(inst, dinst)
+				rc.LinkedRelationshipID = r.ID
+				inst.Relationships = append(inst.Relationships, rc)
+			})
 		}
 	})
 	if !m.AddImpliedRelationships {
@@ -131,6 +163,11 @@ func (m *Model) Finalize() {
 				addImpliedRelationships(src, r.Destination, r)
 				addImpliedRelationships(s.Container, r.Destination, r)
 				addImpliedRelationships(s.Container.System, r.Destination, r)
+			case *CodeElement:
+				addImpliedRelationships(src, r.Destination, r)
+				addImpliedRelationships(s.Component, r.Destination, r)
+				addImpliedRelationships(s.Component.Container, r.Destination, r)
+				addImpliedRelationships(s.Component.Container.System, r.Destination, r)
 			}
 		}
 	})
@@ -168,12 +205,27 @@ func (m *Model) DeploymentNode(env, name string) *DeploymentNode {
 	return nil
 }
 
+// DeploymentGroup returns the deployment group with the given name in the
+// given environment if any, nil otherwise.
+func (m *Model) DeploymentGroup(env, name string) *DeploymentGroup {
+	for _, g := range m.DeploymentGroups {
+		if g.Environment == env && g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
 // FindElement finds the element with the given path in the given scope. The path must be one of:
 //
-//   - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>" or "<SoftwareSystem>/<Container>/<Component>"
+//   - "<Person>", "<SoftwareSystem>", "<SoftwareSystem>/<Container>", "<SoftwareSystem>/<Container>/<Component>"
+//     or "<SoftwareSystem>/<Container>/<Component>/<CodeElement>"
 //   - "<Container>" (if container is a child of the software system scope)
 //   - "<Component>" (if component is a child of the container scope)
+//   - "<CodeElement>" (if code element is a child of the component scope)
 //   - "<Container>/<Component>" (if container is a child of the software system scope)
+//   - "<Component>/<CodeElement>" (if component is a child of the container scope
+//     or a sibling of the component scope)
 //
 // The scope may be nil in which case the path must be rooted with a top level
 // element (person or software system).
@@ -190,6 +242,10 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 			if c := s.Component(path); c != nil {
 				eh = c
 			}
+		case *Component:
+			if c := s.CodeElement(path); c != nil {
+				eh = c
+			}
 		}
 		if eh == nil {
 			if p := m.Person(path); p != nil {
@@ -205,12 +261,25 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 			}
 		}
 	case 2:
-		if s, ok := scope.(*SoftwareSystem); ok {
+		switch s := scope.(type) {
+		case *SoftwareSystem:
 			if c := s.Container(elems[0]); c != nil {
 				if cmp := c.Component(elems[1]); cmp != nil {
 					eh = cmp
 				}
 			}
+		case *Container:
+			if cmp := s.Component(elems[0]); cmp != nil {
+				if ce := cmp.CodeElement(elems[1]); ce != nil {
+					eh = ce
+				}
+			}
+		case *Component:
+			if cmp := s.Container.Component(elems[0]); cmp != nil {
+				if ce := cmp.CodeElement(elems[1]); ce != nil {
+					eh = ce
+				}
+			}
 		}
 		if eh == nil {
 			if s := m.SoftwareSystem(elems[0]); s != nil {
@@ -240,6 +309,19 @@ func (m *Model) FindElement(scope ElementHolder, path string) (eh ElementHolder,
 		if eh == nil {
 			return nil, fmt.Errorf("%q does not match the name of a software system, container and component", path)
 		}
+	case 4:
+		if s := m.SoftwareSystem(elems[0]); s != nil {
+			if c := s.Container(elems[1]); c != nil {
+				if cmp := c.Component(elems[2]); cmp != nil {
+					if ce := cmp.CodeElement(elems[3]); ce != nil {
+						eh = ce
+					}
+				}
+			}
+		}
+		if eh == nil {
+			return nil, fmt.Errorf("%q does not match the name of a software system, container, component and code element", path)
+		}
 	default:
 		return nil, fmt.Errorf("too many colons in path")
 	}
@@ -265,6 +347,9 @@ func (m *Model) AddPerson(p *Person) *Person {
 	if p.Description != "" {
 		existing.Description = p.Description
 	}
+	if p.Group != "" {
+		existing.Group = p.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil {
 		existing.DSLFunc = func() { olddsl(); p.DSLFunc() }
 	}
@@ -291,6 +376,9 @@ func (m *Model) AddSystem(s *SoftwareSystem) *SoftwareSystem {
 	if s.Description != "" {
 		existing.Description = s.Description
 	}
+	if s.Group != "" {
+		existing.Group = s.Group
+	}
 	if olddsl := existing.DSLFunc; olddsl != nil && s.DSLFunc != nil {
 		existing.DSLFunc = func() { olddsl(); s.DSLFunc() }
 	}
@@ -322,12 +410,21 @@ func (m *Model) AddDeploymentNode(d *DeploymentNode) *DeploymentNode {
 	if d.Technology != "" {
 		existing.Technology = d.Technology
 	}
-	if olddsl := existing.DSLFunc; olddsl != nil {
-		existing.DSLFunc = func() { olddsl(); d.DSLFunc() }
-	}
+	existing.DSLFunc = mergeDSL(existing.DSLFunc, d.DSLFunc)
 	return existing
 }
 
+// AddDeploymentGroup adds the given deployment group to the model. If there is
+// already a deployment group with the same name in the same environment then
+// AddDeploymentGroup returns the existing group.
+func (m *Model) AddDeploymentGroup(g *DeploymentGroup) *DeploymentGroup {
+	if existing := m.DeploymentGroup(g.Environment, g.Name); existing != nil {
+		return existing
+	}
+	m.DeploymentGroups = append(m.DeploymentGroups, g)
+	return g
+}
+
 // addImpliedRelationships adds relationships from src to element with ID destID
 // and its parents (container system software and component container) based on
 // the properties of existing. It only adds a relationship if one doesn't
@@ -374,5 +471,9 @@ func addImpliedRelationships(src ElementHolder, destElem *Element, existing *Rel
 	case *Component:
 		addImpliedRelationships(src, e.Container.Element, existing)
 		addImpliedRelationships(src, e.Container.System.Element, existing)
+	case *CodeElement:
+		addImpliedRelationships(src, e.Component.Element, existing)
+		addImpliedRelationships(src, e.Component.Container.Element, existing)
+		addImpliedRelationships(src, e.Component.Container.System.Element, existing)
 	}
 }
@@ -54,6 +54,9 @@ func Identify(element any) {
 	case *Component:
 		id = idify(e.Container.ID + ":" + e.Name)
 		e.ID = id
+	case *CodeElement:
+		id = idify(e.Component.ID + ":" + e.Name)
+		e.ID = id
 	case *DeploymentNode:
 		prefix := "dn:" + e.Environment + ":"
 		for f := e.Parent; f != nil; f = f.Parent {
@@ -67,6 +70,9 @@ func Identify(element any) {
 	case *ContainerInstance:
 		id = idify(e.Environment + ":" + e.Parent.ID + ":" + e.ContainerID)
 		e.ID = id
+	case *SoftwareSystemInstance:
+		id = idify(e.Environment + ":" + e.Parent.ID + ":" + e.SoftwareSystemID)
+		e.ID = id
 	case *Relationship:
 		var dest string
 		if e.Destination != nil {
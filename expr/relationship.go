@@ -28,6 +28,11 @@ type (
 		// container corresponding to the container instance with this
 		// relationship.
 		LinkedRelationshipID string
+
+		// File and Line record where the relationship is declared in the
+		// DSL. They are empty for implied relationships.
+		File string
+		Line int
 	}
 
 	// InteractionStyleKind is the enum for possible interaction styles.
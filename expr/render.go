@@ -29,6 +29,12 @@ func addAllElements(view View) {
 		c := Registry[v.ContainerID].(*Container)
 		v.AddElements(c.System.Containers.Elements()...) // nolint: errcheck
 		v.AddElements(c.Components.Elements()...)        // nolint: errcheck
+	case *CodeView:
+		v.AddElements(m.People.Elements()...)  // nolint: errcheck
+		v.AddElements(m.Systems.Elements()...) // nolint: errcheck
+		c := Registry[v.ComponentID].(*Component)
+		v.AddElements(c.Container.Components.Elements()...) // nolint: errcheck
+		v.AddElements(c.CodeElements.Elements()...)         // nolint: errcheck
 	case *DeploymentView:
 		for _, n := range m.DeploymentNodes {
 			if n.Environment == "" || n.Environment == v.Environment {
@@ -66,6 +72,15 @@ func addDefaultElements(view View) {
 			v.AddElements(relatedSoftwareSystems(c.Element).Elements()...) // nolint: errcheck
 			v.AddElements(relatedPeople(c.Element).Elements()...)          // nolint: errcheck
 		}
+	case *CodeView:
+		c := Registry[v.ComponentID].(*Component)
+		v.AddElements(c.CodeElements.Elements()...) // nolint: errcheck
+		for _, ce := range c.CodeElements {
+			v.AddElements(relatedComponents(ce.Element).Elements()...)      // nolint: errcheck
+			v.AddElements(relatedContainers(ce.Element).Elements()...)      // nolint: errcheck
+			v.AddElements(relatedSoftwareSystems(ce.Element).Elements()...) // nolint: errcheck
+			v.AddElements(relatedPeople(ce.Element).Elements()...)          // nolint: errcheck
+		}
 	case *DeploymentView:
 		addAllElements(v)
 	}
@@ -121,6 +136,8 @@ func addMissingElementsAndRelationships(vp *ViewProps) {
 				srcTop = top(s.Parent)
 			case *ContainerInstance:
 				srcTop = top(s.Parent)
+			case *SoftwareSystemInstance:
+				srcTop = top(s.Parent)
 			}
 			switch d := Registry[r.Destination.ID].(type) {
 			case *DeploymentNode:
@@ -129,6 +146,8 @@ func addMissingElementsAndRelationships(vp *ViewProps) {
 				destTop = top(d.Parent)
 			case *ContainerInstance:
 				destTop = top(d.Parent)
+			case *SoftwareSystemInstance:
+				destTop = top(d.Parent)
 			}
 			if srcTop != destTop {
 				continue loop
@@ -184,9 +203,16 @@ func addNeighbors(e *Element, view View) {
 		v.AddElements(relatedSoftwareSystems(e).Elements()...) // nolint: errcheck
 		v.AddElements(relatedContainers(e).Elements()...)      // nolint: errcheck
 		v.AddElements(relatedComponents(e).Elements()...)      // nolint: errcheck
+	case *CodeView:
+		v.AddElements(relatedPeople(e).Elements()...)          // nolint: errcheck
+		v.AddElements(relatedSoftwareSystems(e).Elements()...) // nolint: errcheck
+		v.AddElements(relatedContainers(e).Elements()...)      // nolint: errcheck
+		v.AddElements(relatedComponents(e).Elements()...)      // nolint: errcheck
+		v.AddElements(relatedCodeElements(e).Elements()...)    // nolint: errcheck
 	case *DeploymentView:
-		v.AddElements(relatedInfrastructureNodes(e).Elements()...) // nolint: errcheck
-		v.AddElements(relatedContainerInstances(e).Elements()...)  // nolint: errcheck
+		v.AddElements(relatedInfrastructureNodes(e).Elements()...)     // nolint: errcheck
+		v.AddElements(relatedContainerInstances(e).Elements()...)      // nolint: errcheck
+		v.AddElements(relatedSoftwareSystemInstances(e).Elements()...) // nolint: errcheck
 	}
 
 }
@@ -439,6 +465,32 @@ func relatedComponents(elem *Element) (res Components) {
 	return
 }
 
+// relatedCodeElements returns all code elements the element has a relationship
+// with (either as source or as destination).
+func relatedCodeElements(elem *Element) (res CodeElements) {
+	add := func(c *CodeElement) {
+		for _, es := range res {
+			if es.ID == c.ID {
+				return
+			}
+		}
+		res = append(res, c)
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Source.ID == elem.ID {
+			if c, ok := Registry[r.Destination.ID].(*CodeElement); ok {
+				add(c)
+			}
+		}
+		if r.Destination.ID == elem.ID {
+			if c, ok := Registry[r.Source.ID].(*CodeElement); ok {
+				add(c)
+			}
+		}
+	})
+	return
+}
+
 // relatedInfrastructureNodes returns all infrastructure nodes the element has a
 // relationship with (either as source or as destination).
 func relatedInfrastructureNodes(elem *Element) (res InfrastructureNodes) {
@@ -491,6 +543,32 @@ func relatedContainerInstances(elem *Element) (res ContainerInstances) {
 	return
 }
 
+// relatedSoftwareSystemInstances returns all software system instances the
+// element has a relationship with (either as source or as destination).
+func relatedSoftwareSystemInstances(elem *Element) (res SoftwareSystemInstances) {
+	add := func(si *SoftwareSystemInstance) {
+		for _, esi := range res {
+			if esi.ID == si.ID {
+				return
+			}
+		}
+		res = append(res, si)
+	}
+	IterateRelationships(func(r *Relationship) {
+		if r.Source.ID == elem.ID {
+			if si, ok := Registry[r.Destination.ID].(*SoftwareSystemInstance); ok {
+				add(si)
+			}
+		}
+		if r.Destination.ID == elem.ID {
+			if si, ok := Registry[r.Source.ID].(*SoftwareSystemInstance); ok {
+				add(si)
+			}
+		}
+	})
+	return
+}
+
 // allUnreachable fetches all elements in view not reachable from eh (directory
 // or not).
 func unreachable(v *ViewProps, eh ElementHolder) (elems []*Element) {
@@ -79,6 +79,9 @@ func (s *SoftwareSystem) AddContainer(c *Container) *Container {
 	if c.URL != "" {
 		existing.URL = c.URL
 	}
+	if c.Group != "" {
+		existing.Group = c.Group
+	}
 	existing.MergeTags(strings.Split(c.Tags, ",")...)
 	for _, cmp := range c.Components {
 		existing.AddComponent(cmp) // will merge if needed
@@ -13,6 +13,7 @@ type (
 		ContextViews    []*ContextView
 		ContainerViews  []*ContainerView
 		ComponentViews  []*ComponentView
+		CodeViews       []*CodeView
 		DynamicViews    []*DynamicView
 		DeploymentViews []*DeploymentView
 		FilteredViews   []*FilteredView
@@ -49,6 +50,12 @@ type (
 		ContainerID                string
 	}
 
+	// CodeView describes a code view for a specific component.
+	CodeView struct {
+		*ViewProps
+		ComponentID string
+	}
+
 	// DynamicView describes a dynamic view for a specified scope.
 	DynamicView struct {
 		*ViewProps
@@ -149,6 +156,7 @@ var (
 	_ View = &ContextView{}
 	_ View = &ContainerView{}
 	_ View = &ComponentView{}
+	_ View = &CodeView{}
 	_ View = &DynamicView{}
 	_ View = &DeploymentView{}
 
@@ -157,6 +165,7 @@ var (
 	_ ViewAdder = &ContextView{}
 	_ ViewAdder = &ContainerView{}
 	_ ViewAdder = &ComponentView{}
+	_ ViewAdder = &CodeView{}
 	_ ViewAdder = &DeploymentView{}
 )
 
@@ -214,16 +223,17 @@ func (vs *Views) Validate() error {
 			destID := rv.Destination.ID
 			desc := rv.Description
 
-			// The relationships between container instances is implicitly
-			// derived from the relationships between the corresponding
-			// containers so make sure there is one for all relationships added
-			// explicitly to the deployment view and if so create the
-			// relationship between the container instances.
-			sci, srcIsCI := Registry[rv.Source.ID].(*ContainerInstance)
-			dci, destIsCI := Registry[rv.Destination.ID].(*ContainerInstance)
-			if srcIsCI && destIsCI {
-				srcID = sci.ContainerID
-				destID = dci.ContainerID
+			// The relationships between container and software system
+			// instances are implicitly derived from the relationships between
+			// the corresponding containers and software systems so make sure
+			// there is one for all relationships added explicitly to the
+			// deployment view and if so create the relationship between the
+			// instances.
+			srcInst, sid := instanceOf(Registry[rv.Source.ID])
+			destInst, did := instanceOf(Registry[rv.Destination.ID])
+			if srcInst != nil && destInst != nil {
+				srcID = sid
+				destID = did
 			}
 
 			IterateRelationships(func(r *Relationship) {
@@ -231,11 +241,11 @@ func (vs *Views) Validate() error {
 					return // a validation error was already created in model.Validate
 				}
 				if r.Source.ID == srcID && r.Destination.ID == destID && r.Description == desc {
-					if srcIsCI && destIsCI {
-						rci := r.Dup(sci.Element, dci.Element)
-						rci.LinkedRelationshipID = r.ID
-						sci.Relationships = append(sci.Relationships, rci)
-						r = rci
+					if srcInst != nil && destInst != nil {
+						ri := r.Dup(srcInst, destInst)
+						ri.LinkedRelationshipID = r.ID
+						srcInst.Relationships = append(srcInst.Relationships, ri)
+						r = ri
 					}
 					rv.RelationshipID = r.ID
 				}
@@ -386,6 +396,9 @@ func (vs Views) All() (vps []View) {
 	for _, cv := range vs.ComponentViews {
 		vps = append(vps, cv)
 	}
+	for _, cv := range vs.CodeViews {
+		vps = append(vps, cv)
+	}
 	for _, dv := range vs.DynamicViews {
 		vps = append(vps, dv)
 	}
@@ -479,6 +492,27 @@ func (cv *ComponentView) AddAnimationStep(s *AnimationStep) error {
 	return addAnimationStep(cv.ViewProps, s)
 }
 
+// AddElements adds the given elements to the view if not already present.
+func (cv *CodeView) AddElements(ehs ...ElementHolder) error {
+	for _, eh := range ehs {
+		if !isPSCCE(eh) {
+			return fmt.Errorf("elements of type %T cannot be added to code view", eh)
+		}
+	}
+	addElements(cv.ViewProps, ehs...)
+	return nil
+}
+
+// AddAnimationStep adds the given animation step to the view.
+func (cv *CodeView) AddAnimationStep(s *AnimationStep) error {
+	for _, eh := range s.Elements {
+		if !isPSCCE(eh) {
+			return fmt.Errorf("elements of type %T cannot be added to an animation step in a code view", eh)
+		}
+	}
+	return addAnimationStep(cv.ViewProps, s)
+}
+
 // AddElements adds the given elements to the view if not already present.
 func (dv *DeploymentView) AddElements(ehs ...ElementHolder) error {
 	var nodes []*DeploymentNode
@@ -493,6 +527,11 @@ func (dv *DeploymentView) AddElements(ehs ...ElementHolder) error {
 				addElements(dv.ViewProps, e)
 				nodes = append(nodes, e.Parent)
 			}
+		case *SoftwareSystemInstance:
+			if dv.SoftwareSystemID == "" || dv.SoftwareSystemID != e.SoftwareSystemID {
+				addElements(dv.ViewProps, e)
+				nodes = append(nodes, e.Parent)
+			}
 		case *InfrastructureNode:
 			addElements(dv.ViewProps, e)
 			nodes = append(nodes, e.Parent)
@@ -568,11 +607,21 @@ func isPSCC(eh ElementHolder) bool {
 	return ok
 }
 
-// isDCI returns true if element is a deployment node, a container instance or
-// an infrastructure node, false otherwise.
+// isPSCCE returns true if element is a person, a software system, a container,
+// a component or a code element, false otherwise.
+func isPSCCE(eh ElementHolder) bool {
+	if isPSCC(eh) {
+		return true
+	}
+	_, ok := eh.(*CodeElement)
+	return ok
+}
+
+// isDCI returns true if element is a deployment node, a container instance, a
+// software system instance or an infrastructure node, false otherwise.
 func isDCI(eh ElementHolder) bool {
 	switch eh.(type) {
-	case *DeploymentNode, *ContainerInstance, *InfrastructureNode:
+	case *DeploymentNode, *ContainerInstance, *SoftwareSystemInstance, *InfrastructureNode:
 		return true
 	}
 	return false
@@ -592,8 +641,9 @@ loop:
 	}
 }
 
-// addDeploymentNodeChildren adds the children, infrastructure nodes and container
-// instances of n to dv and returns true if anything was added, false otherwise.
+// addDeploymentNodeChildren adds the children, infrastructure nodes, container
+// and software system instances of n to dv and returns true if anything was
+// added, false otherwise.
 func addDeploymentNodeChildren(dv *DeploymentView, n *DeploymentNode) bool {
 	var nested bool
 	for _, inst := range n.ContainerInstances {
@@ -602,6 +652,12 @@ func addDeploymentNodeChildren(dv *DeploymentView, n *DeploymentNode) bool {
 			nested = true
 		}
 	}
+	for _, inst := range n.SoftwareSystemInstances {
+		if dv.SoftwareSystemID == "" || inst.SoftwareSystemID != dv.SoftwareSystemID {
+			addElements(dv.ViewProps, inst)
+			nested = true
+		}
+	}
 	for _, inf := range n.InfrastructureNodes {
 		addElements(dv.ViewProps, inf)
 		nested = true
@@ -640,13 +696,15 @@ loop:
 		known = append(known, e)
 		filtered = append(filtered, e)
 
-		// Add parent deployment nodes for infrastructure nodes
-		// and container instances.
+		// Add parent deployment nodes for infrastructure nodes, container
+		// and software system instances.
 		var node *DeploymentNode
 		if inf, ok := e.(*InfrastructureNode); ok {
 			node = inf.Parent
 		} else if ci, ok := e.(*ContainerInstance); ok {
 			node = ci.Parent
+		} else if si, ok := e.(*SoftwareSystemInstance); ok {
+			node = si.Parent
 		}
 		for node != nil {
 			known = append(known, node)
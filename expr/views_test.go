@@ -137,6 +137,35 @@ func Test_IsPSCC(t *testing.T) {
 	}
 }
 
+func Test_IsPSCCE(t *testing.T) {
+	mDeploymentNode := DeploymentNode{
+		Element: &Element{Name: "SoftwareSystem"},
+	}
+	mComponent := Component{
+		Element: &Element{Name: "Component"},
+	}
+	mCodeElement := CodeElement{
+		Element: &Element{Name: "CodeElement"},
+	}
+
+	tests := []struct {
+		eh   ElementHolder
+		want bool
+	}{
+		{eh: &mComponent, want: true},
+		{eh: &mCodeElement, want: true},
+		{eh: &mDeploymentNode, want: false},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			got := isPSCCE(tt.eh)
+			if got != tt.want {
+				t.Errorf("Got %t, wanted %t, for %v", got, tt.want, tt.eh)
+			}
+		})
+	}
+}
+
 func Test_IsDCI(t *testing.T) {
 	mDeploymentNode := DeploymentNode{
 		Element: &Element{Name: "SoftwareSystem"},
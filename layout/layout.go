@@ -0,0 +1,976 @@
+/*
+Package layout computes the position of the nodes and the route of the edges
+of diagrams using a layered algorithm: nodes are assigned to ranks so that
+edges follow the rank direction, the nodes of each rank are ordered to reduce
+edge crossings and nodes are then positioned so that edges are as straight as
+possible. Edges that span multiple ranks are routed through vertices and edge
+labels are allotted their own space between ranks.
+
+Nodes may contain other nodes (clusters). The content of a cluster is laid out
+first, the cluster is then laid out as a single node of its parent. Edges are
+laid out in the closest cluster that contains both their source and their
+destination.
+
+	g := &layout.Graph{
+	    Nodes: []*layout.Node{
+	        {ID: "user", Width: 280, Height: 240},
+	        {ID: "system", Width: 280, Height: 180},
+	    },
+	    Edges: []*layout.Edge{{ID: "uses", Source: "user", Destination: "system"}},
+	}
+	if err := layout.Layout(g, layout.Options{Direction: mdl.RankTopBottom}); err != nil {
+	    return err
+	}
+*/
+package layout
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// Graph is the diagram to lay out.
+	Graph struct {
+		// Nodes lists the top level nodes.
+		Nodes []*Node
+		// Edges lists the edges between nodes that are not clusters.
+		Edges []*Edge
+	}
+
+	// Node is a diagram node.
+	Node struct {
+		// ID of node, unique in the graph.
+		ID string
+		// Width of node, computed by Layout for clusters.
+		Width float64
+		// Height of node, computed by Layout for clusters.
+		Height float64
+		// Children lists the nodes contained in a cluster.
+		Children []*Node
+		// X is the abscissa of the center of the node computed by Layout.
+		X float64
+		// Y is the ordinate of the center of the node computed by Layout.
+		Y float64
+	}
+
+	// Edge is a diagram edge.
+	Edge struct {
+		// ID of edge.
+		ID string
+		// Source is the ID of the edge source node.
+		Source string
+		// Destination is the ID of the edge destination node.
+		Destination string
+		// LabelWidth is the width of the edge label if any.
+		LabelWidth float64
+		// LabelHeight is the height of the edge label if any.
+		LabelHeight float64
+		// Vertices lists the points the edge goes through between the center
+		// of its source and the center of its destination, computed by
+		// Layout.
+		Vertices []Point
+		// Label is the position of the center of the edge label computed by
+		// Layout.
+		Label Point
+	}
+
+	// Point is a position in the diagram.
+	Point struct {
+		X, Y float64
+	}
+
+	// Options are the layout settings.
+	Options struct {
+		// Direction is the rank direction, defaults to mdl.RankTopBottom.
+		Direction mdl.RankDirectionKind
+		// RankSep is the distance between ranks, defaults to 80.
+		RankSep float64
+		// NodeSep is the distance between nodes of the same rank, defaults
+		// to 80.
+		NodeSep float64
+		// EdgeSep is the distance between edges and between edges and their
+		// labels, defaults to 20.
+		EdgeSep float64
+		// ClusterPadding is the distance between the border of clusters and
+		// their content.
+		ClusterPadding float64
+		// ClusterLabelHeight is the space reserved below the content of
+		// clusters for their label.
+		ClusterLabelHeight float64
+	}
+
+	// layouter holds the state of a layout.
+	layouter struct {
+		opts Options
+		// byID indexes the nodes by ID.
+		byID map[string]*Node
+		// parent maps nodes to the cluster that contains them.
+		parent map[*Node]*Node
+		// owned lists the edges laid out in each cluster.
+		owned map[*Node][]*Edge
+		// crossing lists the edges that cross the border of each cluster.
+		crossing map[*Node][]*Edge
+		// forward records whether edges go along the rank direction once
+		// the cycles of the whole graph are broken.
+		forward map[*Edge]bool
+		// ports is the position of the point where edges cross the border
+		// of clusters relative to the top left corner of the cluster.
+		ports map[piece]Point
+		// pieces lists the points of the part of the edge routes computed
+		// in each cluster relative to the top left corner of the cluster.
+		pieces map[piece][]Point
+		// labels is the position of the edge labels relative to the top left
+		// corner of the cluster that owns the edge.
+		labels map[*Edge]Point
+		// origin is the absolute position of the top left corner of each
+		// cluster.
+		origin map[*Node]Point
+	}
+
+	// piece identifies the part of the route of an edge computed in a
+	// cluster.
+	piece struct {
+		edge    *Edge
+		cluster *Node
+	}
+
+	// vertex is a node of the layered graph: either a diagram node or a
+	// dummy node that an edge goes through.
+	vertex struct {
+		node    *Node
+		breadth float64
+		depth   float64
+		// anchor is the offset of the point edges go through relative to
+		// the center of the vertex, non-zero for edge label vertices.
+		anchor float64
+		label  bool
+		rank   int
+		order  int
+		pos    float64
+		in     []*vertex
+		out    []*vertex
+	}
+
+	// link is an edge of the layered graph.
+	link struct {
+		edge     *Edge
+		from, to *vertex
+		reversed bool
+		chain    []*vertex
+		// port is the vertex where the edge crosses the border of the
+		// cluster, nil if the link connects two children of the cluster.
+		port *vertex
+		// before is true if port is on the side of the first rank.
+		before bool
+	}
+)
+
+// Layout computes the position of the nodes and the route of the edges of g.
+func Layout(g *Graph, opts Options) error {
+	if opts.Direction == mdl.RankUndefined {
+		opts.Direction = mdl.RankTopBottom
+	}
+	if opts.RankSep <= 0 {
+		opts.RankSep = 80
+	}
+	if opts.NodeSep <= 0 {
+		opts.NodeSep = 80
+	}
+	if opts.EdgeSep <= 0 {
+		opts.EdgeSep = 20
+	}
+	l := &layouter{
+		opts:     opts,
+		byID:     make(map[string]*Node),
+		parent:   make(map[*Node]*Node),
+		owned:    make(map[*Node][]*Edge),
+		crossing: make(map[*Node][]*Edge),
+		ports:    make(map[piece]Point),
+		pieces:   make(map[piece][]Point),
+		labels:   make(map[*Edge]Point),
+		origin:   make(map[*Node]Point),
+	}
+	root := &Node{Children: g.Nodes}
+	if err := l.index(root); err != nil {
+		return err
+	}
+	var edges []*Edge
+	for _, e := range g.Edges {
+		src, dest := l.byID[e.Source], l.byID[e.Destination]
+		switch {
+		case src == nil:
+			return fmt.Errorf("edge %s: unknown source %q", e.ID, e.Source)
+		case dest == nil:
+			return fmt.Errorf("edge %s: unknown destination %q", e.ID, e.Destination)
+		case len(src.Children) > 0:
+			return fmt.Errorf("edge %s: source %q is a cluster", e.ID, e.Source)
+		case len(dest.Children) > 0:
+			return fmt.Errorf("edge %s: destination %q is a cluster", e.ID, e.Destination)
+		}
+		e.Vertices = nil
+		if src == dest {
+			continue
+		}
+		owner := l.commonAncestor(src, dest)
+		l.owned[owner] = append(l.owned[owner], e)
+		for c := l.parent[src]; c != owner; c = l.parent[c] {
+			l.crossing[c] = append(l.crossing[c], e)
+		}
+		for c := l.parent[dest]; c != owner; c = l.parent[c] {
+			l.crossing[c] = append(l.crossing[c], e)
+		}
+		edges = append(edges, e)
+	}
+	l.forward = l.directions(root, edges)
+	l.place(root)
+	l.translate(root, Point{})
+	for _, e := range edges {
+		l.assemble(e)
+	}
+	return nil
+}
+
+// index records the nodes contained in c recursively.
+func (l *layouter) index(c *Node) error {
+	for _, n := range c.Children {
+		if n.ID == "" {
+			return fmt.Errorf("node with no ID")
+		}
+		if _, ok := l.byID[n.ID]; ok {
+			return fmt.Errorf("duplicate node %q", n.ID)
+		}
+		l.byID[n.ID] = n
+		l.parent[n] = c
+		if err := l.index(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commonAncestor returns the closest cluster that contains both a and b.
+func (l *layouter) commonAncestor(a, b *Node) *Node {
+	ancestors := make(map[*Node]bool)
+	for p := l.parent[a]; p != nil; p = l.parent[p] {
+		ancestors[p] = true
+	}
+	for p := l.parent[b]; p != nil; p = l.parent[p] {
+		if ancestors[p] {
+			return p
+		}
+	}
+	return nil
+}
+
+// childOf returns the child of c that is or contains n.
+func (l *layouter) childOf(c, n *Node) *Node {
+	for l.parent[n] != c {
+		n = l.parent[n]
+	}
+	return n
+}
+
+// contains returns true if n is a descendant of cluster c.
+func (l *layouter) contains(c, n *Node) bool {
+	for p := l.parent[n]; p != nil; p = l.parent[p] {
+		if p == c {
+			return true
+		}
+	}
+	return false
+}
+
+// directions breaks the cycles of the graph ignoring clusters and returns
+// whether each edge goes along the rank direction. All clusters orient their
+// links accordingly so that edges cross cluster borders on the side of the
+// rest of the edge.
+func (l *layouter) directions(root *Node, edges []*Edge) map[*Edge]bool {
+	byNode := make(map[*Node]*vertex)
+	var vertices []*vertex
+	var collect func(c *Node)
+	collect = func(c *Node) {
+		for _, n := range c.Children {
+			if len(n.Children) > 0 {
+				collect(n)
+				continue
+			}
+			v := &vertex{node: n}
+			byNode[n] = v
+			vertices = append(vertices, v)
+		}
+	}
+	collect(root)
+	links := make([]*link, len(edges))
+	for i, e := range edges {
+		links[i] = &link{edge: e, from: byNode[l.byID[e.Source]], to: byNode[l.byID[e.Destination]]}
+	}
+	breakCycles(vertices, links)
+	forward := make(map[*Edge]bool, len(links))
+	for _, lk := range links {
+		forward[lk.edge] = !lk.reversed
+	}
+	return forward
+}
+
+// assemble computes the absolute route of e from the pieces computed in each
+// cluster it goes through.
+func (l *layouter) assemble(e *Edge) {
+	src, dest := l.byID[e.Source], l.byID[e.Destination]
+	owner := l.commonAncestor(src, dest)
+	points := []Point{{src.X, src.Y}}
+	add := func(c *Node) {
+		o := l.origin[c]
+		for _, p := range l.pieces[piece{e, c}] {
+			points = append(points, Point{p.X + o.X, p.Y + o.Y})
+		}
+	}
+	for c := l.parent[src]; c != owner; c = l.parent[c] {
+		add(c)
+	}
+	add(owner)
+	var down []*Node
+	for c := l.parent[dest]; c != owner; c = l.parent[c] {
+		down = append(down, c)
+	}
+	for i := len(down) - 1; i >= 0; i-- {
+		add(down[i])
+	}
+	points = append(points, Point{dest.X, dest.Y})
+	e.Vertices = simplify(points)
+	o := l.origin[owner]
+	e.Label = Point{l.labels[e].X + o.X, l.labels[e].Y + o.Y}
+}
+
+// place lays out the content of cluster c and computes its size. The
+// positions of the children of c are relative to the top left corner of c.
+// Edges that cross the border of c go through port vertices placed on an
+// extra rank before the first or after the last rank.
+func (l *layouter) place(c *Node) {
+	for _, n := range c.Children {
+		if len(n.Children) > 0 {
+			l.place(n)
+		}
+	}
+
+	// Build the layered graph.
+	vertices := make([]*vertex, len(c.Children))
+	byNode := make(map[*Node]*vertex, len(c.Children))
+	for i, n := range c.Children {
+		v := &vertex{node: n}
+		v.breadth, v.depth = l.extent(n.Width, n.Height)
+		vertices[i] = v
+		byNode[n] = v
+	}
+	links := make([]*link, 0, len(l.owned[c])+len(l.crossing[c]))
+	for _, e := range l.owned[c] {
+		links = append(links, &link{
+			edge: e,
+			from: byNode[l.childOf(c, l.byID[e.Source])],
+			to:   byNode[l.childOf(c, l.byID[e.Destination])],
+		})
+		if lk := links[len(links)-1]; !l.forward[e] {
+			lk.from, lk.to = lk.to, lk.from
+			lk.reversed = true
+		}
+	}
+	breakCycles(vertices, links)
+	assignRanks(vertices, links)
+	first, last := 0, 0
+	for _, v := range vertices {
+		last = max(last, v.rank)
+	}
+	all := vertices
+	for _, e := range l.crossing[c] {
+		src := l.byID[e.Source]
+		outgoing := l.contains(c, src)
+		inner := src
+		if !outgoing {
+			inner = l.byID[e.Destination]
+		}
+		lk := &link{edge: e, port: &vertex{rank: last + 1}}
+		lk.before = l.forward[e] != outgoing
+		if lk.before {
+			lk.port.rank = first - 1
+		}
+		lk.from, lk.to = byNode[l.childOf(c, inner)], lk.port
+		if !outgoing {
+			lk.from, lk.to = lk.to, lk.from
+		}
+		if lk.from.rank > lk.to.rank {
+			lk.from, lk.to = lk.to, lk.from
+			lk.reversed = true
+		}
+		links = append(links, lk)
+		all = append(all, lk.port)
+	}
+	lowest := math.MaxInt
+	for _, v := range all {
+		lowest = min(lowest, v.rank)
+	}
+	for _, v := range all {
+		v.rank -= lowest
+	}
+	for _, lk := range links {
+		all = append(all, l.chain(lk)...)
+	}
+	layers := order(all)
+	l.position(layers)
+
+	// Compute the coordinates.
+	centers := make([]float64, len(layers))
+	depths := make([]float64, len(layers))
+	var start float64
+	for r, layer := range layers {
+		for _, v := range layer {
+			depths[r] = math.Max(depths[r], v.depth)
+		}
+		centers[r] = start + depths[r]/2
+		start += depths[r] + l.opts.RankSep/2
+	}
+	total := start - l.opts.RankSep/2
+	vertical := l.opts.Direction == mdl.RankLeftRight || l.opts.Direction == mdl.RankRightLeft
+	point := func(pos, rc float64) Point {
+		switch l.opts.Direction {
+		case mdl.RankBottomTop:
+			return Point{pos, total - rc}
+		case mdl.RankLeftRight:
+			return Point{rc, pos}
+		case mdl.RankRightLeft:
+			return Point{total - rc, pos}
+		default:
+			return Point{pos, rc}
+		}
+	}
+	for _, v := range vertices {
+		p := point(v.pos, centers[v.rank])
+		v.node.X, v.node.Y = p.X, p.Y
+	}
+	// attach returns the point where lk leaves or enters v on the border of
+	// the band of its rank coming from its neighbor w in the chain. side is
+	// 1 for the border toward the next rank and -1 for the other one. The
+	// point is omitted if the straight line to w stays in front of v.
+	attach := func(lk *link, v, w *vertex, side float64) (Point, bool) {
+		edge := centers[v.rank] + side*depths[v.rank]/2
+		if len(v.node.Children) > 0 {
+			n := v.node
+			p := l.ports[piece{lk.edge, n}]
+			if vertical {
+				return point(n.Y-n.Height/2+p.Y, edge), true
+			}
+			return point(n.X-n.Width/2+p.X, edge), true
+		}
+		target := w.pos + w.anchor
+		dist := math.Abs(centers[w.rank] - centers[v.rank])
+		if dist == 0 || math.Abs(target-v.pos)*depths[v.rank]/2/dist <= v.breadth/2 {
+			return Point{}, false
+		}
+		half := v.breadth / 4
+		return point(math.Max(v.pos-half, math.Min(v.pos+half, target)), edge), true
+	}
+	routes := make(map[*link][]Point, len(links))
+	for _, lk := range links {
+		var points []Point
+		next, prev := lk.to, lk.from
+		if len(lk.chain) > 0 {
+			next, prev = lk.chain[0], lk.chain[len(lk.chain)-1]
+		}
+		if lk.from.node != nil {
+			if p, ok := attach(lk, lk.from, next, 1); ok {
+				points = append(points, p)
+			}
+		} else {
+			points = append(points, point(lk.from.pos, centers[lk.from.rank]))
+		}
+		for _, v := range lk.chain {
+			// Go straight through the band of the rank so that the edge
+			// does not cut the corners of the nodes of the rank.
+			rc, half := centers[v.rank], depths[v.rank]/2
+			points = append(points, point(v.pos+v.anchor, rc-half), point(v.pos+v.anchor, rc+half))
+			if v.label {
+				l.labels[lk.edge] = point(v.pos+l.opts.EdgeSep/2, centers[v.rank])
+			}
+		}
+		if lk.to.node != nil {
+			if p, ok := attach(lk, lk.to, prev, -1); ok {
+				points = append(points, p)
+			}
+		} else {
+			points = append(points, point(lk.to.pos, centers[lk.to.rank]))
+		}
+		if lk.reversed {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		routes[lk] = points
+	}
+
+	// Compute the bounding box and move the content to the top left corner.
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(x, y, w, h float64) {
+		minX, maxX = math.Min(minX, x-w/2), math.Max(maxX, x+w/2)
+		minY, maxY = math.Min(minY, y-h/2), math.Max(maxY, y+h/2)
+	}
+	for _, v := range vertices {
+		extend(v.node.X, v.node.Y, v.node.Width, v.node.Height)
+	}
+	for lk, points := range routes {
+		for _, p := range points {
+			extend(p.X, p.Y, 0, 0)
+		}
+		if lk.port == nil {
+			e := lk.edge
+			extend(l.labels[e].X, l.labels[e].Y, e.LabelWidth, e.LabelHeight)
+		}
+	}
+	if len(vertices) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	pad := l.opts.ClusterPadding
+	if l.parent[c] == nil {
+		pad = 0
+	}
+	dx, dy := pad-minX, pad-minY
+	for _, v := range vertices {
+		v.node.X += dx
+		v.node.Y += dy
+	}
+	if l.parent[c] != nil {
+		c.Width = maxX - minX + 2*pad
+		c.Height = maxY - minY + 2*pad + l.opts.ClusterLabelHeight
+	}
+	for _, lk := range links {
+		points := routes[lk]
+		for i := range points {
+			points[i].X += dx
+			points[i].Y += dy
+		}
+		if lk.port == nil {
+			e := lk.edge
+			l.labels[e] = Point{l.labels[e].X + dx, l.labels[e].Y + dy}
+		} else {
+			// The port is the first point of the route if the edge enters
+			// the cluster and the last one if it leaves it.
+			entering := !l.contains(c, l.byID[lk.edge.Source])
+			i := len(points) - 1
+			if entering {
+				i = 0
+			}
+			p := l.border(c, points[i], lk.before)
+			l.ports[piece{lk.edge, c}] = p
+			if entering {
+				points = append([]Point{p}, points...)
+			} else {
+				points = append(points, p)
+			}
+		}
+		l.pieces[piece{lk.edge, c}] = points
+	}
+}
+
+// border returns the projection of p on the border of cluster c on the side
+// of the first rank if before is true, of the last rank otherwise.
+func (l *layouter) border(c *Node, p Point, before bool) Point {
+	switch l.opts.Direction {
+	case mdl.RankBottomTop:
+		p.Y = 0
+		if before {
+			p.Y = c.Height
+		}
+	case mdl.RankLeftRight:
+		p.X = c.Width
+		if before {
+			p.X = 0
+		}
+	case mdl.RankRightLeft:
+		p.X = 0
+		if before {
+			p.X = c.Width
+		}
+	default:
+		p.Y = c.Height
+		if before {
+			p.Y = 0
+		}
+	}
+	return p
+}
+
+// extent returns the size of a node along the ranks (breadth) and across
+// the ranks (depth).
+func (l *layouter) extent(width, height float64) (breadth, depth float64) {
+	if l.opts.Direction == mdl.RankLeftRight || l.opts.Direction == mdl.RankRightLeft {
+		return height, width
+	}
+	return width, height
+}
+
+// chain creates the dummy vertices that the link goes through, one per rank
+// between its source and its destination. The vertex in the middle holds the
+// edge label unless the link goes to a cluster port.
+func (l *layouter) chain(lk *link) []*vertex {
+	from, to := lk.from, lk.to
+	mid := -1
+	if lk.port == nil {
+		mid = from.rank + (to.rank-from.rank)/2
+		if (mid-from.rank)%2 == 0 {
+			mid--
+		}
+	}
+	prev := from
+	var dummies []*vertex
+	for r := from.rank + 1; r < to.rank; r++ {
+		v := &vertex{rank: r}
+		if r == mid {
+			v.label = true
+			if lk.edge.LabelWidth > 0 && lk.edge.LabelHeight > 0 {
+				w, h := lk.edge.LabelWidth, lk.edge.LabelHeight
+				if l.opts.Direction == mdl.RankLeftRight || l.opts.Direction == mdl.RankRightLeft {
+					w, h = h, w
+				}
+				v.breadth = w + l.opts.EdgeSep
+				v.depth = h
+				v.anchor = -v.breadth / 2
+			}
+		}
+		prev.out = append(prev.out, v)
+		v.in = append(v.in, prev)
+		dummies = append(dummies, v)
+		prev = v
+	}
+	prev.out = append(prev.out, to)
+	to.in = append(to.in, prev)
+	lk.chain = dummies
+	return dummies
+}
+
+// breakCycles reverses the links that close a cycle.
+func breakCycles(vertices []*vertex, links []*link) {
+	out := make(map[*vertex][]*link)
+	for _, lk := range links {
+		out[lk.from] = append(out[lk.from], lk)
+	}
+	const (
+		white = iota
+		gray
+		black
+	)
+	state := make(map[*vertex]int)
+	var back []*link
+	var visit func(v *vertex)
+	visit = func(v *vertex) {
+		state[v] = gray
+		for _, lk := range out[v] {
+			switch state[lk.to] {
+			case gray:
+				back = append(back, lk)
+			case white:
+				visit(lk.to)
+			}
+		}
+		state[v] = black
+	}
+	for _, v := range vertices {
+		if state[v] == white {
+			visit(v)
+		}
+	}
+	for _, lk := range back {
+		lk.from, lk.to = lk.to, lk.from
+		lk.reversed = !lk.reversed
+	}
+}
+
+// assignRanks assigns ranks to the vertices so that links go from lower to
+// higher ranks. Vertices use even ranks, odd ranks hold edge labels.
+func assignRanks(vertices []*vertex, links []*link) {
+	in := make(map[*vertex]int)
+	out := make(map[*vertex][]*link)
+	for _, lk := range links {
+		in[lk.to]++
+		out[lk.from] = append(out[lk.from], lk)
+	}
+	var queue, sorted []*vertex
+	for _, v := range vertices {
+		if in[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, v)
+		for _, lk := range out[v] {
+			if lk.to.rank < v.rank+2 {
+				lk.to.rank = v.rank + 2
+			}
+			if in[lk.to]--; in[lk.to] == 0 {
+				queue = append(queue, lk.to)
+			}
+		}
+	}
+	// Move sources next to their closest successor.
+	for i := len(sorted) - 1; i >= 0; i-- {
+		v := sorted[i]
+		if len(out[v]) == 0 || hasIncoming(v, links) {
+			continue
+		}
+		r := math.MaxInt
+		for _, lk := range out[v] {
+			r = min(r, lk.to.rank-2)
+		}
+		v.rank = r
+	}
+	lowest := math.MaxInt
+	for _, v := range vertices {
+		lowest = min(lowest, v.rank)
+	}
+	for _, v := range vertices {
+		v.rank -= lowest
+	}
+}
+
+// hasIncoming returns true if a link ends at v.
+func hasIncoming(v *vertex, links []*link) bool {
+	for _, lk := range links {
+		if lk.to == v {
+			return true
+		}
+	}
+	return false
+}
+
+// order groups the vertices by rank and orders each rank to reduce the
+// number of edge crossings.
+func order(vertices []*vertex) [][]*vertex {
+	var maxRank int
+	for _, v := range vertices {
+		maxRank = max(maxRank, v.rank)
+	}
+	layers := make([][]*vertex, maxRank+1)
+	if len(vertices) == 0 {
+		return nil
+	}
+
+	// Initial order: depth first traversal from the vertices in input order.
+	visited := make(map[*vertex]bool)
+	var visit func(v *vertex)
+	visit = func(v *vertex) {
+		if visited[v] {
+			return
+		}
+		visited[v] = true
+		layers[v.rank] = append(layers[v.rank], v)
+		for _, w := range v.out {
+			visit(w)
+		}
+	}
+	for _, v := range vertices {
+		if v.node != nil && len(v.in) == 0 {
+			visit(v)
+		}
+	}
+	for _, v := range vertices {
+		visit(v)
+	}
+	renumber(layers)
+
+	best := snapshot(layers)
+	bestCrossings := crossings(layers)
+	for i := 0; i < 24 && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for r := 1; r < len(layers); r++ {
+				sortByBarycenter(layers[r], func(v *vertex) []*vertex { return v.in })
+			}
+		} else {
+			for r := len(layers) - 2; r >= 0; r-- {
+				sortByBarycenter(layers[r], func(v *vertex) []*vertex { return v.out })
+			}
+		}
+		if c := crossings(layers); c < bestCrossings {
+			best, bestCrossings = snapshot(layers), c
+		}
+	}
+	for r := range layers {
+		layers[r] = best[r]
+	}
+	renumber(layers)
+	return layers
+}
+
+// sortByBarycenter sorts the layer by the average order of the neighbors
+// of its vertices. Vertices with no neighbors keep their order.
+func sortByBarycenter(layer []*vertex, neighbors func(*vertex) []*vertex) {
+	bary := make(map[*vertex]float64, len(layer))
+	for _, v := range layer {
+		nb := neighbors(v)
+		if len(nb) == 0 {
+			bary[v] = float64(v.order)
+			continue
+		}
+		var sum float64
+		for _, w := range nb {
+			sum += float64(w.order)
+		}
+		bary[v] = sum / float64(len(nb))
+	}
+	sort.SliceStable(layer, func(i, j int) bool { return bary[layer[i]] < bary[layer[j]] })
+	for i, v := range layer {
+		v.order = i
+	}
+}
+
+// crossings returns the number of edge crossings between consecutive ranks.
+func crossings(layers [][]*vertex) int {
+	var count int
+	for _, layer := range layers {
+		type segment struct{ a, b int }
+		var segments []segment
+		for _, v := range layer {
+			for _, w := range v.out {
+				segments = append(segments, segment{v.order, w.order})
+			}
+		}
+		for i, s := range segments {
+			for _, t := range segments[i+1:] {
+				if (s.a-t.a)*(s.b-t.b) < 0 {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// snapshot returns a copy of the layers.
+func snapshot(layers [][]*vertex) [][]*vertex {
+	res := make([][]*vertex, len(layers))
+	for r, layer := range layers {
+		res[r] = append([]*vertex(nil), layer...)
+	}
+	return res
+}
+
+// renumber sets the order of the vertices from their index in their layer.
+func renumber(layers [][]*vertex) {
+	for _, layer := range layers {
+		for i, v := range layer {
+			v.order = i
+		}
+	}
+}
+
+// position computes the position of the vertices along the ranks so that
+// vertices are centered on their neighbors while keeping the rank order and
+// the separation between vertices.
+func (l *layouter) position(layers [][]*vertex) {
+	for _, layer := range layers {
+		var pos float64
+		for i, v := range layer {
+			if i > 0 {
+				pos += l.gap(layer[i-1], v)
+			}
+			v.pos = pos
+		}
+	}
+	for i := 0; i < 8; i++ {
+		for r := 1; r < len(layers); r++ {
+			l.align(layers[r], func(v *vertex) []*vertex { return v.in })
+		}
+		for r := len(layers) - 2; r >= 0; r-- {
+			l.align(layers[r], func(v *vertex) []*vertex { return v.out })
+		}
+	}
+	for _, layer := range layers {
+		l.align(layer, func(v *vertex) []*vertex { return append(append([]*vertex(nil), v.in...), v.out...) })
+	}
+}
+
+// align moves the vertices of the layer toward the average position of
+// their neighbors. The result is the average of the layer packed to the
+// left and packed to the right which keeps the vertex separation.
+func (l *layouter) align(layer []*vertex, neighbors func(*vertex) []*vertex) {
+	n := len(layer)
+	desired := make([]float64, n)
+	for i, v := range layer {
+		nb := neighbors(v)
+		if len(nb) == 0 {
+			desired[i] = v.pos
+			continue
+		}
+		var sum float64
+		for _, w := range nb {
+			sum += w.pos + w.anchor
+		}
+		desired[i] = sum/float64(len(nb)) - v.anchor
+	}
+	left := make([]float64, n)
+	right := make([]float64, n)
+	for i := 0; i < n; i++ {
+		left[i] = desired[i]
+		if i > 0 {
+			left[i] = math.Max(left[i], left[i-1]+l.gap(layer[i-1], layer[i]))
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		right[i] = desired[i]
+		if i < n-1 {
+			right[i] = math.Min(right[i], right[i+1]-l.gap(layer[i], layer[i+1]))
+		}
+	}
+	for i, v := range layer {
+		v.pos = (left[i] + right[i]) / 2
+	}
+}
+
+// gap returns the minimum distance between the centers of two consecutive
+// vertices of a rank.
+func (l *layouter) gap(a, b *vertex) float64 {
+	sep := func(v *vertex) float64 {
+		if v.node != nil {
+			return l.opts.NodeSep
+		}
+		return l.opts.EdgeSep
+	}
+	return (a.breadth+b.breadth)/2 + (sep(a)+sep(b))/2
+}
+
+// translate converts the positions relative to cluster c whose top left
+// corner is at origin into absolute positions.
+func (l *layouter) translate(c *Node, origin Point) {
+	l.origin[c] = origin
+	for _, n := range c.Children {
+		n.X += origin.X
+		n.Y += origin.Y
+		if len(n.Children) > 0 {
+			l.translate(n, Point{n.X - n.Width/2, n.Y - n.Height/2})
+		}
+	}
+}
+
+// simplify removes the interior points that are aligned with their
+// neighbors and returns the remaining interior points.
+func simplify(points []Point) []Point {
+	kept := []Point{points[0]}
+	for i := 1; i < len(points)-1; i++ {
+		if !aligned(kept[len(kept)-1], points[i], points[i+1]) {
+			kept = append(kept, points[i])
+		}
+	}
+	return kept[1:]
+}
+
+// aligned returns true if b is on the segment between a and c.
+func aligned(a, b, c Point) bool {
+	dx, dy := c.X-a.X, c.Y-a.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return math.Hypot(b.X-a.X, b.Y-a.Y) < 0.5
+	}
+	return math.Abs(dx*(a.Y-b.Y)-dy*(a.X-b.X))/length < 0.5
+}
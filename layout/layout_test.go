@@ -0,0 +1,221 @@
+package layout
+
+import (
+	"math"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+// testGraph returns a graph with a user that uses a web app which reads
+// from a database, the web app and the database belong to a cluster.
+func testGraph() *Graph {
+	return &Graph{
+		Nodes: []*Node{
+			{ID: "user", Width: 200, Height: 100},
+			{ID: "system", Children: []*Node{
+				{ID: "web", Width: 200, Height: 100},
+				{ID: "db", Width: 200, Height: 100},
+			}},
+		},
+		Edges: []*Edge{
+			{ID: "uses", Source: "user", Destination: "web", LabelWidth: 100, LabelHeight: 40},
+			{ID: "reads", Source: "web", Destination: "db", LabelWidth: 100, LabelHeight: 40},
+		},
+	}
+}
+
+func TestLayout(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Direction mdl.RankDirectionKind
+		Before    func(a, b *Node) bool
+	}{
+		{"top-bottom", mdl.RankTopBottom, func(a, b *Node) bool { return a.Y+a.Height/2 < b.Y-b.Height/2 }},
+		{"bottom-top", mdl.RankBottomTop, func(a, b *Node) bool { return a.Y-a.Height/2 > b.Y+b.Height/2 }},
+		{"left-right", mdl.RankLeftRight, func(a, b *Node) bool { return a.X+a.Width/2 < b.X-b.Width/2 }},
+		{"right-left", mdl.RankRightLeft, func(a, b *Node) bool { return a.X-a.Width/2 > b.X+b.Width/2 }},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			g := testGraph()
+			if err := Layout(g, Options{Direction: c.Direction, ClusterPadding: 25, ClusterLabelHeight: 30}); err != nil {
+				t.Fatal(err)
+			}
+			user, system := g.Nodes[0], g.Nodes[1]
+			web, db := system.Children[0], system.Children[1]
+			if !c.Before(user, system) {
+				t.Errorf("user %+v is not before system %+v", *user, *system)
+			}
+			if !c.Before(web, db) {
+				t.Errorf("web %+v is not before db %+v", *web, *db)
+			}
+			for _, n := range system.Children {
+				if !inside(n, system) {
+					t.Errorf("%s %+v is not inside system %+v", n.ID, *n, *system)
+				}
+			}
+			for _, e := range g.Edges {
+				for _, n := range []*Node{user, web, db} {
+					if overlaps(e, n) {
+						t.Errorf("label of %s at %+v overlaps %s", e.ID, e.Label, n.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLayoutVertices(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{
+			{ID: "a", Width: 100, Height: 50},
+			{ID: "b", Width: 100, Height: 50},
+			{ID: "c", Width: 100, Height: 50},
+		},
+		Edges: []*Edge{
+			{ID: "ab", Source: "a", Destination: "b"},
+			{ID: "bc", Source: "b", Destination: "c"},
+			{ID: "ac", Source: "a", Destination: "c"},
+		},
+	}
+	if err := Layout(g, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	a, b, c := g.Nodes[0], g.Nodes[1], g.Nodes[2]
+	if a.Y >= b.Y || b.Y >= c.Y {
+		t.Fatalf("got ranks a=%v b=%v c=%v, want increasing", a.Y, b.Y, c.Y)
+	}
+	if len(g.Edges[0].Vertices) != 0 || len(g.Edges[1].Vertices) != 0 {
+		t.Errorf("got vertices %v and %v for straight edges, want none", g.Edges[0].Vertices, g.Edges[1].Vertices)
+	}
+	if len(g.Edges[2].Vertices) == 0 {
+		t.Fatal("got no vertex for edge going around b")
+	}
+	for _, p := range g.Edges[2].Vertices {
+		if math.Abs(p.X-b.X) < b.Width/2 {
+			t.Errorf("vertex %+v of edge ac crosses b %+v", p, *b)
+		}
+	}
+}
+
+func TestLayoutClusterPorts(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{
+			{ID: "system", Children: []*Node{
+				{ID: "a", Width: 100, Height: 50},
+				{ID: "b", Width: 100, Height: 50},
+				{ID: "c", Width: 100, Height: 50},
+				{ID: "d", Width: 100, Height: 50},
+			}},
+			{ID: "user", Width: 100, Height: 50},
+		},
+		Edges: []*Edge{
+			{ID: "ab", Source: "a", Destination: "b"},
+			{ID: "bc", Source: "b", Destination: "c"},
+			{ID: "ua", Source: "user", Destination: "a"},
+			{ID: "dc", Source: "d", Destination: "c"},
+			{ID: "ud", Source: "user", Destination: "d"},
+		},
+	}
+	if err := Layout(g, Options{ClusterPadding: 25}); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range g.Edges {
+		src, dest := nodeByID(g, e.Source), nodeByID(g, e.Destination)
+		points := append(append([]Point{{src.X, src.Y}}, e.Vertices...), Point{dest.X, dest.Y})
+		for _, n := range g.Nodes[0].Children {
+			if n == src || n == dest {
+				continue
+			}
+			for i := 1; i < len(points); i++ {
+				if crosses(points[i-1], points[i], n) {
+					t.Errorf("edge %s %v crosses %s %+v", e.ID, points, n.ID, *n)
+				}
+			}
+		}
+	}
+}
+
+func TestLayoutCycle(t *testing.T) {
+	g := &Graph{
+		Nodes: []*Node{{ID: "a", Width: 100, Height: 50}, {ID: "b", Width: 100, Height: 50}},
+		Edges: []*Edge{
+			{ID: "ab", Source: "a", Destination: "b"},
+			{ID: "ba", Source: "b", Destination: "a"},
+		},
+	}
+	if err := Layout(g, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	a, b := g.Nodes[0], g.Nodes[1]
+	if a.Y >= b.Y {
+		t.Errorf("got a at %v and b at %v, want a above b", a.Y, b.Y)
+	}
+	ab, ba := g.Edges[0], g.Edges[1]
+	if ab.Label == ba.Label {
+		t.Errorf("got same label position %+v for both edges", ab.Label)
+	}
+	if len(ba.Vertices) > 0 && ba.Vertices[0].Y < ba.Vertices[len(ba.Vertices)-1].Y {
+		t.Errorf("got vertices %v for edge ba, want bottom to top", ba.Vertices)
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Graph *Graph
+		Error string
+	}{
+		{"unknown source", &Graph{Nodes: []*Node{{ID: "a"}}, Edges: []*Edge{{ID: "e", Source: "x", Destination: "a"}}}, `edge e: unknown source "x"`},
+		{"unknown destination", &Graph{Nodes: []*Node{{ID: "a"}}, Edges: []*Edge{{ID: "e", Source: "a", Destination: "x"}}}, `edge e: unknown destination "x"`},
+		{"cluster", &Graph{Nodes: []*Node{{ID: "a"}, {ID: "c", Children: []*Node{{ID: "b"}}}}, Edges: []*Edge{{ID: "e", Source: "a", Destination: "c"}}}, `edge e: destination "c" is a cluster`},
+		{"duplicate", &Graph{Nodes: []*Node{{ID: "a"}, {ID: "a"}}}, `duplicate node "a"`},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := Layout(c.Graph, Options{})
+			if err == nil || err.Error() != c.Error {
+				t.Errorf("got error %v, want %q", err, c.Error)
+			}
+		})
+	}
+}
+
+func inside(n, c *Node) bool {
+	return n.X-n.Width/2 >= c.X-c.Width/2 && n.X+n.Width/2 <= c.X+c.Width/2 &&
+		n.Y-n.Height/2 >= c.Y-c.Height/2 && n.Y+n.Height/2 <= c.Y+c.Height/2
+}
+
+func overlaps(e *Edge, n *Node) bool {
+	return math.Abs(e.Label.X-n.X) < (e.LabelWidth+n.Width)/2 &&
+		math.Abs(e.Label.Y-n.Y) < (e.LabelHeight+n.Height)/2
+}
+
+func nodeByID(g *Graph, id string) *Node {
+	var find func(nodes []*Node) *Node
+	find = func(nodes []*Node) *Node {
+		for _, n := range nodes {
+			if n.ID == id {
+				return n
+			}
+			if found := find(n.Children); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return find(g.Nodes)
+}
+
+// crosses returns true if the segment between a and b goes through n.
+func crosses(a, b Point, n *Node) bool {
+	for i := 0; i <= 100; i++ {
+		t := float64(i) / 100
+		x, y := a.X+t*(b.X-a.X), a.Y+t*(b.Y-a.Y)
+		if math.Abs(x-n.X) < n.Width/2 && math.Abs(y-n.Y) < n.Height/2 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+package layout
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// Default sizes and separations used to lay out views, they match the
+// defaults of Structurizr.
+const (
+	defaultWidth        = 450
+	defaultHeight       = 300
+	defaultFontSize     = 24
+	defaultLabelWidth   = 200
+	defaultRankSep      = 300
+	defaultNodeSep      = 300
+	defaultEdgeSep      = 10
+	boundaryPadding     = 50
+	boundaryLabelHeight = 60
+	// margin is the distance between the origin and the laid out elements
+	// so that no element ends up at the origin.
+	margin = 50
+)
+
+// bounds is a bounding box.
+type bounds struct {
+	minX, minY, maxX, maxY float64
+}
+
+// Views computes the position of the elements and the vertices of the
+// relationships of the views of d that have not been laid out. The positions
+// are the top left corners of the elements as expected by Structurizr.
+//
+// Views honor the rank direction and separations of their automatic layout
+// settings if any. Elements and relationships that already have a position
+// or vertices keep them, elements added to a view since it was laid out are
+// placed below the existing ones.
+func Views(d *mdl.Design) error {
+	g := query.New(d)
+	done := make(map[*mdl.ViewProps]bool)
+	for _, v := range g.AllViews() {
+		// Filtered views share the properties of their base view.
+		if v.Props == nil || done[v.Props] {
+			continue
+		}
+		done[v.Props] = true
+		if err := View(g, v); err != nil {
+			return fmt.Errorf("view %s: %w", v.Key, err)
+		}
+	}
+	return nil
+}
+
+// View computes the position of the elements of v that have none and the
+// vertices of the relationships between them. See Views.
+func View(g *query.Graph, v *query.View) error {
+	evs := make(map[string]*mdl.ElementView)
+	for _, ev := range v.Props.ElementViews {
+		evs[ev.ID] = ev
+	}
+	var missing bool
+	for _, e := range v.Elements {
+		if !positioned(evs[e.ID]) {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	// Elements whose children are shown in the view are laid out as
+	// clusters, so is the boundary of container, component and code views.
+	parents := make(map[string]*query.Element)
+	switch v.Kind {
+	case query.ViewContainer, query.ViewComponent, query.ViewCode, query.ViewDeployment:
+		for _, e := range v.Elements {
+			if e.Parent != nil {
+				parents[e.ID] = e.Parent
+			}
+		}
+	}
+	clusters := make(map[string]*Node)
+	for _, p := range parents {
+		clusters[p.ID] = &Node{ID: p.ID}
+	}
+	var (
+		graph Graph
+		nodes = make(map[string]*Node)
+		add   func(n *Node, e *query.Element)
+	)
+	added := make(map[*Node]bool)
+	add = func(n *Node, e *query.Element) {
+		if added[n] {
+			return
+		}
+		added[n] = true
+		p := parents[e.ID]
+		if p == nil {
+			graph.Nodes = append(graph.Nodes, n)
+			return
+		}
+		c := clusters[p.ID]
+		c.Children = append(c.Children, n)
+		add(c, p)
+	}
+	var styles *mdl.Styles
+	if g.Design.Views != nil {
+		styles = g.Design.Views.Styles
+	}
+	for _, e := range v.Elements {
+		if c, ok := clusters[e.ID]; ok {
+			add(c, e)
+			continue
+		}
+		w, h := elementSize(styles, e.Tags)
+		n := &Node{ID: e.ID, Width: w, Height: h}
+		nodes[e.ID] = n
+		add(n, e)
+	}
+	edges := make(map[*query.ViewRelationship]*Edge)
+	for _, r := range v.Relationships {
+		if nodes[r.Source.ID] == nil || nodes[r.Destination.ID] == nil {
+			continue
+		}
+		e := &Edge{ID: r.Relationship.ID, Source: r.Source.ID, Destination: r.Destination.ID}
+		e.LabelWidth, e.LabelHeight = labelSize(styles, r)
+		edges[r] = e
+		graph.Edges = append(graph.Edges, e)
+	}
+
+	opts := Options{
+		RankSep:            defaultRankSep,
+		NodeSep:            defaultNodeSep,
+		EdgeSep:            defaultEdgeSep,
+		ClusterPadding:     boundaryPadding,
+		ClusterLabelHeight: boundaryLabelHeight,
+	}
+	vertices := true
+	if al := v.Props.AutoLayout; al != nil {
+		opts.Direction = al.RankDirection
+		if al.RankSep != nil {
+			opts.RankSep = float64(*al.RankSep)
+		}
+		if al.NodeSep != nil {
+			opts.NodeSep = float64(*al.NodeSep)
+		}
+		if al.EdgeSep != nil {
+			opts.EdgeSep = float64(*al.EdgeSep)
+		}
+		if al.Vertices != nil {
+			vertices = *al.Vertices
+		}
+	}
+	if err := Layout(&graph, opts); err != nil {
+		return err
+	}
+
+	// Move the elements that have no position below the others if any.
+	kept, placed := newBounds(), newBounds()
+	for _, e := range v.Elements {
+		n := nodes[e.ID]
+		if n == nil {
+			n = clusters[e.ID]
+		}
+		if ev := evs[e.ID]; positioned(ev) {
+			kept.add(float64(*ev.X), float64(*ev.Y), n.Width, n.Height)
+		} else {
+			placed.add(n.X-n.Width/2, n.Y-n.Height/2, n.Width, n.Height)
+		}
+	}
+	dx, dy := margin-placed.minX, margin-placed.minY
+	if !kept.empty() {
+		dx, dy = kept.minX-placed.minX, kept.maxY+opts.RankSep-placed.minY
+	}
+	for _, e := range v.Elements {
+		ev := evs[e.ID]
+		if ev == nil || positioned(ev) {
+			continue
+		}
+		n := nodes[e.ID]
+		if n == nil {
+			n = clusters[e.ID]
+		}
+		x, y := int(math.Round(n.X-n.Width/2+dx)), int(math.Round(n.Y-n.Height/2+dy))
+		ev.X, ev.Y = &x, &y
+	}
+	if !vertices {
+		return nil
+	}
+	for _, r := range v.Relationships {
+		e := edges[r]
+		if e == nil || len(r.Props.Vertices) > 0 {
+			continue
+		}
+		if positioned(evs[r.Source.ID]) || positioned(evs[r.Destination.ID]) {
+			// The vertices would not match the existing position.
+			continue
+		}
+		for _, p := range e.Vertices {
+			r.Props.Vertices = append(r.Props.Vertices, &mdl.Vertex{X: int(math.Round(p.X + dx)), Y: int(math.Round(p.Y + dy))})
+		}
+	}
+	return nil
+}
+
+// positioned returns true if ev has a position. Structurizr stacks the
+// elements with no position at the origin.
+func positioned(ev *mdl.ElementView) bool {
+	return ev != nil && ev.X != nil && ev.Y != nil && (*ev.X != 0 || *ev.Y != 0)
+}
+
+// elementSize returns the size of the elements with the given tags.
+func elementSize(styles *mdl.Styles, tags string) (width, height float64) {
+	width, height = defaultWidth, defaultHeight
+	if styles == nil {
+		return
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		for _, s := range styles.Elements {
+			if s.Tag != strings.TrimSpace(tag) {
+				continue
+			}
+			if s.Width != nil {
+				width = float64(*s.Width)
+			}
+			if s.Height != nil {
+				height = float64(*s.Height)
+			}
+		}
+	}
+	return
+}
+
+// labelSize returns an estimate of the size of the label of r.
+func labelSize(styles *mdl.Styles, r *query.ViewRelationship) (width, height float64) {
+	text := r.Description
+	if r.Relationship.Technology != "" {
+		text += " [" + r.Relationship.Technology + "]"
+	}
+	if strings.TrimSpace(text) == "" {
+		return 0, 0
+	}
+	fontSize, maxWidth := float64(defaultFontSize), float64(defaultLabelWidth)
+	if styles != nil {
+		for _, tag := range strings.Split(r.Relationship.Tags, ",") {
+			for _, s := range styles.Relationships {
+				if s.Tag != strings.TrimSpace(tag) {
+					continue
+				}
+				if s.FontSize != nil {
+					fontSize = float64(*s.FontSize)
+				}
+				if s.Width != nil {
+					maxWidth = float64(*s.Width)
+				}
+			}
+		}
+	}
+	// Characters are about half as wide as the font size on average.
+	width = float64(len(text)) * fontSize / 2
+	lines := math.Ceil(width / maxWidth)
+	return math.Min(width, maxWidth), lines * fontSize * 1.2
+}
+
+// newBounds returns an empty bounding box.
+func newBounds() *bounds {
+	return &bounds{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+}
+
+// add extends b to include the rectangle with top left corner x, y.
+func (b *bounds) add(x, y, width, height float64) {
+	b.minX, b.minY = math.Min(b.minX, x), math.Min(b.minY, y)
+	b.maxX, b.maxY = math.Max(b.maxX, x+width), math.Max(b.maxY, y+height)
+}
+
+// empty returns true if nothing was added to b.
+func (b *bounds) empty() bool {
+	return b.minX > b.maxX
+}
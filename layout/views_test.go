@@ -0,0 +1,118 @@
+package layout
+
+import (
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person",
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Tags: "Element,Container",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship"},
+						},
+					},
+					{ID: "db", Name: "DB", Tags: "Element,Container,Database"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r2"}},
+				},
+			}},
+			Styles: &mdl.Styles{
+				Elements: []*mdl.ElementStyle{{Tag: "Database", Width: &dbWidth}},
+			},
+		},
+	}
+}
+
+var dbWidth = 600
+
+func TestViews(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Direction mdl.RankDirectionKind
+		Before    func(a, b *mdl.ElementView) bool
+	}{
+		{"default", mdl.RankUndefined, func(a, b *mdl.ElementView) bool { return *a.Y < *b.Y }},
+		{"bottom-top", mdl.RankBottomTop, func(a, b *mdl.ElementView) bool { return *a.Y > *b.Y }},
+		{"left-right", mdl.RankLeftRight, func(a, b *mdl.ElementView) bool { return *a.X < *b.X }},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			d := testDesign()
+			props := d.Views.ContainerViews[0].ViewProps
+			rankSep := 100
+			props.AutoLayout = &mdl.AutoLayout{RankDirection: c.Direction, RankSep: &rankSep}
+			if err := Views(d); err != nil {
+				t.Fatal(err)
+			}
+			user, web, db := props.ElementViews[0], props.ElementViews[1], props.ElementViews[2]
+			for _, ev := range props.ElementViews {
+				if ev.X == nil || ev.Y == nil {
+					t.Fatalf("%s has no position", ev.ID)
+				}
+			}
+			if !c.Before(user, web) || !c.Before(web, db) {
+				t.Errorf("got user at %d,%d, web at %d,%d and db at %d,%d, want them in rank order",
+					*user.X, *user.Y, *web.X, *web.Y, *db.X, *db.Y)
+			}
+			if c.Direction == mdl.RankUndefined && *db.Y-(*web.Y+defaultHeight) < rankSep {
+				t.Errorf("got web at %d and db at %d, want at least %d apart", *web.Y, *db.Y, rankSep)
+			}
+		})
+	}
+}
+
+func TestViewsKeepPositions(t *testing.T) {
+	d := testDesign()
+	props := d.Views.ContainerViews[0].ViewProps
+	x, y := 1000, 50
+	props.ElementViews[0].X, props.ElementViews[0].Y = &x, &y
+	props.RelationshipViews[0].Vertices = []*mdl.Vertex{{X: 10, Y: 20}}
+	if err := Views(d); err != nil {
+		t.Fatal(err)
+	}
+	user, web := props.ElementViews[0], props.ElementViews[1]
+	if *user.X != 1000 || *user.Y != 50 {
+		t.Errorf("got user at %d,%d, want 1000,50", *user.X, *user.Y)
+	}
+	if *web.Y < 50+defaultHeight {
+		t.Errorf("got web at %d, want below user", *web.Y)
+	}
+	if vs := props.RelationshipViews[0].Vertices; len(vs) != 1 || *vs[0] != (mdl.Vertex{X: 10, Y: 20}) {
+		t.Errorf("got vertices %v for r1, want existing vertex", vs)
+	}
+}
+
+func TestViewsNoVertices(t *testing.T) {
+	d := testDesign()
+	props := d.Views.ContainerViews[0].ViewProps
+	props.AutoLayout = &mdl.AutoLayout{Vertices: new(bool)}
+	if err := Views(d); err != nil {
+		t.Fatal(err)
+	}
+	for _, rv := range props.RelationshipViews {
+		if len(rv.Vertices) > 0 {
+			t.Errorf("got vertices %v for %s, want none", rv.Vertices, rv.ID)
+		}
+	}
+}
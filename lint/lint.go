@@ -0,0 +1,243 @@
+/*
+Package lint checks designs against a set of rules that capture common
+modeling mistakes such as containers without technology or elements that do
+not appear in any view.
+
+Each rule has a default severity that may be overridden with a Config. Rules
+whose severity is set to SeverityOff are not reported. Violations record the
+location of the offending element or relationship in the DSL source when
+available.
+*/
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"goa.design/model/expr"
+)
+
+type (
+	// Rule describes a lint rule.
+	Rule struct {
+		// Name is the unique name of the rule used in configurations.
+		Name string
+		// Description describes what the rule checks.
+		Description string
+		// Severity is the default severity of the rule violations.
+		Severity Severity
+		// Check returns the violations of the rule found in the design.
+		Check func(d *expr.Design) []*Violation
+	}
+
+	// Violation describes a rule violation.
+	Violation struct {
+		// Rule is the name of the violated rule.
+		Rule string `json:"rule"`
+		// Severity is the violation severity.
+		Severity Severity `json:"severity"`
+		// Message describes the violation.
+		Message string `json:"message"`
+		// File is the DSL source file that declares the offending element
+		// or relationship if known.
+		File string `json:"file,omitempty"`
+		// Line is the line in File.
+		Line int `json:"line,omitempty"`
+	}
+
+	// Config overrides the default severity of rules indexed by rule name.
+	Config map[string]Severity
+
+	// Severity is the enum for violation severities.
+	Severity int
+)
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	// SeverityInfo reports informational violations.
+	SeverityInfo
+	// SeverityWarning reports violations that should be fixed.
+	SeverityWarning
+	// SeverityError reports violations that must be fixed.
+	SeverityError
+)
+
+// Rules lists all the lint rules in the order they are run.
+var Rules = []*Rule{
+	{
+		Name:        "container-technology",
+		Description: "containers must define a technology",
+		Severity:    SeverityWarning,
+		Check:       checkContainerTechnology,
+	},
+	{
+		Name:        "element-description",
+		Description: "people, software systems, containers and components must have a description",
+		Severity:    SeverityWarning,
+		Check:       checkElementDescription,
+	},
+	{
+		Name:        "orphan-element",
+		Description: "elements must be the source or destination of at least one relationship",
+		Severity:    SeverityWarning,
+		Check:       checkOrphanElement,
+	},
+	{
+		Name:        "relationship-description",
+		Description: "relationships must have a description",
+		Severity:    SeverityWarning,
+		Check:       checkRelationshipDescription,
+	},
+	{
+		Name:        "element-not-in-view",
+		Description: "elements must appear in at least one view",
+		Severity:    SeverityWarning,
+		Check:       checkElementNotInView,
+	},
+	{
+		Name:        "external-system-children",
+		Description: "external software systems must not define containers",
+		Severity:    SeverityError,
+		Check:       checkExternalSystemChildren,
+	},
+}
+
+// Run checks d against all the rules and returns the violations using the
+// severities defined in c.
+func Run(d *expr.Design, c Config) []*Violation {
+	return c.Apply(Check(d))
+}
+
+// Check checks d against all the rules and returns the violations using the
+// rules default severities. Violations are sorted by source location.
+func Check(d *expr.Design) []*Violation {
+	var vs []*Violation
+	for _, r := range Rules {
+		for _, v := range r.Check(d) {
+			v.Rule = r.Name
+			v.Severity = r.Severity
+			vs = append(vs, v)
+		}
+	}
+	sort.SliceStable(vs, func(i, j int) bool {
+		if vs[i].File != vs[j].File {
+			return vs[i].File < vs[j].File
+		}
+		return vs[i].Line < vs[j].Line
+	})
+	return vs
+}
+
+// HasErrors returns true if vs contains a violation with severity error.
+func HasErrors(vs []*Violation) bool {
+	for _, v := range vs {
+		if v.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Set sets the severity of the given rule. It returns an error if there is
+// no rule with the given name or if the severity is invalid.
+func (c Config) Set(rule, severity string) error {
+	if findRule(rule) == nil {
+		names := make([]string, len(Rules))
+		for i, r := range Rules {
+			names[i] = r.Name
+		}
+		return fmt.Errorf("unknown lint rule %q, valid rules are %s", rule, strings.Join(names, ", "))
+	}
+	s, err := ParseSeverity(severity)
+	if err != nil {
+		return err
+	}
+	c[rule] = s
+	return nil
+}
+
+// Apply overrides the severity of the violations with the severities set in
+// c and removes the violations of rules that are turned off.
+func (c Config) Apply(vs []*Violation) []*Violation {
+	var res []*Violation
+	for _, v := range vs {
+		if s, ok := c[v.Rule]; ok {
+			v.Severity = s
+		}
+		if v.Severity == SeverityOff {
+			continue
+		}
+		res = append(res, v)
+	}
+	return res
+}
+
+// String returns the location and message of the violation.
+func (v *Violation) String() string {
+	msg := fmt.Sprintf("%s: %s [%s]", v.Severity, v.Message, v.Rule)
+	if v.File == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s:%d: %s", v.File, v.Line, msg)
+}
+
+// ParseSeverity returns the severity with the given name.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "off":
+		return SeverityOff, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return SeverityOff, fmt.Errorf("invalid severity %q, valid severities are off, info, warning and error", s)
+}
+
+// String returns the name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "off"
+	}
+}
+
+// MarshalJSON replaces the constant value with the proper string value.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON sets the constant from its JSON representation.
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var val string
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	sev, err := ParseSeverity(val)
+	if err != nil {
+		return err
+	}
+	*s = sev
+	return nil
+}
+
+// findRule returns the rule with the given name, nil if there is none.
+func findRule(name string) *Rule {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
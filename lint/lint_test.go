@@ -0,0 +1,137 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"goa.design/model/expr"
+)
+
+func testDesign() *expr.Design {
+	user := &expr.Person{Element: &expr.Element{ID: "user", Name: "User", Description: "A user", File: "model.go", Line: 1}}
+	system := &expr.SoftwareSystem{Element: &expr.Element{ID: "sys", Name: "System", Description: "A system", File: "model.go", Line: 2}}
+	api := &expr.Container{Element: &expr.Element{ID: "api", Name: "API", File: "model.go", Line: 3}, System: system}
+	db := &expr.Container{Element: &expr.Element{ID: "db", Name: "DB", Description: "Database", Technology: "PostgreSQL", File: "model.go", Line: 4}, System: system}
+	system.Containers = expr.Containers{api, db}
+	external := &expr.SoftwareSystem{
+		Element:  &expr.Element{ID: "ext", Name: "External", Description: "An external system", File: "model.go", Line: 5},
+		Location: expr.LocationExternal,
+	}
+	extAPI := &expr.Container{Element: &expr.Element{ID: "extapi", Name: "ExtAPI", Description: "External API", Technology: "Go", File: "model.go", Line: 6}, System: external}
+	external.Containers = expr.Containers{extAPI}
+	user.Relationships = []*expr.Relationship{{Source: user.Element, Destination: api.Element, Description: "Uses", File: "model.go", Line: 7}}
+	api.Relationships = []*expr.Relationship{
+		{Source: api.Element, Destination: extAPI.Element, File: "model.go", Line: 8},
+		{Source: api.Element, Destination: external.Element}, // implied
+	}
+	return &expr.Design{
+		Model: &expr.Model{
+			People:  expr.People{user},
+			Systems: expr.SoftwareSystems{system, external},
+		},
+		Views: &expr.Views{
+			ContainerViews: []*expr.ContainerView{{
+				ViewProps: &expr.ViewProps{
+					Key: "containers",
+					ElementViews: []*expr.ElementView{
+						{Element: user.Element},
+						{Element: system.Element},
+						{Element: api.Element},
+						{Element: external.Element},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	vs := Check(testDesign())
+	var got []string
+	for _, v := range vs {
+		got = append(got, v.Rule+" "+v.Message)
+	}
+	want := []string{
+		`container-technology container "System/API" has no technology`,
+		`element-description container "System/API" has no description`,
+		`orphan-element container "System/DB" is not part of any relationship`,
+		`element-not-in-view container "System/DB" does not appear in any view`,
+		`external-system-children software system "External" is external but defines 1 container(s)`,
+		`element-not-in-view container "External/ExtAPI" does not appear in any view`,
+		`relationship-description relationship from "API" to "ExtAPI" has no description`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !HasErrors(vs) {
+		t.Errorf("got no errors, want external-system-children error")
+	}
+	if vs[0].File != "model.go" || vs[0].Line != 3 {
+		t.Errorf("got location %s:%d, want model.go:3", vs[0].File, vs[0].Line)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+	c := make(Config)
+	if err := c.Set("external-system-children", "warning"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Set("element-not-in-view", "off"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Set("orphan-element", "ERROR"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := c.Set("unknown", "error"); err == nil || !strings.Contains(err.Error(), "unknown lint rule") {
+		t.Errorf("got error %v, want unknown lint rule", err)
+	}
+	if err := c.Set("orphan-element", "fatal"); err == nil || !strings.Contains(err.Error(), "invalid severity") {
+		t.Errorf("got error %v, want invalid severity", err)
+	}
+
+	vs := Run(testDesign(), c)
+	sevs := make(map[string]Severity)
+	for _, v := range vs {
+		sevs[v.Rule] = v.Severity
+	}
+	want := map[string]Severity{
+		"container-technology":     SeverityWarning,
+		"element-description":      SeverityWarning,
+		"orphan-element":           SeverityError,
+		"relationship-description": SeverityWarning,
+		"external-system-children": SeverityWarning,
+	}
+	var rules []string
+	for r := range sevs {
+		rules = append(rules, r)
+	}
+	sort.Strings(rules)
+	if len(sevs) != len(want) {
+		t.Errorf("got rules %v, want %d rules", rules, len(want))
+	}
+	for r, s := range want {
+		if sevs[r] != s {
+			t.Errorf("rule %s: got severity %s, want %s", r, sevs[r], s)
+		}
+	}
+}
+
+func TestSeverityJSON(t *testing.T) {
+	t.Parallel()
+	for _, s := range []Severity{SeverityOff, SeverityInfo, SeverityWarning, SeverityError} {
+		b, err := s.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var got Severity
+		if err := got.UnmarshalJSON(b); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != s {
+			t.Errorf("got %s, want %s", got, s)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package lint
+
+import (
+	"fmt"
+
+	"goa.design/model/expr"
+)
+
+// checkContainerTechnology reports containers that do not define a
+// technology.
+func checkContainerTechnology(d *expr.Design) []*Violation {
+	var vs []*Violation
+	for _, s := range d.Model.Systems {
+		for _, c := range s.Containers {
+			if c.Technology == "" {
+				vs = append(vs, violation(c, "%s has no technology", describe(c)))
+			}
+		}
+	}
+	return vs
+}
+
+// checkElementDescription reports elements that do not have a description.
+func checkElementDescription(d *expr.Design) []*Violation {
+	var vs []*Violation
+	for _, eh := range elements(d) {
+		if eh.GetElement().Description == "" {
+			vs = append(vs, violation(eh, "%s has no description", describe(eh)))
+		}
+	}
+	return vs
+}
+
+// checkOrphanElement reports elements that are not involved in any
+// relationship. An element is involved in a relationship if it or one of its
+// children is the source or destination of the relationship.
+func checkOrphanElement(d *expr.Design) []*Violation {
+	byID := make(map[string]expr.ElementHolder)
+	for _, eh := range elements(d) {
+		byID[eh.GetElement().ID] = eh
+	}
+	related := make(map[string]bool)
+	mark := func(e *expr.Element) {
+		if e == nil {
+			return
+		}
+		related[e.ID] = true
+		eh, ok := byID[e.ID]
+		if !ok {
+			return
+		}
+		for p := expr.Parent(eh); p != nil; p = expr.Parent(p) {
+			related[p.GetElement().ID] = true
+		}
+	}
+	for _, r := range relationships(d) {
+		mark(r.Source)
+		mark(r.Destination)
+	}
+	var vs []*Violation
+	for _, eh := range elements(d) {
+		if !related[eh.GetElement().ID] {
+			vs = append(vs, violation(eh, "%s is not part of any relationship", describe(eh)))
+		}
+	}
+	return vs
+}
+
+// checkRelationshipDescription reports relationships declared in the DSL
+// that do not have a description.
+func checkRelationshipDescription(d *expr.Design) []*Violation {
+	var vs []*Violation
+	for _, r := range relationships(d) {
+		if r.Description != "" || r.File == "" {
+			continue
+		}
+		var dest = "<unknown destination>"
+		if r.Destination != nil {
+			dest = r.Destination.Name
+		}
+		vs = append(vs, &Violation{
+			Message: fmt.Sprintf("relationship from %q to %q has no description", r.Source.Name, dest),
+			File:    r.File,
+			Line:    r.Line,
+		})
+	}
+	return vs
+}
+
+// checkElementNotInView reports elements that do not appear in any view.
+// Elements whose instances appear in a deployment view are considered to
+// appear in that view.
+func checkElementNotInView(d *expr.Design) []*Violation {
+	inView := make(map[string]bool)
+	if d.Views != nil {
+		var props []*expr.ViewProps
+		for _, v := range d.Views.LandscapeViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.ContextViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.ContainerViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.ComponentViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.CodeViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.DynamicViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, v := range d.Views.DeploymentViews {
+			props = append(props, v.ViewProps)
+		}
+		for _, vp := range props {
+			for _, ev := range vp.ElementViews {
+				inView[ev.Element.ID] = true
+				switch inst := expr.Registry[ev.Element.ID].(type) {
+				case *expr.ContainerInstance:
+					inView[inst.ContainerID] = true
+				case *expr.SoftwareSystemInstance:
+					inView[inst.SoftwareSystemID] = true
+				}
+			}
+		}
+	}
+	var vs []*Violation
+	for _, eh := range elements(d) {
+		if !inView[eh.GetElement().ID] {
+			vs = append(vs, violation(eh, "%s does not appear in any view", describe(eh)))
+		}
+	}
+	return vs
+}
+
+// checkExternalSystemChildren reports external software systems that define
+// containers.
+func checkExternalSystemChildren(d *expr.Design) []*Violation {
+	var vs []*Violation
+	for _, s := range d.Model.Systems {
+		if s.Location == expr.LocationExternal && len(s.Containers) > 0 {
+			vs = append(vs, violation(s, "%s is external but defines %d container(s)", describe(s), len(s.Containers)))
+		}
+	}
+	return vs
+}
+
+// elements returns the people, software systems, containers and components
+// of the design.
+func elements(d *expr.Design) []expr.ElementHolder {
+	var res []expr.ElementHolder
+	for _, p := range d.Model.People {
+		res = append(res, p)
+	}
+	for _, s := range d.Model.Systems {
+		res = append(res, s)
+		for _, c := range s.Containers {
+			res = append(res, c)
+			for _, cmp := range c.Components {
+				res = append(res, cmp)
+			}
+		}
+	}
+	return res
+}
+
+// relationships returns the relationships whose source is a person, software
+// system, container, component or code element of the design.
+func relationships(d *expr.Design) []*expr.Relationship {
+	var res []*expr.Relationship
+	for _, p := range d.Model.People {
+		res = append(res, p.Relationships...)
+	}
+	for _, s := range d.Model.Systems {
+		res = append(res, s.Relationships...)
+		for _, c := range s.Containers {
+			res = append(res, c.Relationships...)
+			for _, cmp := range c.Components {
+				res = append(res, cmp.Relationships...)
+				for _, ce := range cmp.CodeElements {
+					res = append(res, ce.Relationships...)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// violation creates a violation located where eh is declared.
+func violation(eh expr.ElementHolder, format string, args ...any) *Violation {
+	e := eh.GetElement()
+	return &Violation{
+		Message: fmt.Sprintf(format, args...),
+		File:    e.File,
+		Line:    e.Line,
+	}
+}
+
+// describe returns the kind and path of the element, e.g. container
+// "System/Container".
+func describe(eh expr.ElementHolder) string {
+	var kind string
+	switch eh.(type) {
+	case *expr.Person:
+		kind = "person"
+	case *expr.SoftwareSystem:
+		kind = "software system"
+	case *expr.Container:
+		kind = "container"
+	case *expr.Component:
+		kind = "component"
+	default:
+		kind = "element"
+	}
+	path := eh.GetElement().Name
+	for p := expr.Parent(eh); p != nil; p = expr.Parent(p) {
+		path = p.GetElement().Name + "/" + path
+	}
+	return fmt.Sprintf("%s %q", kind, path)
+}
@@ -28,6 +28,9 @@ type (
 		// ContainerInstances describe instances of containers deployed in
 		// deployment node.
 		ContainerInstances []*ContainerInstance `json:"containerInstances,omitempty"`
+		// SoftwareSystemInstances describe instances of software systems
+		// deployed in deployment node.
+		SoftwareSystemInstances []*SoftwareSystemInstance `json:"softwareSystemInstances,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
 		// Relationships is the set of relationships from this element to other
@@ -79,11 +82,41 @@ type (
 		InstanceID int `json:"instanceId"`
 		// Environment is the deployment environment of this instance.
 		Environment string `json:"environment"`
+		// DeploymentGroups lists the names of the deployment groups this
+		// instance belongs to.
+		DeploymentGroups []string `json:"deploymentGroups,omitempty"`
 		// HealthChecks is the set of HTTP-based health checks for this
 		// container instance.
 		HealthChecks []*HealthCheck `json:"healthChecks,omitempty"`
 	}
 
+	// SoftwareSystemInstance describes an instance of a software system.
+	SoftwareSystemInstance struct {
+		// ID of element.
+		ID string `json:"id"`
+		// Tags attached to element as comma separated list if any.
+		Tags string `json:"tags,omitempty"`
+		// URL where more information about this element can be found.
+		URL string `json:"url,omitempty"`
+		// Set of arbitrary name-value properties (shown in diagram tooltips).
+		Properties map[string]string `json:"properties,omitempty"`
+		// Relationships is the set of relationships from this element to other
+		// elements.
+		Relationships []*Relationship `json:"relationships,omitempty"`
+		// ID of software system that is instantiated.
+		SoftwareSystemID string `json:"softwareSystemId"`
+		// InstanceID is the number/index of this instance.
+		InstanceID int `json:"instanceId"`
+		// Environment is the deployment environment of this instance.
+		Environment string `json:"environment"`
+		// DeploymentGroups lists the names of the deployment groups this
+		// instance belongs to.
+		DeploymentGroups []string `json:"deploymentGroups,omitempty"`
+		// HealthChecks is the set of HTTP-based health checks for this
+		// software system instance.
+		HealthChecks []*HealthCheck `json:"healthChecks,omitempty"`
+	}
+
 	// HealthCheck is a HTTP-based health check.
 	HealthCheck struct {
 		// Name of health check.
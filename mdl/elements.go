@@ -18,6 +18,8 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group is the name of the group the element belongs to if any.
+		Group string `json:"group,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
 		// Relationships is the set of relationships from this element to other
@@ -39,6 +41,8 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group is the name of the group the element belongs to if any.
+		Group string `json:"group,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
 		// Relationships is the set of relationships from this element to other
@@ -64,6 +68,8 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group is the name of the group the element belongs to if any.
+		Group string `json:"group,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
 		// Relationships is the set of relationships from this element to other
@@ -87,6 +93,32 @@ type (
 		Tags string `json:"tags,omitempty"`
 		// URL where more information about this element can be found.
 		URL string `json:"url,omitempty"`
+		// Group is the name of the group the element belongs to if any.
+		Group string `json:"group,omitempty"`
+		// Set of arbitrary name-value properties (shown in diagram tooltips).
+		Properties map[string]string `json:"properties,omitempty"`
+		// Relationships is the set of relationships from this element to other
+		// elements.
+		Relationships []*Relationship `json:"relationships,omitempty"`
+		// CodeElements list the code elements within the component.
+		CodeElements []*CodeElement `json:"codeElements,omitempty"`
+	}
+
+	// CodeElement represents a code element (e.g. a class, interface or
+	// type) that implements a component.
+	CodeElement struct {
+		// ID of element.
+		ID string `json:"id"`
+		// Name of element.
+		Name string `json:"name,omitempty"`
+		// Description of element if any.
+		Description string `json:"description,omitempty"`
+		// Technology used by element if any.
+		Technology string `json:"technology,omitempty"`
+		// Tags attached to element as comma separated list if any.
+		Tags string `json:"tags,omitempty"`
+		// URL where more information about this element can be found.
+		URL string `json:"url,omitempty"`
 		// Set of arbitrary name-value properties (shown in diagram tooltips).
 		Properties map[string]string `json:"properties,omitempty"`
 		// Relationships is the set of relationships from this element to other
@@ -18,6 +18,9 @@ type (
 		Model *Model `json:"model,omitempty"`
 		// Views contains the views if any.
 		Views *Views `json:"views,omitempty"`
+		// Documentation contains the documentation sections and decisions
+		// if any.
+		Documentation *Documentation `json:"documentation,omitempty"`
 	}
 )
 
@@ -78,6 +81,13 @@ func ModelizeDesign(d *expr.Design) *Design {
 			ContainerID:                cv.ContainerID,
 		}
 	}
+	views.CodeViews = make([]*CodeView, len(v.CodeViews))
+	for i, cv := range v.CodeViews {
+		views.CodeViews[i] = &CodeView{
+			ViewProps:   modelizeProps(cv.Props()),
+			ComponentID: cv.ComponentID,
+		}
+	}
 	views.DynamicViews = make([]*DynamicView, len(v.DynamicViews))
 	for i, dv := range v.DynamicViews {
 		views.DynamicViews[i] = &DynamicView{
@@ -111,14 +121,52 @@ func ModelizeDesign(d *expr.Design) *Design {
 	views.Styles = modelizeStyles(v.Styles)
 
 	return &Design{
-		Name:        d.Name,
-		Description: d.Description,
-		Version:     d.Version,
-		Model:       model,
-		Views:       views,
+		Name:          d.Name,
+		Description:   d.Description,
+		Version:       d.Version,
+		Model:         model,
+		Views:         views,
+		Documentation: modelizeDocumentation(d.Documentation),
 	}
 }
 
+func modelizeDocumentation(doc *expr.Documentation) *Documentation {
+	if doc == nil || len(doc.Sections) == 0 && len(doc.Decisions) == 0 {
+		return nil
+	}
+	elementID := func(e *expr.Element) string {
+		if e == nil {
+			return ""
+		}
+		return e.ID
+	}
+	res := &Documentation{
+		Sections:  make([]*DocumentationSection, len(doc.Sections)),
+		Decisions: make([]*Decision, len(doc.Decisions)),
+	}
+	for i, s := range doc.Sections {
+		res.Sections[i] = &DocumentationSection{
+			Title:     s.Title,
+			Content:   s.Content,
+			Format:    DocFormatKind(s.Format),
+			Order:     i + 1,
+			ElementID: elementID(s.Element),
+		}
+	}
+	for i, d := range doc.Decisions {
+		res.Decisions[i] = &Decision{
+			ID:        d.ID,
+			Date:      d.Date,
+			Status:    DecisionStatusKind(d.Status),
+			Title:     d.Title,
+			Content:   d.Content,
+			Format:    DocFormatKind(d.Format),
+			ElementID: elementID(d.Element),
+		}
+	}
+	return res
+}
+
 func modelizePerson(p *expr.Person) *Person {
 	return &Person{
 		ID:            p.ID,
@@ -126,6 +174,7 @@ func modelizePerson(p *expr.Person) *Person {
 		Description:   p.Description,
 		Tags:          p.Tags,
 		URL:           p.URL,
+		Group:         p.Group,
 		Properties:    p.Properties,
 		Relationships: modelizeRelationships(p.Relationships),
 		Location:      LocationKind(p.Location),
@@ -157,6 +206,7 @@ func modelizeSystem(sys *expr.SoftwareSystem) *SoftwareSystem {
 		Description:   sys.Description,
 		Tags:          sys.Tags,
 		URL:           sys.URL,
+		Group:         sys.Group,
 		Properties:    sys.Properties,
 		Relationships: modelizeRelationships(sys.Relationships),
 		Location:      LocationKind(sys.Location),
@@ -174,6 +224,7 @@ func modelizeContainers(cs []*expr.Container) []*Container {
 			Technology:    c.Technology,
 			Tags:          c.Tags,
 			URL:           c.URL,
+			Group:         c.Group,
 			Properties:    c.Properties,
 			Relationships: modelizeRelationships(c.Relationships),
 			Components:    modelizeComponents(c.Components),
@@ -186,6 +237,25 @@ func modelizeComponents(cs []*expr.Component) []*Component {
 	res := make([]*Component, len(cs))
 	for i, c := range cs {
 		res[i] = &Component{
+			ID:            c.ID,
+			Name:          c.Name,
+			Description:   c.Description,
+			Technology:    c.Technology,
+			Tags:          c.Tags,
+			URL:           c.URL,
+			Group:         c.Group,
+			Properties:    c.Properties,
+			Relationships: modelizeRelationships(c.Relationships),
+			CodeElements:  modelizeCodeElements(c.CodeElements),
+		}
+	}
+	return res
+}
+
+func modelizeCodeElements(cs []*expr.CodeElement) []*CodeElement {
+	res := make([]*CodeElement, len(cs))
+	for i, c := range cs {
+		res[i] = &CodeElement{
 			ID:            c.ID,
 			Name:          c.Name,
 			Description:   c.Description,
@@ -220,29 +290,47 @@ func modelizeDeploymentNodes(dns []*expr.DeploymentNode) []*DeploymentNode {
 		cis := make([]*ContainerInstance, len(dn.ContainerInstances))
 		for i, ci := range dn.ContainerInstances {
 			cis[i] = &ContainerInstance{
-				ID:            ci.ID,
-				Tags:          ci.Tags,
-				URL:           ci.URL,
-				Properties:    ci.Properties,
-				Relationships: modelizeRelationships(ci.Relationships),
-				ContainerID:   ci.ContainerID,
-				InstanceID:    ci.InstanceID,
-				Environment:   ci.Environment,
-				HealthChecks:  modelizeHealthChecks(ci.HealthChecks),
+				ID:               ci.ID,
+				Tags:             ci.Tags,
+				URL:              ci.URL,
+				Properties:       ci.Properties,
+				Relationships:    modelizeRelationships(ci.Relationships),
+				ContainerID:      ci.ContainerID,
+				InstanceID:       ci.InstanceID,
+				Environment:      ci.Environment,
+				DeploymentGroups: ci.DeploymentGroups,
+				HealthChecks:     modelizeHealthChecks(ci.HealthChecks),
+			}
+		}
+		sis := make([]*SoftwareSystemInstance, len(dn.SoftwareSystemInstances))
+		for i, si := range dn.SoftwareSystemInstances {
+			sis[i] = &SoftwareSystemInstance{
+				ID:               si.ID,
+				Tags:             si.Tags,
+				URL:              si.URL,
+				Properties:       si.Properties,
+				Relationships:    modelizeRelationships(si.Relationships),
+				SoftwareSystemID: si.SoftwareSystemID,
+				InstanceID:       si.InstanceID,
+				Environment:      si.Environment,
+				DeploymentGroups: si.DeploymentGroups,
+				HealthChecks:     modelizeHealthChecks(si.HealthChecks),
 			}
 		}
 		res[i] = &DeploymentNode{
-			ID:                  dn.ID,
-			Name:                dn.Name,
-			Description:         dn.Description,
-			Technology:          dn.Technology,
-			Environment:         dn.Environment,
-			Children:            children,
-			InfrastructureNodes: infs,
-			ContainerInstances:  cis,
-			Instances:           dn.Instances,
-			Tags:                dn.Tags,
-			URL:                 dn.URL,
+			ID:                      dn.ID,
+			Name:                    dn.Name,
+			Description:             dn.Description,
+			Technology:              dn.Technology,
+			Environment:             dn.Environment,
+			Children:                children,
+			InfrastructureNodes:     infs,
+			ContainerInstances:      cis,
+			SoftwareSystemInstances: sis,
+			Instances:               dn.Instances,
+			Tags:                    dn.Tags,
+			URL:                     dn.URL,
+			Properties:              dn.Properties,
 		}
 	}
 	return res
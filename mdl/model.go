@@ -44,6 +44,10 @@ func (m *Model) MarshalJSON() ([]byte, error) {
 			sort.Slice(c.Components, func(i, j int) bool { return c.Components[i].Name < c.Components[j].Name })
 			for _, cmp := range c.Components {
 				sort.Slice(cmp.Relationships, func(i, j int) bool { return cmp.Relationships[i].ID < cmp.Relationships[j].ID })
+				sort.Slice(cmp.CodeElements, func(i, j int) bool { return cmp.CodeElements[i].Name < cmp.CodeElements[j].Name })
+				for _, ce := range cmp.CodeElements {
+					sort.Slice(ce.Relationships, func(i, j int) bool { return ce.Relationships[i].ID < ce.Relationships[j].ID })
+				}
 			}
 		}
 	}
@@ -58,5 +62,6 @@ func sortDeploymentNodes(nodes []*DeploymentNode) {
 		sortDeploymentNodes(node.Children)
 		sort.Slice(node.InfrastructureNodes, func(i, j int) bool { return node.InfrastructureNodes[i].Name < node.InfrastructureNodes[j].Name })
 		sort.Slice(node.ContainerInstances, func(i, j int) bool { return node.ContainerInstances[i].ID < node.ContainerInstances[j].ID })
+		sort.Slice(node.SoftwareSystemInstances, func(i, j int) bool { return node.SoftwareSystemInstances[i].ID < node.SoftwareSystemInstances[j].ID })
 	}
 }
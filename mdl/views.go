@@ -17,6 +17,8 @@ type (
 		ContainerViews []*ContainerView `json:"containerViews,omitempty"`
 		// ComponentViews lists the component views.
 		ComponentViews []*ComponentView `json:"componentViews,omitempty"`
+		// CodeViews lists the code views.
+		CodeViews []*CodeView `json:"codeViews,omitempty"`
 		// DynamicViews lists the dynamic views.
 		DynamicViews []*DynamicView `json:"dynamicViews,omitempty"`
 		// DeploymentViews lists the deployment views.
@@ -70,6 +72,13 @@ type (
 		ContainerID string `json:"containerId"`
 	}
 
+	// CodeView describes a code view for a specific component.
+	CodeView struct {
+		*ViewProps
+		// The ID of the component this view is associated with.
+		ComponentID string `json:"componentId"`
+	}
+
 	// DynamicView describes a dynamic view for a specified scope.
 	DynamicView struct {
 		*ViewProps
@@ -276,6 +285,7 @@ type (
 	_contextView    ContextView
 	_containerView  ContainerView
 	_componentView  ComponentView
+	_codeView       CodeView
 	_dynamicView    DynamicView
 	_deploymentView DeploymentView
 	_filteredView   FilteredView
@@ -360,6 +370,7 @@ func (v *Views) MarshalJSON() ([]byte, error) {
 	sort.Slice(v.ContextViews, func(i, j int) bool { return v.ContextViews[i].Key < v.ContextViews[j].Key })
 	sort.Slice(v.ContainerViews, func(i, j int) bool { return v.ContainerViews[i].Key < v.ContainerViews[j].Key })
 	sort.Slice(v.ComponentViews, func(i, j int) bool { return v.ComponentViews[i].Key < v.ComponentViews[j].Key })
+	sort.Slice(v.CodeViews, func(i, j int) bool { return v.CodeViews[i].Key < v.CodeViews[j].Key })
 	sort.Slice(v.DynamicViews, func(i, j int) bool { return v.DynamicViews[i].Key < v.DynamicViews[j].Key })
 	sort.Slice(v.DeploymentViews, func(i, j int) bool { return v.DeploymentViews[i].Key < v.DeploymentViews[j].Key })
 	sort.Slice(v.FilteredViews, func(i, j int) bool { return v.FilteredViews[i].Key < v.FilteredViews[j].Key })
@@ -399,6 +410,14 @@ func (v *ComponentView) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&vv)
 }
 
+// MarshalJSON guarantees the order of elements in generated JSON arrays that
+// correspond to sets.
+func (v *CodeView) MarshalJSON() ([]byte, error) {
+	sortViews(v.ViewProps)
+	vv := _codeView(*v)
+	return json.Marshal(&vv)
+}
+
 // MarshalJSON guarantees the order of elements in generated JSON arrays that
 // correspond to sets.
 func (v *DynamicView) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,234 @@
+package mermaid
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// WriteFlowchart writes the view v of the design indexed by g to w as a
+// Mermaid flowchart. Boundaries and deployment nodes are rendered as
+// subgraphs and the styles of the design are rendered as classes.
+func WriteFlowchart(w io.Writer, g *query.Graph, v *query.View) error {
+	mw := newWriter(g, v)
+	mw.line("---")
+	mw.line("title: %s", strconv.Quote(text(title(v))))
+	mw.line("---")
+	mw.line("flowchart %s", direction(v))
+	mw.indent++
+	mw.writeFlowchartElements()
+	mw.sep()
+	mw.writeFlowchartRelationships()
+	mw.sep()
+	mw.writeFlowchartStyles()
+	return mw.flush(w)
+}
+
+// direction returns the flowchart direction corresponding to the view
+// automatic layout rank direction.
+func direction(v *query.View) string {
+	if v.Props == nil || v.Props.AutoLayout == nil {
+		return "TB"
+	}
+	switch v.Props.AutoLayout.RankDirection {
+	case mdl.RankBottomTop:
+		return "BT"
+	case mdl.RankLeftRight:
+		return "LR"
+	case mdl.RankRightLeft:
+		return "RL"
+	default:
+		return "TB"
+	}
+}
+
+// writeFlowchartElements writes the nodes and subgraphs of the view.
+func (mw *writer) writeFlowchartElements() {
+	v := mw.v
+	if v.Kind == query.ViewDeployment {
+		for _, e := range mw.roots() {
+			mw.writeFlowchartDeploymentElement(e)
+		}
+		return
+	}
+	inside, outside := mw.partition()
+	if len(inside) > 0 {
+		var id, name string
+		if v.Kind == query.ViewLandscape || v.Kind == query.ViewContext {
+			id, name = mw.unique("enterprise"), mw.g.Design.Model.Enterprise.Name
+		} else {
+			id, name = mw.unique(alias(v.Scope.Path)+"_boundary"), v.Scope.Name
+		}
+		mw.line("subgraph %s[%s]", id, label("<b>"+html(name)+"</b>"))
+		mw.indent++
+		for _, e := range inside {
+			mw.writeNode(e, e)
+		}
+		mw.indent--
+		mw.line("end")
+	}
+	for _, e := range outside {
+		mw.writeNode(e, e)
+	}
+}
+
+// writeFlowchartDeploymentElement writes the subgraph of a deployment node and
+// the nodes of the elements it contains.
+func (mw *writer) writeFlowchartDeploymentElement(e *query.Element) {
+	switch e.Kind {
+	case query.KindDeploymentNode:
+		children := mw.children(e)
+		if len(children) == 0 {
+			mw.writeNode(e, e)
+			return
+		}
+		mw.line("subgraph %s[%s]", mw.aliases[e.ID], label(description(e, "")))
+		mw.indent++
+		for _, c := range children {
+			mw.writeFlowchartDeploymentElement(c)
+		}
+		mw.indent--
+		mw.line("end")
+	case query.KindContainerInstance, query.KindSoftwareSystemInstance:
+		if e.InstanceOf != nil {
+			mw.writeNode(e, e.InstanceOf)
+		}
+	default:
+		mw.writeNode(e, e)
+	}
+}
+
+// writeNode writes the node of the view element e that renders el.
+func (mw *writer) writeNode(e, el *query.Element) {
+	start, stop := nodeShape(el.Kind, mw.shape(e))
+	mw.line("%s%s%s%s", mw.aliases[e.ID], start, label(description(el, el.Description)), stop)
+}
+
+// description returns the HTML label of a node made of the element name, its
+// kind and technology and desc.
+func description(e *query.Element, desc string) string {
+	kind := e.Kind.String()
+	if e.Technology != "" {
+		kind += ": " + e.Technology
+	}
+	res := "<b>" + html(e.Name) + "</b><br/>[" + html(kind) + "]"
+	if desc != "" {
+		res += "<br/><br/>" + html(desc)
+	}
+	return res
+}
+
+// nodeShape returns the flowchart delimiters of the node of an element of the
+// given kind and shape.
+func nodeShape(kind query.ElementKind, shape mdl.ShapeKind) (string, string) {
+	if shape == mdl.ShapeUndefined && kind == query.KindPerson {
+		shape = mdl.ShapePerson
+	}
+	switch shape {
+	case mdl.ShapeCylinder:
+		return "[(", ")]"
+	case mdl.ShapeRoundedBox:
+		return "(", ")"
+	case mdl.ShapeCircle, mdl.ShapeEllipse:
+		return "((", "))"
+	case mdl.ShapeHexagon:
+		return "{{", "}}"
+	case mdl.ShapePerson, mdl.ShapeRobot:
+		return "([", "])"
+	case mdl.ShapePipe, mdl.ShapeComponent:
+		return "[[", "]]"
+	default:
+		return "[", "]"
+	}
+}
+
+// writeFlowchartRelationships writes the edges of the view. The descriptions
+// of the relationships of dynamic views are prefixed with their order.
+func (mw *writer) writeFlowchartRelationships() {
+	for i, r := range mw.v.Relationships {
+		desc := html(r.Description)
+		if mw.v.Kind == query.ViewDynamic {
+			order := r.Order
+			if order == "" {
+				order = fmt.Sprint(i + 1)
+			}
+			desc = html(order) + ". " + desc
+		}
+		if t := r.Relationship.Technology; t != "" {
+			desc += "<br/>[" + html(t) + "]"
+		}
+		arrow := "-->"
+		if s := mw.relationshipStyle(r.Relationship); s.Dashed != nil && *s.Dashed {
+			arrow = "-.->"
+		}
+		src, dest := mw.aliases[r.Source.ID], mw.aliases[r.Destination.ID]
+		if strings.TrimSpace(desc) == "" {
+			mw.line("%s %s %s", src, arrow, dest)
+			continue
+		}
+		mw.line("%s %s|%s| %s", src, arrow, label(desc), dest)
+	}
+}
+
+// writeFlowchartStyles writes a class for each element style of the design
+// used by the view and the linkStyle statements for the colors of the
+// relationships. Classes are written in the order of the styles so that later
+// styles override earlier ones.
+func (mw *writer) writeFlowchartStyles() {
+	if mw.styles == nil {
+		return
+	}
+	for _, s := range mw.styles.Elements {
+		var props []string
+		if s.Background != "" {
+			props = append(props, "fill:"+s.Background)
+		}
+		if s.Color != "" {
+			props = append(props, "color:"+s.Color)
+		}
+		if s.Stroke != "" {
+			props = append(props, "stroke:"+s.Stroke)
+		}
+		if len(props) == 0 {
+			continue
+		}
+		var ids []string
+		for _, e := range mw.v.Elements {
+			if contains(tags(e), s.Tag) {
+				ids = append(ids, mw.aliases[e.ID])
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		class := "tag_" + alias(s.Tag)
+		mw.line("classDef %s %s", class, strings.Join(props, ","))
+		mw.line("class %s %s", strings.Join(ids, ","), class)
+	}
+	for i, r := range mw.v.Relationships {
+		if s := mw.relationshipStyle(r.Relationship); s.Color != "" {
+			mw.line("linkStyle %d stroke:%s,color:%s", i, s.Color, s.Color)
+		}
+	}
+}
+
+// label returns s as a quoted flowchart label.
+func label(s string) string {
+	return `"` + s + `"`
+}
+
+// html escapes the characters of s that have a special meaning in flowchart
+// labels and replaces new lines with line breaks.
+func html(s string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+		"\r\n", "<br/>",
+		"\n", "<br/>",
+	).Replace(s)
+}
@@ -0,0 +1,515 @@
+/*
+Package mermaid renders the views of a design as Mermaid diagrams that GitHub,
+GitLab and other Markdown renderers display inline.
+
+System landscape and system context views are rendered as "C4Context"
+diagrams, container, component, dynamic and deployment views as
+"C4Container", "C4Component", "C4Dynamic" and "C4Deployment" diagrams. Views
+that have no C4 diagram counterpart such as code views are rendered as
+flowcharts. WriteFlowchart renders any view as a flowchart.
+
+Mermaid C4 diagrams do not support tags, the colors defined by the element
+and relationship styles of the design are applied to each element and
+relationship instead.
+
+	g := query.New(design)
+	for _, v := range g.AllViews() {
+	    var buf bytes.Buffer
+	    if err := mermaid.WriteView(&buf, g, v); err != nil {
+	        return err
+	    }
+	    os.WriteFile(v.Key+".mmd", buf.Bytes(), 0644)
+	}
+*/
+package mermaid
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// writer writes the Mermaid representation of a view.
+type writer struct {
+	b      strings.Builder
+	indent int
+	g      *query.Graph
+	v      *query.View
+	styles *mdl.Styles
+	// aliases maps element IDs to Mermaid identifiers.
+	aliases map[string]string
+	// used lists the identifiers that are already taken.
+	used map[string]bool
+}
+
+// WriteView writes the Mermaid C4 diagram of the view v of the design indexed
+// by g to w. Views that cannot be represented with a Mermaid C4 diagram are
+// written as flowcharts.
+func WriteView(w io.Writer, g *query.Graph, v *query.View) error {
+	kind := diagram(v.Kind)
+	if kind == "" {
+		return WriteFlowchart(w, g, v)
+	}
+	mw := newWriter(g, v)
+	mw.line("%s", kind)
+	mw.indent++
+	mw.line("title %s", text(title(v)))
+	mw.sep()
+	mw.writeC4Elements()
+	mw.sep()
+	mw.writeC4Relationships()
+	mw.sep()
+	mw.writeC4Styles()
+	return mw.flush(w)
+}
+
+// newWriter returns a writer for v that assigns a unique identifier to each
+// element of the view.
+func newWriter(g *query.Graph, v *query.View) *writer {
+	mw := &writer{
+		g:       g,
+		v:       v,
+		aliases: make(map[string]string),
+		used:    make(map[string]bool),
+	}
+	if g.Design != nil && g.Design.Views != nil {
+		mw.styles = g.Design.Views.Styles
+	}
+	for _, e := range v.Elements {
+		mw.aliases[e.ID] = mw.unique(alias(e.Path))
+	}
+	return mw
+}
+
+// diagram returns the Mermaid C4 diagram type used to render views of the
+// given kind, the empty string if there is none.
+func diagram(k query.ViewKind) string {
+	switch k {
+	case query.ViewLandscape, query.ViewContext:
+		return "C4Context"
+	case query.ViewContainer:
+		return "C4Container"
+	case query.ViewComponent:
+		return "C4Component"
+	case query.ViewDynamic:
+		return "C4Dynamic"
+	case query.ViewDeployment:
+		return "C4Deployment"
+	default:
+		return ""
+	}
+}
+
+// title returns the title of the view, defaults to the view kind followed by
+// the name of the view scope and environment.
+func title(v *query.View) string {
+	if v.Title != "" {
+		return v.Title
+	}
+	parts := []string{"[" + v.Kind.String() + "]"}
+	if v.Scope != nil {
+		parts = append(parts, v.Scope.Name)
+	}
+	if v.Environment != "" {
+		parts = append(parts, v.Environment)
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeC4Elements writes the elements of the view. Elements are nested in the
+// boundary of the view if any and in their parent deployment node in
+// deployment views.
+func (mw *writer) writeC4Elements() {
+	v := mw.v
+	if v.Kind == query.ViewDeployment {
+		for _, e := range mw.roots() {
+			mw.writeC4DeploymentElement(e)
+		}
+		return
+	}
+	inside, outside := mw.partition()
+	if len(inside) > 0 {
+		switch {
+		case v.Kind == query.ViewLandscape || v.Kind == query.ViewContext:
+			mw.open("Enterprise_Boundary(%s, %s)", mw.unique("enterprise"), quote(mw.g.Design.Model.Enterprise.Name))
+		case v.Scope.Kind == query.KindSoftwareSystem:
+			mw.open("System_Boundary(%s, %s)", mw.unique(alias(v.Scope.Path)+"_boundary"), quote(v.Scope.Name))
+		default:
+			mw.open("Container_Boundary(%s, %s)", mw.unique(alias(v.Scope.Path)+"_boundary"), quote(v.Scope.Name))
+		}
+		for _, e := range inside {
+			mw.writeC4Element(e, e)
+		}
+		mw.close()
+	}
+	for _, e := range outside {
+		mw.writeC4Element(e, e)
+	}
+}
+
+// writeC4Element writes the C4 statement for the person, software system,
+// container or component el rendered as the view element e.
+func (mw *writer) writeC4Element(e, el *query.Element) {
+	macro := macroName(el.Kind, mw.shape(e), location(el) == mdl.LocationExternal)
+	args := []string{mw.aliases[e.ID], quote(el.Name)}
+	if el.Kind != query.KindPerson && el.Kind != query.KindSoftwareSystem {
+		args = append(args, quote(el.Technology))
+	}
+	args = append(args, quote(el.Description))
+	mw.line("%s(%s)", macro, strings.Join(args, ", "))
+}
+
+// writeC4DeploymentElement writes the C4 statements for a deployment element
+// and the elements it contains. Mermaid does not support empty boundaries so
+// deployment nodes that do not contain any element of the view and
+// infrastructure nodes are rendered as containers.
+func (mw *writer) writeC4DeploymentElement(e *query.Element) {
+	switch e.Kind {
+	case query.KindDeploymentNode, query.KindInfrastructureNode:
+		args := []string{mw.aliases[e.ID], quote(e.Name), quote(e.Technology), quote(e.Description)}
+		children := mw.children(e)
+		if len(children) == 0 {
+			mw.line("Container(%s)", strings.Join(args, ", "))
+			return
+		}
+		mw.open("Deployment_Node(%s)", strings.Join(args, ", "))
+		for _, c := range children {
+			mw.writeC4DeploymentElement(c)
+		}
+		mw.close()
+	case query.KindContainerInstance, query.KindSoftwareSystemInstance:
+		if e.InstanceOf != nil {
+			mw.writeC4Element(e, e.InstanceOf)
+		}
+	default:
+		mw.writeC4Element(e, e)
+	}
+}
+
+// macroName returns the name of the C4 statement used to render an element of
+// the given kind and shape.
+func macroName(kind query.ElementKind, shape mdl.ShapeKind, external bool) string {
+	var name string
+	switch kind {
+	case query.KindPerson:
+		name = "Person"
+	case query.KindSoftwareSystem:
+		name = "System"
+	case query.KindContainer:
+		name = "Container"
+	default:
+		name = "Component"
+	}
+	if kind != query.KindPerson {
+		switch shape {
+		case mdl.ShapeCylinder:
+			name += "Db"
+		case mdl.ShapePipe:
+			name += "Queue"
+		}
+	}
+	if external {
+		name += "_Ext"
+	}
+	return name
+}
+
+// writeC4Relationships writes the relationships of the view. Mermaid numbers
+// the relationships of dynamic views in the order they are written, the
+// relationships of the view are already sorted by order.
+func (mw *writer) writeC4Relationships() {
+	for i, r := range mw.v.Relationships {
+		args := []string{mw.aliases[r.Source.ID], mw.aliases[r.Destination.ID], quote(r.Description)}
+		if r.Relationship.Technology != "" {
+			args = append(args, quote(r.Relationship.Technology))
+		}
+		if mw.v.Kind == query.ViewDynamic {
+			order := r.Order
+			if order == "" {
+				order = fmt.Sprint(i + 1)
+			}
+			mw.line("RelIndex(%s, %s)", order, strings.Join(args, ", "))
+			continue
+		}
+		mw.line("Rel(%s)", strings.Join(args, ", "))
+	}
+}
+
+// writeC4Styles writes the UpdateElementStyle and UpdateRelStyle statements
+// that apply the colors of the styles of the design.
+func (mw *writer) writeC4Styles() {
+	for _, e := range mw.v.Elements {
+		s := mw.elementStyle(e)
+		var args []string
+		if s.Background != "" {
+			args = append(args, "$bgColor="+quote(s.Background))
+		}
+		if s.Color != "" {
+			args = append(args, "$fontColor="+quote(s.Color))
+		}
+		if s.Stroke != "" {
+			args = append(args, "$borderColor="+quote(s.Stroke))
+		}
+		if len(args) > 0 {
+			mw.line("UpdateElementStyle(%s, %s)", mw.aliases[e.ID], strings.Join(args, ", "))
+		}
+	}
+	for _, r := range mw.v.Relationships {
+		if s := mw.relationshipStyle(r.Relationship); s.Color != "" {
+			mw.line("UpdateRelStyle(%s, %s, $textColor=%s, $lineColor=%s)",
+				mw.aliases[r.Source.ID], mw.aliases[r.Destination.ID], quote(s.Color), quote(s.Color))
+		}
+	}
+}
+
+// partition returns the elements of the view rendered inside the view
+// boundary and the other elements. The boundary is the enterprise boundary
+// in system landscape and system context views and the boundary of the view
+// scope in other views.
+func (mw *writer) partition() (inside, outside []*query.Element) {
+	for _, e := range mw.v.Elements {
+		if mw.inBoundary(e) {
+			inside = append(inside, e)
+		} else {
+			outside = append(outside, e)
+		}
+	}
+	return
+}
+
+// inBoundary returns true if e must be rendered inside the boundary of the
+// view.
+func (mw *writer) inBoundary(e *query.Element) bool {
+	v := mw.v
+	switch v.Kind {
+	case query.ViewLandscape, query.ViewContext:
+		return mw.enterpriseBoundaryVisible() && location(e) != mdl.LocationExternal
+	case query.ViewContainer, query.ViewComponent, query.ViewDynamic, query.ViewCode:
+		return v.Scope != nil && e.Parent == v.Scope
+	}
+	return false
+}
+
+// enterpriseBoundaryVisible returns true if the model defines an enterprise
+// and the view does not hide its boundary.
+func (mw *writer) enterpriseBoundaryVisible() bool {
+	d := mw.g.Design
+	if d.Model == nil || d.Model.Enterprise == nil || d.Views == nil {
+		return false
+	}
+	for _, lv := range d.Views.LandscapeViews {
+		if lv.Key == mw.v.Key {
+			return lv.EnterpriseBoundaryVisible == nil || *lv.EnterpriseBoundaryVisible
+		}
+	}
+	for _, cv := range d.Views.ContextViews {
+		if cv.Key == mw.v.Key {
+			return cv.EnterpriseBoundaryVisible == nil || *cv.EnterpriseBoundaryVisible
+		}
+	}
+	return true
+}
+
+// roots returns the elements of a deployment view that are not contained in
+// another element of the view.
+func (mw *writer) roots() []*query.Element {
+	var res []*query.Element
+	for _, e := range mw.v.Elements {
+		if mw.v.Enclosing(e) == nil {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// children returns the elements of the view whose closest ancestor in the
+// view is e.
+func (mw *writer) children(e *query.Element) []*query.Element {
+	var res []*query.Element
+	for _, c := range mw.v.Elements {
+		if mw.v.Enclosing(c) == e {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// tags returns the tags of e, for container and software system instances
+// the tags of the instantiated element come first.
+func tags(e *query.Element) []string {
+	tags := strings.Split(e.Tags, ",")
+	if e.InstanceOf != nil {
+		tags = append(strings.Split(e.InstanceOf.Tags, ","), tags...)
+	}
+	for i, t := range tags {
+		tags[i] = strings.TrimSpace(t)
+	}
+	return tags
+}
+
+// elementStyle returns the style of e obtained by merging the styles of its
+// tags, later styles override earlier ones.
+func (mw *writer) elementStyle(e *query.Element) *mdl.ElementStyle {
+	res := &mdl.ElementStyle{}
+	if mw.styles == nil {
+		return res
+	}
+	tags := tags(e)
+	for _, s := range mw.styles.Elements {
+		if !contains(tags, s.Tag) {
+			continue
+		}
+		if s.Background != "" {
+			res.Background = s.Background
+		}
+		if s.Color != "" {
+			res.Color = s.Color
+		}
+		if s.Stroke != "" {
+			res.Stroke = s.Stroke
+		}
+		if s.Shape != mdl.ShapeUndefined {
+			res.Shape = s.Shape
+		}
+	}
+	return res
+}
+
+// shape returns the shape of e defined by the styles of its tags.
+func (mw *writer) shape(e *query.Element) mdl.ShapeKind {
+	return mw.elementStyle(e).Shape
+}
+
+// relationshipStyle returns the style of r obtained by merging the styles of
+// its tags, later styles override earlier ones.
+func (mw *writer) relationshipStyle(r *mdl.Relationship) *mdl.RelationshipStyle {
+	res := &mdl.RelationshipStyle{}
+	if mw.styles == nil {
+		return res
+	}
+	tags := strings.Split(r.Tags, ",")
+	for i, t := range tags {
+		tags[i] = strings.TrimSpace(t)
+	}
+	for _, s := range mw.styles.Relationships {
+		if !contains(tags, s.Tag) {
+			continue
+		}
+		if s.Color != "" {
+			res.Color = s.Color
+		}
+		if s.Dashed != nil {
+			res.Dashed = s.Dashed
+		}
+	}
+	return res
+}
+
+// contains returns true if tags contains tag.
+func contains(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// location returns the location of e, the location of the software system
+// that contains e for containers and components.
+func location(e *query.Element) mdl.LocationKind {
+	for el := e; el != nil; el = el.Parent {
+		switch v := el.Value.(type) {
+		case *mdl.Person:
+			return v.Location
+		case *mdl.SoftwareSystem:
+			return v.Location
+		}
+	}
+	return mdl.LocationUndefined
+}
+
+// unique returns a that is not used yet, it appends a number to a if
+// needed.
+func (mw *writer) unique(a string) string {
+	res := a
+	for i := 2; mw.used[res]; i++ {
+		res = fmt.Sprintf("%s_%d", a, i)
+	}
+	mw.used[res] = true
+	return res
+}
+
+// alias returns a Mermaid identifier derived from s.
+func alias(s string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range s {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	res := strings.TrimSuffix(b.String(), "_")
+	switch {
+	case res == "":
+		return "element"
+	case res[0] >= '0' && res[0] <= '9':
+		return "e_" + res
+	case res == "end":
+		// "end" closes subgraphs in flowcharts.
+		return "end_"
+	}
+	return res
+}
+
+// quote returns s as a Mermaid C4 string literal. Double quotes cannot be
+// escaped and are replaced with single quotes.
+func quote(s string) string {
+	return `"` + text(strings.ReplaceAll(s, `"`, "'")) + `"`
+}
+
+// text replaces the new lines in s with spaces, Mermaid C4 statements must
+// fit on one line and texts are wrapped automatically.
+func text(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\r\n", "\n")), " ")
+}
+
+// line writes a line of Mermaid indented at the current level.
+func (mw *writer) line(format string, args ...any) {
+	mw.b.WriteString(strings.Repeat("    ", mw.indent))
+	fmt.Fprintf(&mw.b, format, args...)
+	mw.b.WriteByte('\n')
+}
+
+// open writes a line that opens a block.
+func (mw *writer) open(format string, args ...any) {
+	mw.line(format+" {", args...)
+	mw.indent++
+}
+
+// close closes the current block.
+func (mw *writer) close() {
+	mw.indent--
+	mw.line("}")
+}
+
+// sep writes an empty line unless the previous line is empty.
+func (mw *writer) sep() {
+	if s := mw.b.String(); strings.HasSuffix(s, "\n\n") {
+		return
+	}
+	mw.b.WriteByte('\n')
+}
+
+// flush writes the diagram to w without trailing empty lines.
+func (mw *writer) flush(w io.Writer) error {
+	_, err := io.WriteString(w, strings.TrimRight(mw.b.String(), "\n")+"\n")
+	return err
+}
@@ -0,0 +1,241 @@
+package mermaid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			Enterprise: &mdl.Enterprise{Name: "Acme"},
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Description: `The "user"`, Tags: "Element,Person", Location: mdl.LocationExternal,
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", Tags: "Relationship,Synchronous"},
+					{ID: "r4", SourceID: "user", DestinationID: "shop", Description: "Buys from", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Description: "Sells things", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container,Frontend",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship,Asynchronous"},
+						},
+					},
+					{ID: "db", Name: "DB", Technology: "PostgreSQL", Tags: "Element,Container,Database"},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID: "node", Name: "Server", Technology: "Linux", Environment: "Production", Tags: "Element,Deployment Node",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "webInst", ContainerID: "web", Tags: "Element,Container Instance", Relationships: []*mdl.Relationship{
+						{ID: "r3", SourceID: "webInst", DestinationID: "dbInst", Description: "Reads", Tags: "Relationship,Asynchronous", LinkedRelationshipID: "r2"},
+					}},
+					{ID: "dbInst", ContainerID: "db", Tags: "Element,Container Instance"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContextViews: []*mdl.ContextView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Context",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "shop"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r4"}},
+				},
+			}},
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					Title:             "Shop containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r2"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankLeftRight},
+				},
+			}},
+			DynamicViews: []*mdl.DynamicView{{
+				ElementID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Checkout",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r2", Order: "2"}, {ID: "r1", Order: "1", Description: "Checks out"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankBottomTop},
+				},
+			}},
+			DeploymentViews: []*mdl.DeploymentView{{
+				Environment: "Production",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Deployment",
+					ElementViews:      []*mdl.ElementView{{ID: "node"}, {ID: "webInst"}, {ID: "dbInst"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r3"}},
+				},
+			}},
+			Styles: &mdl.Styles{
+				Elements: []*mdl.ElementStyle{
+					{Tag: "Person", Background: "#08427b", Color: "#ffffff"},
+					{Tag: "Database", Shape: mdl.ShapeCylinder, Background: "#ffffff"},
+				},
+				Relationships: []*mdl.RelationshipStyle{{Tag: "Asynchronous", Dashed: &dashed, Color: "#ff0000"}},
+			},
+		},
+	}
+}
+
+var dashed = true
+
+func TestWriteView(t *testing.T) {
+	cases := map[string]string{
+		"Context": `C4Context
+    title [System Context] Shop
+
+    Enterprise_Boundary(enterprise, "Acme") {
+        System(Shop, "Shop", "Sells things")
+    }
+    Person_Ext(User, "User", "The 'user'")
+
+    Rel(User, Shop, "Buys from")
+
+    UpdateElementStyle(User, $bgColor="#08427b", $fontColor="#ffffff")
+`,
+		"Containers": `C4Container
+    title Shop containers
+
+    System_Boundary(Shop_boundary, "Shop") {
+        Container(Shop_Web, "Web", "Go", "")
+        ContainerDb(Shop_DB, "DB", "PostgreSQL", "")
+    }
+    Person_Ext(User, "User", "The 'user'")
+
+    Rel(User, Shop_Web, "Browses", "HTTPS")
+    Rel(Shop_Web, Shop_DB, "Reads")
+
+    UpdateElementStyle(User, $bgColor="#08427b", $fontColor="#ffffff")
+    UpdateElementStyle(Shop_DB, $bgColor="#ffffff")
+    UpdateRelStyle(Shop_Web, Shop_DB, $textColor="#ff0000", $lineColor="#ff0000")
+`,
+		"Checkout": `C4Dynamic
+    title [Dynamic] Shop
+
+    System_Boundary(Shop_boundary, "Shop") {
+        Container(Shop_Web, "Web", "Go", "")
+        ContainerDb(Shop_DB, "DB", "PostgreSQL", "")
+    }
+    Person_Ext(User, "User", "The 'user'")
+
+    RelIndex(1, User, Shop_Web, "Checks out", "HTTPS")
+    RelIndex(2, Shop_Web, Shop_DB, "Reads")
+
+    UpdateElementStyle(User, $bgColor="#08427b", $fontColor="#ffffff")
+    UpdateElementStyle(Shop_DB, $bgColor="#ffffff")
+    UpdateRelStyle(Shop_Web, Shop_DB, $textColor="#ff0000", $lineColor="#ff0000")
+`,
+		"Deployment": `C4Deployment
+    title [Deployment] Production
+
+    Deployment_Node(Production_Server, "Server", "Linux", "") {
+        Container(Production_Server_Web, "Web", "Go", "")
+        ContainerDb(Production_Server_DB, "DB", "PostgreSQL", "")
+    }
+
+    Rel(Production_Server_Web, Production_Server_DB, "Reads")
+
+    UpdateElementStyle(Production_Server_DB, $bgColor="#ffffff")
+    UpdateRelStyle(Production_Server_Web, Production_Server_DB, $textColor="#ff0000", $lineColor="#ff0000")
+`,
+	}
+	g := query.New(testDesign())
+	for key, want := range cases {
+		var buf bytes.Buffer
+		if err := WriteView(&buf, g, g.View(key)); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", key, got, want)
+		}
+	}
+}
+
+func TestWriteFlowchart(t *testing.T) {
+	cases := map[string]string{
+		"Checkout": `---
+title: "[Dynamic] Shop"
+---
+flowchart BT
+    subgraph Shop_boundary["<b>Shop</b>"]
+        Shop_Web["<b>Web</b><br/>[Container: Go]"]
+        Shop_DB[("<b>DB</b><br/>[Container: PostgreSQL]")]
+    end
+    User(["<b>User</b><br/>[Person]<br/><br/>The #quot;user#quot;"])
+
+    User -->|"1. Checks out<br/>[HTTPS]"| Shop_Web
+    Shop_Web -.->|"2. Reads"| Shop_DB
+
+    classDef tag_Person fill:#08427b,color:#ffffff
+    class User tag_Person
+    classDef tag_Database fill:#ffffff
+    class Shop_DB tag_Database
+    linkStyle 1 stroke:#ff0000,color:#ff0000
+`,
+		"Deployment": `---
+title: "[Deployment] Production"
+---
+flowchart TB
+    subgraph Production_Server["<b>Server</b><br/>[Deployment Node: Linux]"]
+        Production_Server_Web["<b>Web</b><br/>[Container: Go]"]
+        Production_Server_DB[("<b>DB</b><br/>[Container: PostgreSQL]")]
+    end
+
+    Production_Server_Web -.->|"Reads"| Production_Server_DB
+
+    classDef tag_Database fill:#ffffff
+    class Production_Server_DB tag_Database
+    linkStyle 0 stroke:#ff0000,color:#ff0000
+`,
+	}
+	g := query.New(testDesign())
+	for key, want := range cases {
+		var buf bytes.Buffer
+		if err := WriteFlowchart(&buf, g, g.View(key)); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", key, got, want)
+		}
+	}
+}
+
+func TestWriteViewFallback(t *testing.T) {
+	g := query.New(testDesign())
+	v := *g.View("Containers")
+	v.Kind = query.ViewCode
+	var buf bytes.Buffer
+	if err := WriteView(&buf, g, &v); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, "flowchart LR\n") {
+		t.Errorf("got:\n%s\nwant a flowchart", got)
+	}
+}
+
+func TestAlias(t *testing.T) {
+	cases := map[string]string{
+		"Shop/Web":                 "Shop_Web",
+		"Live/Customer's computer": "Live_Customer_s_computer",
+		"3rd Party":                "e_3rd_Party",
+		"end":                      "end_",
+		"---":                      "element",
+	}
+	for s, want := range cases {
+		if got := alias(s); got != want {
+			t.Errorf("alias(%q): got %q, want %q", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,590 @@
+/*
+Package plantuml renders the views of a design as C4-PlantUML diagrams.
+
+Each view is rendered as a standalone diagram that includes the C4-PlantUML
+library bundled with PlantUML (e.g. "!include <C4/C4_Container>"). People,
+software systems, containers and components are rendered with the
+corresponding C4 macros, external people and software systems use the "_Ext"
+variants and elements whose style shape is a cylinder or a pipe use the "Db"
+and "Queue" variants. Custom tags are rendered as stereotypes and the element
+and relationship styles of the design are mapped to C4 tag styles.
+
+	g := query.New(design)
+	for _, v := range g.AllViews() {
+	    var buf bytes.Buffer
+	    if err := plantuml.WriteView(&buf, g, v); err != nil {
+	        return err
+	    }
+	    os.WriteFile(v.Key+".puml", buf.Bytes(), 0644)
+	}
+*/
+package plantuml
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+// writer writes the C4-PlantUML representation of a view.
+type writer struct {
+	b      strings.Builder
+	indent int
+	g      *query.Graph
+	v      *query.View
+	styles *mdl.Styles
+	// aliases maps element IDs to PlantUML aliases.
+	aliases map[string]string
+	// used lists the aliases that are already taken.
+	used map[string]bool
+}
+
+// WriteView writes the C4-PlantUML source of the view v of the design indexed
+// by g to w.
+func WriteView(w io.Writer, g *query.Graph, v *query.View) error {
+	pw := &writer{
+		g:       g,
+		v:       v,
+		aliases: make(map[string]string),
+		used:    make(map[string]bool),
+	}
+	if g.Design != nil && g.Design.Views != nil {
+		pw.styles = g.Design.Views.Styles
+	}
+	for _, e := range v.Elements {
+		pw.aliases[e.ID] = pw.unique(alias(e.Path))
+	}
+
+	pw.line("@startuml %s", alias(v.Key))
+	pw.line("!include <C4/%s>", library(v.Kind))
+	pw.sep()
+	pw.writeLayout()
+	pw.line("title %s", escape(title(v)))
+	if v.Description != "" {
+		pw.line("caption %s", escape(v.Description))
+	}
+	pw.sep()
+	pw.writeTagStyles()
+	pw.sep()
+	pw.writeElements()
+	pw.sep()
+	pw.writeRelationships()
+	pw.line("@enduml")
+
+	_, err := io.WriteString(w, pw.b.String())
+	return err
+}
+
+// library returns the name of the C4-PlantUML library used to render views of
+// the given kind.
+func library(k query.ViewKind) string {
+	switch k {
+	case query.ViewLandscape, query.ViewContext:
+		return "C4_Context"
+	case query.ViewContainer:
+		return "C4_Container"
+	case query.ViewDynamic:
+		return "C4_Dynamic"
+	case query.ViewDeployment:
+		return "C4_Deployment"
+	default:
+		return "C4_Component"
+	}
+}
+
+// title returns the title of the view, defaults to the view kind followed by
+// the name of the view scope and environment.
+func title(v *query.View) string {
+	if v.Title != "" {
+		return v.Title
+	}
+	parts := []string{"[" + v.Kind.String() + "]"}
+	if v.Scope != nil {
+		parts = append(parts, v.Scope.Name)
+	}
+	if v.Environment != "" {
+		parts = append(parts, v.Environment)
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeLayout writes the layout directive corresponding to the view
+// automatic layout rank direction.
+func (pw *writer) writeLayout() {
+	switch pw.rankDirection() {
+	case mdl.RankLeftRight, mdl.RankRightLeft:
+		pw.line("LAYOUT_LEFT_RIGHT()")
+	default:
+		pw.line("LAYOUT_TOP_DOWN()")
+	}
+}
+
+// rankDirection returns the rank direction of the view automatic layout,
+// RankTopBottom if the view does not use automatic layout.
+func (pw *writer) rankDirection() mdl.RankDirectionKind {
+	if vp := pw.v.Props; vp != nil && vp.AutoLayout != nil && vp.AutoLayout.RankDirection != 0 {
+		return vp.AutoLayout.RankDirection
+	}
+	return mdl.RankTopBottom
+}
+
+// writeTagStyles writes the AddElementTag and AddRelTag declarations for the
+// custom tags used by the elements and relationships of the view and the
+// UpdateElementStyle and UpdateRelStyle declarations for the styles of the
+// default tags.
+func (pw *writer) writeTagStyles() {
+	if pw.styles != nil {
+		for _, s := range pw.styles.Elements {
+			kinds, ok := defaultElementStyles[s.Tag]
+			if !ok {
+				continue
+			}
+			args := elementStyleArgs(s)
+			if len(args) == 0 {
+				continue
+			}
+			for _, k := range kinds {
+				pw.line("UpdateElementStyle(%s)", strings.Join(append([]string{quote(k)}, args...), ", "))
+			}
+		}
+		for _, s := range pw.styles.Relationships {
+			if s.Tag != "Relationship" || s.Color == "" {
+				continue
+			}
+			pw.line("UpdateRelStyle($textColor=%s, $lineColor=%s)", quote(s.Color), quote(s.Color))
+		}
+	}
+
+	var elemTags, relTags []string
+	seen := make(map[string]bool)
+	for _, e := range pw.v.Elements {
+		for _, t := range pw.tags(e) {
+			if !seen[t] {
+				seen[t] = true
+				elemTags = append(elemTags, t)
+			}
+		}
+	}
+	seen = make(map[string]bool)
+	for _, r := range pw.v.Relationships {
+		for _, t := range relationshipTags(r.Relationship) {
+			if !seen[t] {
+				seen[t] = true
+				relTags = append(relTags, t)
+			}
+		}
+	}
+	sort.Strings(elemTags)
+	sort.Strings(relTags)
+	for _, t := range elemTags {
+		args := []string{quote(t)}
+		if s := pw.elementStyle(t); s != nil {
+			args = append(args, elementStyleArgs(s)...)
+			switch s.Shape {
+			case mdl.ShapeRoundedBox:
+				args = append(args, "$shape=RoundedBoxShape()")
+			case mdl.ShapeHexagon:
+				args = append(args, "$shape=EightSidedShape()")
+			}
+		}
+		pw.line("AddElementTag(%s)", strings.Join(args, ", "))
+	}
+	for _, t := range relTags {
+		args := []string{quote(t)}
+		if s := pw.relationshipStyle(t); s != nil {
+			if s.Color != "" {
+				args = append(args, "$textColor="+quote(s.Color), "$lineColor="+quote(s.Color))
+			}
+			if s.Dashed != nil && *s.Dashed {
+				args = append(args, "$lineStyle=DashedLine()")
+			}
+			if s.Thickness != nil {
+				args = append(args, fmt.Sprintf("$lineThickness=%d", *s.Thickness))
+			}
+		}
+		pw.line("AddRelTag(%s)", strings.Join(args, ", "))
+	}
+}
+
+// defaultElementStyles maps the default element tags to the C4-PlantUML
+// element kinds they apply to.
+var defaultElementStyles = map[string][]string{
+	"Person":          {"person", "external_person"},
+	"Software System": {"system", "external_system"},
+	"Container":       {"container", "external_container"},
+	"Component":       {"component", "external_component"},
+	"Deployment Node": {"node"},
+}
+
+// elementStyleArgs returns the C4-PlantUML style arguments corresponding to
+// the given element style.
+func elementStyleArgs(s *mdl.ElementStyle) []string {
+	var args []string
+	if s.Background != "" {
+		args = append(args, "$bgColor="+quote(s.Background))
+	}
+	if s.Color != "" {
+		args = append(args, "$fontColor="+quote(s.Color))
+	}
+	if s.Stroke != "" {
+		args = append(args, "$borderColor="+quote(s.Stroke))
+	}
+	return args
+}
+
+// writeElements writes the elements of the view. Elements are nested in the
+// boundary of their software system or container when it is the scope of the
+// view, in the enterprise boundary when visible and in their parent
+// deployment node in deployment views.
+func (pw *writer) writeElements() {
+	v := pw.v
+	if v.Kind == query.ViewDeployment {
+		for _, e := range v.Elements {
+			if v.Enclosing(e) == nil {
+				pw.writeDeploymentElement(e)
+			}
+		}
+		return
+	}
+
+	var boundary, others []*query.Element
+	for _, e := range v.Elements {
+		if pw.inBoundary(e) {
+			boundary = append(boundary, e)
+		} else {
+			others = append(others, e)
+		}
+	}
+	if len(boundary) > 0 {
+		switch {
+		case v.Kind == query.ViewLandscape || v.Kind == query.ViewContext:
+			pw.open("Enterprise_Boundary(%s, %s)", pw.unique("enterprise"), quote(pw.g.Design.Model.Enterprise.Name))
+		case v.Scope.Kind == query.KindSoftwareSystem:
+			pw.open("System_Boundary(%s, %s)", pw.unique(alias(v.Scope.Path)+"_boundary"), quote(v.Scope.Name))
+		default:
+			pw.open("Container_Boundary(%s, %s)", pw.unique(alias(v.Scope.Path)+"_boundary"), quote(v.Scope.Name))
+		}
+		for _, e := range boundary {
+			pw.writeElement(e)
+		}
+		pw.close()
+	}
+	for _, e := range others {
+		pw.writeElement(e)
+	}
+}
+
+// inBoundary returns true if e must be rendered inside the boundary of the
+// view.
+func (pw *writer) inBoundary(e *query.Element) bool {
+	v := pw.v
+	switch v.Kind {
+	case query.ViewLandscape, query.ViewContext:
+		if !pw.enterpriseBoundaryVisible() {
+			return false
+		}
+		return location(e) != mdl.LocationExternal
+	case query.ViewContainer, query.ViewComponent, query.ViewDynamic:
+		return v.Scope != nil && e.Parent == v.Scope
+	}
+	return false
+}
+
+// enterpriseBoundaryVisible returns true if the model defines an enterprise
+// and the view does not hide its boundary.
+func (pw *writer) enterpriseBoundaryVisible() bool {
+	d := pw.g.Design
+	if d.Model == nil || d.Model.Enterprise == nil || d.Views == nil {
+		return false
+	}
+	for _, lv := range d.Views.LandscapeViews {
+		if lv.Key == pw.v.Key {
+			return lv.EnterpriseBoundaryVisible == nil || *lv.EnterpriseBoundaryVisible
+		}
+	}
+	for _, cv := range d.Views.ContextViews {
+		if cv.Key == pw.v.Key {
+			return cv.EnterpriseBoundaryVisible == nil || *cv.EnterpriseBoundaryVisible
+		}
+	}
+	return true
+}
+
+// writeElement writes the C4 macro for a person, software system, container,
+// component or code element.
+func (pw *writer) writeElement(e *query.Element) {
+	macro := macroName(e.Kind, pw.shape(e), location(e) == mdl.LocationExternal)
+	args := []string{pw.aliases[e.ID], quote(e.Name)}
+	if e.Kind == query.KindPerson || e.Kind == query.KindSoftwareSystem {
+		args = append(args, quote(e.Description))
+	} else {
+		args = append(args, quote(e.Technology), quote(e.Description))
+	}
+	pw.line("%s(%s)", macro, strings.Join(append(args, pw.tagsArg(e)...), ", "))
+}
+
+// writeDeploymentElement writes the C4 macro for a deployment element and the
+// elements it contains.
+func (pw *writer) writeDeploymentElement(e *query.Element) {
+	switch e.Kind {
+	case query.KindDeploymentNode:
+		args := []string{pw.aliases[e.ID], quote(e.Name), quote(e.Technology), quote(e.Description)}
+		args = append(args, pw.tagsArg(e)...)
+		var children []*query.Element
+		for _, c := range pw.v.Elements {
+			if pw.v.Enclosing(c) == e {
+				children = append(children, c)
+			}
+		}
+		if len(children) == 0 {
+			pw.line("Deployment_Node(%s)", strings.Join(args, ", "))
+			return
+		}
+		pw.open("Deployment_Node(%s)", strings.Join(args, ", "))
+		for _, c := range children {
+			pw.writeDeploymentElement(c)
+		}
+		pw.close()
+	case query.KindInfrastructureNode:
+		args := []string{pw.aliases[e.ID], quote(e.Name), quote(e.Technology), quote(e.Description)}
+		pw.line("Node(%s)", strings.Join(append(args, pw.tagsArg(e)...), ", "))
+	case query.KindContainerInstance, query.KindSoftwareSystemInstance:
+		inst := e.InstanceOf
+		if inst == nil {
+			return
+		}
+		macro := macroName(inst.Kind, pw.shape(e), location(inst) == mdl.LocationExternal)
+		args := []string{pw.aliases[e.ID], quote(inst.Name)}
+		if inst.Kind == query.KindContainer {
+			args = append(args, quote(inst.Technology))
+		}
+		args = append(args, quote(inst.Description))
+		pw.line("%s(%s)", macro, strings.Join(append(args, pw.tagsArg(e)...), ", "))
+	default:
+		pw.writeElement(e)
+	}
+}
+
+// macroName returns the name of the C4 macro used to render an element of the
+// given kind and shape.
+func macroName(kind query.ElementKind, shape mdl.ShapeKind, external bool) string {
+	var name string
+	switch kind {
+	case query.KindPerson:
+		name = "Person"
+	case query.KindSoftwareSystem:
+		name = "System"
+	case query.KindContainer:
+		name = "Container"
+	default:
+		name = "Component"
+	}
+	if kind != query.KindPerson {
+		switch shape {
+		case mdl.ShapeCylinder:
+			name += "Db"
+		case mdl.ShapePipe:
+			name += "Queue"
+		}
+	}
+	if external {
+		name += "_Ext"
+	}
+	return name
+}
+
+// writeRelationships writes the relationships of the view. Relationships of
+// dynamic views are numbered with their order.
+func (pw *writer) writeRelationships() {
+	var suffix string
+	switch pw.rankDirection() {
+	case mdl.RankBottomTop:
+		suffix = "_U"
+	case mdl.RankRightLeft:
+		suffix = "_L"
+	}
+	for i, r := range pw.v.Relationships {
+		src, dest := pw.aliases[r.Source.ID], pw.aliases[r.Destination.ID]
+		args := []string{src, dest, quote(r.Description)}
+		if r.Relationship.Technology != "" {
+			args = append(args, quote(r.Relationship.Technology))
+		}
+		if tags := relationshipTags(r.Relationship); len(tags) > 0 {
+			args = append(args, "$tags="+quote(strings.Join(tags, "+")))
+		}
+		if pw.v.Kind == query.ViewDynamic {
+			order := r.Order
+			if order == "" {
+				order = fmt.Sprint(i + 1)
+			}
+			pw.line("RelIndex%s(%s)", suffix, strings.Join(append([]string{quote(order)}, args...), ", "))
+			continue
+		}
+		pw.line("Rel%s(%s)", suffix, strings.Join(args, ", "))
+	}
+}
+
+// tags returns the custom tags of e, for container and software system
+// instances the custom tags of the instantiated element come first.
+func (pw *writer) tags(e *query.Element) []string {
+	tags := e.CustomTags()
+	if e.InstanceOf != nil {
+		tags = append(e.InstanceOf.CustomTags(), tags...)
+	}
+	return tags
+}
+
+// tagsArg returns the $tags argument for the custom tags of e if any.
+func (pw *writer) tagsArg(e *query.Element) []string {
+	tags := pw.tags(e)
+	if len(tags) == 0 {
+		return nil
+	}
+	return []string{"$tags=" + quote(strings.Join(tags, "+"))}
+}
+
+// relationshipTags returns the custom tags of r.
+func relationshipTags(r *mdl.Relationship) []string {
+	var res []string
+	for _, t := range strings.Split(r.Tags, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" && t != "Relationship" && t != "Synchronous" {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// shape returns the shape of e defined by the styles of its tags, the last
+// style that defines a shape wins.
+func (pw *writer) shape(e *query.Element) mdl.ShapeKind {
+	tags := strings.Split(e.Tags, ",")
+	if e.InstanceOf != nil {
+		tags = append(strings.Split(e.InstanceOf.Tags, ","), tags...)
+	}
+	var shape mdl.ShapeKind
+	for _, t := range tags {
+		if s := pw.elementStyle(strings.TrimSpace(t)); s != nil && s.Shape != mdl.ShapeUndefined {
+			shape = s.Shape
+		}
+	}
+	return shape
+}
+
+// elementStyle returns the element style for the given tag, nil if there is
+// none.
+func (pw *writer) elementStyle(tag string) *mdl.ElementStyle {
+	if pw.styles == nil {
+		return nil
+	}
+	for _, s := range pw.styles.Elements {
+		if s.Tag == tag {
+			return s
+		}
+	}
+	return nil
+}
+
+// relationshipStyle returns the relationship style for the given tag, nil if
+// there is none.
+func (pw *writer) relationshipStyle(tag string) *mdl.RelationshipStyle {
+	if pw.styles == nil {
+		return nil
+	}
+	for _, s := range pw.styles.Relationships {
+		if s.Tag == tag {
+			return s
+		}
+	}
+	return nil
+}
+
+// location returns the location of e, the location of the software system
+// that contains e for containers and components.
+func location(e *query.Element) mdl.LocationKind {
+	for el := e; el != nil; el = el.Parent {
+		switch v := el.Value.(type) {
+		case *mdl.Person:
+			return v.Location
+		case *mdl.SoftwareSystem:
+			return v.Location
+		}
+	}
+	return mdl.LocationUndefined
+}
+
+// unique returns a that is not used yet, it appends a number to a if
+// needed.
+func (pw *writer) unique(a string) string {
+	res := a
+	for i := 2; pw.used[res]; i++ {
+		res = fmt.Sprintf("%s_%d", a, i)
+	}
+	pw.used[res] = true
+	return res
+}
+
+// alias returns a PlantUML alias derived from s.
+func alias(s string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range s {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	res := strings.TrimSuffix(b.String(), "_")
+	if res == "" {
+		return "element"
+	}
+	if res[0] >= '0' && res[0] <= '9' {
+		res = "e_" + res
+	}
+	return res
+}
+
+// quote returns s as a PlantUML string literal. Double quotes cannot be
+// escaped in PlantUML macro arguments and are replaced with single quotes.
+func quote(s string) string {
+	return `"` + escape(strings.ReplaceAll(s, `"`, "'")) + `"`
+}
+
+// escape replaces the new lines in s with the PlantUML new line sequence.
+func escape(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", `\n`)
+}
+
+// line writes a line of PlantUML indented at the current level.
+func (pw *writer) line(format string, args ...any) {
+	pw.b.WriteString(strings.Repeat("    ", pw.indent))
+	fmt.Fprintf(&pw.b, format, args...)
+	pw.b.WriteByte('\n')
+}
+
+// open writes a line that opens a block.
+func (pw *writer) open(format string, args ...any) {
+	pw.line(format+" {", args...)
+	pw.indent++
+}
+
+// close closes the current block.
+func (pw *writer) close() {
+	pw.indent--
+	pw.line("}")
+}
+
+// sep writes an empty line unless the previous line is empty.
+func (pw *writer) sep() {
+	if s := pw.b.String(); strings.HasSuffix(s, "\n\n") {
+		return
+	}
+	pw.b.WriteByte('\n')
+}
@@ -0,0 +1,201 @@
+package plantuml
+
+import (
+	"bytes"
+	"testing"
+
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			Enterprise: &mdl.Enterprise{Name: "Acme"},
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Description: `The "user"`, Tags: "Element,Person", Location: mdl.LocationExternal,
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", Tags: "Relationship,Synchronous"},
+					{ID: "r4", SourceID: "user", DestinationID: "shop", Description: "Buys from", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Description: "Sells things", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container,Frontend",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship,Asynchronous"},
+						},
+					},
+					{ID: "db", Name: "DB", Technology: "PostgreSQL", Tags: "Element,Container,Database"},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID: "node", Name: "Server", Technology: "Linux", Environment: "Production", Tags: "Element,Deployment Node",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "webInst", ContainerID: "web", Tags: "Element,Container Instance", Relationships: []*mdl.Relationship{
+						{ID: "r3", SourceID: "webInst", DestinationID: "dbInst", Description: "Reads", Tags: "Relationship,Asynchronous", LinkedRelationshipID: "r2"},
+					}},
+					{ID: "dbInst", ContainerID: "db", Tags: "Element,Container Instance"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContextViews: []*mdl.ContextView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Context",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "shop"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r4"}},
+				},
+			}},
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					Title:             "Shop containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r2"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankLeftRight},
+				},
+			}},
+			DynamicViews: []*mdl.DynamicView{{
+				ElementID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Checkout",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r2", Order: "2"}, {ID: "r1", Order: "1", Description: "Checks out"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankBottomTop},
+				},
+			}},
+			DeploymentViews: []*mdl.DeploymentView{{
+				Environment: "Production",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Deployment",
+					ElementViews:      []*mdl.ElementView{{ID: "node"}, {ID: "webInst"}, {ID: "dbInst"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r3"}},
+				},
+			}},
+			Styles: &mdl.Styles{
+				Elements: []*mdl.ElementStyle{
+					{Tag: "Person", Background: "#08427b", Color: "#ffffff"},
+					{Tag: "Database", Shape: mdl.ShapeCylinder, Background: "#ffffff"},
+				},
+				Relationships: []*mdl.RelationshipStyle{{Tag: "Asynchronous", Dashed: &dashed}},
+			},
+		},
+	}
+}
+
+var dashed = true
+
+func TestWriteView(t *testing.T) {
+	cases := map[string]string{
+		"Context": `@startuml Context
+!include <C4/C4_Context>
+
+LAYOUT_TOP_DOWN()
+title [System Context] Shop
+
+UpdateElementStyle("person", $bgColor="#08427b", $fontColor="#ffffff")
+UpdateElementStyle("external_person", $bgColor="#08427b", $fontColor="#ffffff")
+
+Enterprise_Boundary(enterprise, "Acme") {
+    System(Shop, "Shop", "Sells things")
+}
+Person_Ext(User, "User", "The 'user'")
+
+Rel(User, Shop, "Buys from")
+@enduml
+`,
+		"Containers": `@startuml Containers
+!include <C4/C4_Container>
+
+LAYOUT_LEFT_RIGHT()
+title Shop containers
+
+UpdateElementStyle("person", $bgColor="#08427b", $fontColor="#ffffff")
+UpdateElementStyle("external_person", $bgColor="#08427b", $fontColor="#ffffff")
+AddElementTag("Database", $bgColor="#ffffff")
+AddElementTag("Frontend")
+AddRelTag("Asynchronous", $lineStyle=DashedLine())
+
+System_Boundary(Shop_boundary, "Shop") {
+    Container(Shop_Web, "Web", "Go", "", $tags="Frontend")
+    ContainerDb(Shop_DB, "DB", "PostgreSQL", "", $tags="Database")
+}
+Person_Ext(User, "User", "The 'user'")
+
+Rel(User, Shop_Web, "Browses", "HTTPS")
+Rel(Shop_Web, Shop_DB, "Reads", $tags="Asynchronous")
+@enduml
+`,
+		"Checkout": `@startuml Checkout
+!include <C4/C4_Dynamic>
+
+LAYOUT_TOP_DOWN()
+title [Dynamic] Shop
+
+UpdateElementStyle("person", $bgColor="#08427b", $fontColor="#ffffff")
+UpdateElementStyle("external_person", $bgColor="#08427b", $fontColor="#ffffff")
+AddElementTag("Database", $bgColor="#ffffff")
+AddElementTag("Frontend")
+AddRelTag("Asynchronous", $lineStyle=DashedLine())
+
+System_Boundary(Shop_boundary, "Shop") {
+    Container(Shop_Web, "Web", "Go", "", $tags="Frontend")
+    ContainerDb(Shop_DB, "DB", "PostgreSQL", "", $tags="Database")
+}
+Person_Ext(User, "User", "The 'user'")
+
+RelIndex_U("1", User, Shop_Web, "Checks out", "HTTPS")
+RelIndex_U("2", Shop_Web, Shop_DB, "Reads", $tags="Asynchronous")
+@enduml
+`,
+		"Deployment": `@startuml Deployment
+!include <C4/C4_Deployment>
+
+LAYOUT_TOP_DOWN()
+title [Deployment] Production
+
+UpdateElementStyle("person", $bgColor="#08427b", $fontColor="#ffffff")
+UpdateElementStyle("external_person", $bgColor="#08427b", $fontColor="#ffffff")
+AddElementTag("Database", $bgColor="#ffffff")
+AddElementTag("Frontend")
+AddRelTag("Asynchronous", $lineStyle=DashedLine())
+
+Deployment_Node(Production_Server, "Server", "Linux", "") {
+    Container(Production_Server_Web, "Web", "Go", "", $tags="Frontend")
+    ContainerDb(Production_Server_DB, "DB", "PostgreSQL", "", $tags="Database")
+}
+
+Rel(Production_Server_Web, Production_Server_DB, "Reads", $tags="Asynchronous")
+@enduml
+`,
+	}
+	g := query.New(testDesign())
+	for key, want := range cases {
+		var buf bytes.Buffer
+		if err := WriteView(&buf, g, g.View(key)); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", key, got, want)
+		}
+	}
+}
+
+func TestAlias(t *testing.T) {
+	cases := map[string]string{
+		"Shop/Web":                 "Shop_Web",
+		"Live/Customer's computer": "Live_Customer_s_computer",
+		"3rd Party":                "e_3rd_Party",
+		"---":                      "element",
+	}
+	for s, want := range cases {
+		if got := alias(s); got != want {
+			t.Errorf("alias(%q): got %q, want %q", s, got, want)
+		}
+	}
+}
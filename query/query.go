@@ -0,0 +1,395 @@
+/*
+Package query indexes the elements and relationships of a design so that
+they can be looked up and traversed without walking the model by hand.
+
+A Graph is built from a mdl.Design, typically loaded from the JSON produced by
+"mdl gen":
+
+	var d mdl.Design
+	if err := json.Unmarshal(b, &d); err != nil {
+	    return err
+	}
+	g := query.New(&d)
+	api := g.ElementByPath("System/API")
+	for _, e := range g.Dependents(api.ID) {
+	    fmt.Println(e.Path)
+	}
+
+Model elements (people, software systems, containers, components and code
+elements) are identified by their path, e.g. "System/Container/Component".
+Deployment elements are identified by their path prefixed with the
+deployment environment, e.g. "Production/Node/Container". Model elements take
+precedence when a deployment element has the same path.
+*/
+package query
+
+import (
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// Graph indexes the elements and relationships of a design.
+	Graph struct {
+		// Design is the indexed design.
+		Design *mdl.Design
+
+		elements []*Element
+		byID     map[string]*Element
+		byPath   map[string]*Element
+		outgoing map[string][]*mdl.Relationship
+		incoming map[string][]*mdl.Relationship
+		rels     map[string]*mdl.Relationship
+	}
+
+	// Element provides a uniform view over the different kinds of design
+	// elements.
+	Element struct {
+		// ID of element.
+		ID string
+		// Name of element. The name of container and software system
+		// instances is the name of the instantiated element.
+		Name string
+		// Kind of element.
+		Kind ElementKind
+		// Path of element, see package documentation.
+		Path string
+		// Description of element if any.
+		Description string
+		// Technology of element if any.
+		Technology string
+		// Tags attached to element as comma separated list if any.
+		Tags string
+		// Properties of element if any.
+		Properties map[string]string
+		// Parent is the parent element, nil for people, software systems
+		// and top level deployment nodes.
+		Parent *Element
+		// Children lists the child elements.
+		Children []*Element
+		// InstanceOf is the element instantiated by a container or software
+		// system instance, nil for other elements.
+		InstanceOf *Element
+		// Value is the underlying mdl value, e.g. *mdl.Container.
+		Value any
+	}
+
+	// ElementKind is the enum for possible element kinds.
+	ElementKind int
+)
+
+const (
+	// KindUndefined means the kind of the element is unknown.
+	KindUndefined ElementKind = iota
+	// KindPerson is the kind of people.
+	KindPerson
+	// KindSoftwareSystem is the kind of software systems.
+	KindSoftwareSystem
+	// KindContainer is the kind of containers.
+	KindContainer
+	// KindComponent is the kind of components.
+	KindComponent
+	// KindCodeElement is the kind of code elements.
+	KindCodeElement
+	// KindDeploymentNode is the kind of deployment nodes.
+	KindDeploymentNode
+	// KindInfrastructureNode is the kind of infrastructure nodes.
+	KindInfrastructureNode
+	// KindContainerInstance is the kind of container instances.
+	KindContainerInstance
+	// KindSoftwareSystemInstance is the kind of software system instances.
+	KindSoftwareSystemInstance
+)
+
+// New indexes the elements and relationships of d.
+func New(d *mdl.Design) *Graph {
+	g := &Graph{
+		Design:   d,
+		byID:     make(map[string]*Element),
+		byPath:   make(map[string]*Element),
+		outgoing: make(map[string][]*mdl.Relationship),
+		incoming: make(map[string][]*mdl.Relationship),
+		rels:     make(map[string]*mdl.Relationship),
+	}
+	if d == nil || d.Model == nil {
+		return g
+	}
+	m := d.Model
+	for _, p := range m.People {
+		g.add(&Element{ID: p.ID, Name: p.Name, Kind: KindPerson, Description: p.Description, Tags: p.Tags, Properties: p.Properties, Value: p}, nil, p.Relationships)
+	}
+	for _, s := range m.Systems {
+		se := g.add(&Element{ID: s.ID, Name: s.Name, Kind: KindSoftwareSystem, Description: s.Description, Tags: s.Tags, Properties: s.Properties, Value: s}, nil, s.Relationships)
+		for _, c := range s.Containers {
+			ce := g.add(&Element{ID: c.ID, Name: c.Name, Kind: KindContainer, Description: c.Description, Technology: c.Technology, Tags: c.Tags, Properties: c.Properties, Value: c}, se, c.Relationships)
+			for _, cmp := range c.Components {
+				cmpe := g.add(&Element{ID: cmp.ID, Name: cmp.Name, Kind: KindComponent, Description: cmp.Description, Technology: cmp.Technology, Tags: cmp.Tags, Properties: cmp.Properties, Value: cmp}, ce, cmp.Relationships)
+				for _, code := range cmp.CodeElements {
+					g.add(&Element{ID: code.ID, Name: code.Name, Kind: KindCodeElement, Description: code.Description, Technology: code.Technology, Tags: code.Tags, Properties: code.Properties, Value: code}, cmpe, code.Relationships)
+				}
+			}
+		}
+	}
+	for _, n := range m.DeploymentNodes {
+		g.addDeploymentNode(n, nil)
+	}
+	for _, e := range g.elements {
+		if e.Kind != KindContainerInstance && e.Kind != KindSoftwareSystemInstance {
+			continue
+		}
+		e.InstanceOf = g.byID[instanceOf(e.Value)]
+		if e.InstanceOf != nil {
+			e.Name = e.InstanceOf.Name
+			e.Path = e.Parent.Path + "/" + e.Name
+		}
+	}
+	// Index paths once the paths of instances are known. Model elements
+	// come first so that they win if a deployment element has the same
+	// path.
+	for _, e := range g.elements {
+		if e.Name == "" {
+			continue
+		}
+		if _, ok := g.byPath[e.Path]; !ok {
+			g.byPath[e.Path] = e
+		}
+	}
+	return g
+}
+
+// Elements returns all the elements of the design. People come first
+// followed by software systems and their descendants and finally deployment
+// elements.
+func (g *Graph) Elements() []*Element {
+	return g.elements
+}
+
+// Element returns the element with the given ID, nil if there is none.
+func (g *Graph) Element(id string) *Element {
+	return g.byID[id]
+}
+
+// ElementByPath returns the element with the given path, nil if there is
+// none.
+func (g *Graph) ElementByPath(path string) *Element {
+	return g.byPath[path]
+}
+
+// ElementsByTag returns the elements that have the given tag.
+func (g *Graph) ElementsByTag(tag string) []*Element {
+	var res []*Element
+	for _, e := range g.elements {
+		if e.HasTag(tag) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// Relationship returns the relationship with the given ID, nil if there is
+// none.
+func (g *Graph) Relationship(id string) *mdl.Relationship {
+	return g.rels[id]
+}
+
+// Outgoing returns the relationships whose source is the element with the
+// given ID.
+func (g *Graph) Outgoing(id string) []*mdl.Relationship {
+	return g.outgoing[id]
+}
+
+// Incoming returns the relationships whose destination is the element with
+// the given ID.
+func (g *Graph) Incoming(id string) []*mdl.Relationship {
+	return g.incoming[id]
+}
+
+// Views returns the views that include the element with the given ID.
+func (g *Graph) Views(id string) []*mdl.ViewProps {
+	if g.Design == nil || g.Design.Views == nil {
+		return nil
+	}
+	v := g.Design.Views
+	var props []*mdl.ViewProps
+	for _, lv := range v.LandscapeViews {
+		props = append(props, lv.ViewProps)
+	}
+	for _, cv := range v.ContextViews {
+		props = append(props, cv.ViewProps)
+	}
+	for _, cv := range v.ContainerViews {
+		props = append(props, cv.ViewProps)
+	}
+	for _, cv := range v.ComponentViews {
+		props = append(props, cv.ViewProps)
+	}
+	for _, cv := range v.CodeViews {
+		props = append(props, cv.ViewProps)
+	}
+	for _, dv := range v.DynamicViews {
+		props = append(props, dv.ViewProps)
+	}
+	for _, dv := range v.DeploymentViews {
+		props = append(props, dv.ViewProps)
+	}
+	var res []*mdl.ViewProps
+	for _, vp := range props {
+		for _, ev := range vp.ElementViews {
+			if ev.ID == id {
+				res = append(res, vp)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Property returns the value of the property with the given name defined on
+// the element or on its closest ancestor that defines it. It returns the
+// empty string if there is none.
+func (e *Element) Property(name string) string {
+	for el := e; el != nil; el = el.Parent {
+		if v, ok := el.Properties[name]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// HasTag returns true if the element has the given tag.
+func (e *Element) HasTag(tag string) bool {
+	for _, t := range strings.Split(e.Tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomTags returns the tags of the element that are not added by default
+// to all the elements of its kind.
+func (e *Element) CustomTags() []string {
+	var res []string
+	for _, t := range strings.Split(e.Tags, ",") {
+		t = strings.TrimSpace(t)
+		switch {
+		case t == "", t == "Element", t == e.Kind.String():
+		case t == "Code" && e.Kind == KindCodeElement:
+		default:
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Ancestors returns the parent of the element, the parent of the parent and
+// so on.
+func (e *Element) Ancestors() []*Element {
+	var res []*Element
+	for p := e.Parent; p != nil; p = p.Parent {
+		res = append(res, p)
+	}
+	return res
+}
+
+// Descendants returns the children of the element, their children and so on
+// in depth first order.
+func (e *Element) Descendants() []*Element {
+	var res []*Element
+	for _, c := range e.Children {
+		res = append(res, c)
+		res = append(res, c.Descendants()...)
+	}
+	return res
+}
+
+// Contains returns true if other is e or one of its descendants.
+func (e *Element) Contains(other *Element) bool {
+	for o := other; o != nil; o = o.Parent {
+		if o == e {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the name of the element kind.
+func (k ElementKind) String() string {
+	switch k {
+	case KindPerson:
+		return "Person"
+	case KindSoftwareSystem:
+		return "Software System"
+	case KindContainer:
+		return "Container"
+	case KindComponent:
+		return "Component"
+	case KindCodeElement:
+		return "Code Element"
+	case KindDeploymentNode:
+		return "Deployment Node"
+	case KindInfrastructureNode:
+		return "Infrastructure Node"
+	case KindContainerInstance:
+		return "Container Instance"
+	case KindSoftwareSystemInstance:
+		return "Software System Instance"
+	default:
+		return "Undefined"
+	}
+}
+
+// add indexes e and its relationships. It returns e.
+func (g *Graph) add(e *Element, parent *Element, rels []*mdl.Relationship) *Element {
+	e.Parent = parent
+	if parent != nil {
+		parent.Children = append(parent.Children, e)
+		e.Path = parent.Path + "/" + e.Name
+	} else {
+		e.Path = e.Name
+	}
+	g.elements = append(g.elements, e)
+	g.byID[e.ID] = e
+	for _, r := range rels {
+		g.rels[r.ID] = r
+		g.outgoing[r.SourceID] = append(g.outgoing[r.SourceID], r)
+		g.incoming[r.DestinationID] = append(g.incoming[r.DestinationID], r)
+	}
+	return e
+}
+
+// addDeploymentNode indexes n and its children.
+func (g *Graph) addDeploymentNode(n *mdl.DeploymentNode, parent *Element) {
+	// Top level deployment nodes paths are prefixed with the environment.
+	name := n.Name
+	if parent == nil {
+		name = n.Environment + "/" + n.Name
+	}
+	ne := g.add(&Element{ID: n.ID, Name: name, Kind: KindDeploymentNode, Description: n.Description, Technology: n.Technology, Tags: n.Tags, Properties: n.Properties, Value: n}, parent, n.Relationships)
+	ne.Name = n.Name
+	for _, c := range n.Children {
+		g.addDeploymentNode(c, ne)
+	}
+	for _, i := range n.InfrastructureNodes {
+		g.add(&Element{ID: i.ID, Name: i.Name, Kind: KindInfrastructureNode, Description: i.Description, Technology: i.Technology, Tags: i.Tags, Properties: i.Properties, Value: i}, ne, i.Relationships)
+	}
+	for _, ci := range n.ContainerInstances {
+		g.add(&Element{ID: ci.ID, Kind: KindContainerInstance, Tags: ci.Tags, Properties: ci.Properties, Value: ci}, ne, ci.Relationships)
+	}
+	for _, si := range n.SoftwareSystemInstances {
+		g.add(&Element{ID: si.ID, Kind: KindSoftwareSystemInstance, Tags: si.Tags, Properties: si.Properties, Value: si}, ne, si.Relationships)
+	}
+}
+
+// instanceOf returns the ID of the element instantiated by v if v is a
+// container or software system instance, the empty string otherwise.
+func instanceOf(v any) string {
+	switch i := v.(type) {
+	case *mdl.ContainerInstance:
+		return i.ContainerID
+	case *mdl.SoftwareSystemInstance:
+		return i.SoftwareSystemID
+	}
+	return ""
+}
@@ -0,0 +1,239 @@
+package query
+
+import (
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+// testDesign returns a design where:
+//
+//	User -> Shop/Web -> Shop/API/Orders -> Shop/DB
+//	Shop/API/Orders -> Payments
+//	Admin -> Shop/API
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Model: &mdl.Model{
+			People: []*mdl.Person{
+				{ID: "user", Name: "User", Relationships: []*mdl.Relationship{{ID: "r1", SourceID: "user", DestinationID: "web"}}},
+				{ID: "admin", Name: "Admin", Tags: "Element,Person,Staff", Relationships: []*mdl.Relationship{{ID: "r5", SourceID: "admin", DestinationID: "api"}}},
+			},
+			Systems: []*mdl.SoftwareSystem{
+				{
+					ID:   "shop",
+					Name: "Shop",
+					Containers: []*mdl.Container{
+						{ID: "web", Name: "Web", Tags: "Element,Container,Frontend", Relationships: []*mdl.Relationship{{ID: "r2", SourceID: "web", DestinationID: "orders"}}},
+						{ID: "api", Name: "API", Components: []*mdl.Component{
+							{ID: "orders", Name: "Orders", Relationships: []*mdl.Relationship{
+								{ID: "r3", SourceID: "orders", DestinationID: "db"},
+								{ID: "r4", SourceID: "orders", DestinationID: "payments"},
+							}},
+						}},
+						{ID: "db", Name: "DB"},
+					},
+				},
+				{ID: "payments", Name: "Payments"},
+			},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID:          "node",
+				Name:        "Server",
+				Environment: "Production",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "apiInst", ContainerID: "api", Relationships: []*mdl.Relationship{{ID: "r6", SourceID: "apiInst", DestinationID: "dbInst", LinkedRelationshipID: "r3"}}},
+					{ID: "dbInst", ContainerID: "db"},
+				},
+			}},
+		},
+	}
+}
+
+func paths(es []*Element) []string {
+	res := make([]string, len(es))
+	for i, e := range es {
+		res[i] = e.Path
+	}
+	return res
+}
+
+func ids(rs []*mdl.Relationship) []string {
+	res := make([]string, len(rs))
+	for i, r := range rs {
+		res[i] = r.ID
+	}
+	return res
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	g := New(testDesign())
+
+	orders := g.ElementByPath("Shop/API/Orders")
+	if orders == nil || orders.ID != "orders" || orders.Kind != KindComponent {
+		t.Fatalf("got %+v, want component orders", orders)
+	}
+	if got := g.Element("orders"); got != orders {
+		t.Errorf("got %+v, want %+v", got, orders)
+	}
+	if got := orders.Parent.Path; got != "Shop/API" {
+		t.Errorf("got parent %q, want Shop/API", got)
+	}
+	if got := paths(orders.Ancestors()); !equal(got, []string{"Shop/API", "Shop"}) {
+		t.Errorf("got ancestors %v", got)
+	}
+	if got := paths(g.ElementByPath("Shop").Descendants()); !equal(got, []string{"Shop/Web", "Shop/API", "Shop/API/Orders", "Shop/DB"}) {
+		t.Errorf("got descendants %v", got)
+	}
+	if got := paths(g.ElementsByTag("Frontend")); !equal(got, []string{"Shop/Web"}) {
+		t.Errorf("got tagged %v", got)
+	}
+	inst := g.Element("apiInst")
+	if inst == nil || inst.Path != "Production/Server/API" || inst.InstanceOf != g.Element("api") {
+		t.Errorf("got instance %+v", inst)
+	}
+	if got := g.ElementByPath("Production/Server/API"); got != inst {
+		t.Errorf("got %+v for instance path, want %+v", got, inst)
+	}
+	if node := g.ElementByPath("Production/Server"); node == nil || node.ID != "node" || node.Kind != KindDeploymentNode {
+		t.Errorf("got %+v for deployment node path, want node", node)
+	}
+	if g.ElementByPath("Unknown") != nil {
+		t.Errorf("got element for unknown path")
+	}
+}
+
+func TestRelationships(t *testing.T) {
+	t.Parallel()
+	g := New(testDesign())
+
+	if got := ids(g.Outgoing("orders")); !equal(got, []string{"r3", "r4"}) {
+		t.Errorf("got outgoing %v", got)
+	}
+	if got := ids(g.Incoming("api")); !equal(got, []string{"r5"}) {
+		t.Errorf("got incoming %v", got)
+	}
+	if got := ids(g.ImpliedIncoming("api")); !equal(got, []string{"r5", "r2"}) {
+		t.Errorf("got implied incoming %v", got)
+	}
+	if got := ids(g.ImpliedOutgoing("shop")); !equal(got, []string{"r4"}) {
+		t.Errorf("got implied outgoing %v", got)
+	}
+	if got := g.Relationship("r6"); got == nil || got.LinkedRelationshipID != "r3" {
+		t.Errorf("got relationship %+v", got)
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	t.Parallel()
+	g := New(testDesign())
+
+	if got := paths(g.Dependencies("web")); !equal(got, []string{"Shop/API/Orders", "Shop/DB", "Payments"}) {
+		t.Errorf("got dependencies %v", got)
+	}
+	if got := paths(g.Dependents("db")); !equal(got, []string{"Shop/API/Orders", "Shop/Web", "User"}) {
+		t.Errorf("got dependents %v", got)
+	}
+	if got := paths(g.Dependents("api")); !equal(got, []string{"Admin", "Shop/Web", "User"}) {
+		t.Errorf("got dependents %v", got)
+	}
+	if got := ids(g.ShortestPath("user", "db")); !equal(got, []string{"r1", "r2", "r3"}) {
+		t.Errorf("got path %v", got)
+	}
+	if got := ids(g.ShortestPath("admin", "payments")); !equal(got, []string{"r5", "r4"}) {
+		t.Errorf("got path %v", got)
+	}
+	if got := g.ShortestPath("db", "user"); got != nil {
+		t.Errorf("got path %v, want none", ids(got))
+	}
+}
+
+func TestViews(t *testing.T) {
+	t.Parallel()
+	d := testDesign()
+	d.Views = &mdl.Views{
+		ContainerViews: []*mdl.ContainerView{{
+			SoftwareSystemID: "shop",
+			ViewProps: &mdl.ViewProps{
+				Key:               "Containers",
+				Title:             "Shop",
+				ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "api"}, {ID: "unknown"}},
+				RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r5"}},
+			},
+		}},
+		DynamicViews: []*mdl.DynamicView{{
+			ElementID: "shop",
+			ViewProps: &mdl.ViewProps{
+				Key:          "Order",
+				ElementViews: []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "orders"}},
+				RelationshipViews: []*mdl.RelationshipView{
+					{ID: "r2", Order: "10"},
+					{ID: "r1", Order: "2", Description: "Orders"},
+				},
+			},
+		}},
+		FilteredViews: []*mdl.FilteredView{{Key: "NoFrontend", BaseKey: "Containers", Mode: "Exclude", Tags: []string{"Frontend"}}},
+	}
+	g := New(d)
+
+	views := g.AllViews()
+	if len(views) != 3 {
+		t.Fatalf("got %d views, want 3", len(views))
+	}
+	cv := g.View("Containers")
+	if cv.Kind != ViewContainer || cv.Scope != g.Element("shop") || cv.Title != "Shop" {
+		t.Errorf("got view %+v", cv)
+	}
+	if got := paths(cv.Elements); !equal(got, []string{"User", "Shop/Web", "Shop/API"}) {
+		t.Errorf("got view elements %v", got)
+	}
+	if len(cv.Relationships) != 1 || cv.Relationships[0].Source != g.Element("user") {
+		t.Errorf("got view relationships %+v", cv.Relationships)
+	}
+	dv := g.View("Order")
+	if len(dv.Relationships) != 2 || dv.Relationships[0].Order != "2" || dv.Relationships[0].Description != "Orders" || dv.Relationships[1].Description != "" {
+		t.Errorf("got dynamic view relationships %+v %+v", dv.Relationships[0], dv.Relationships[1])
+	}
+	fv := g.View("NoFrontend")
+	if fv.Kind != ViewContainer || fv.Title != "Shop" {
+		t.Errorf("got filtered view %+v", fv)
+	}
+	if got := paths(fv.Elements); !equal(got, []string{"User", "Shop/API"}) {
+		t.Errorf("got filtered view elements %v", got)
+	}
+	if len(fv.Relationships) != 0 {
+		t.Errorf("got %d filtered view relationships, want 0", len(fv.Relationships))
+	}
+	if got := cv.Enclosing(g.Element("orders")); got != g.Element("api") {
+		t.Errorf("got enclosing element %v, want API", got)
+	}
+	if got := cv.Enclosing(g.Element("api")); got != nil {
+		t.Errorf("got enclosing element %v, want none", got)
+	}
+	if g.View("Unknown") != nil {
+		t.Errorf("got view for unknown key")
+	}
+}
+
+func TestCustomTags(t *testing.T) {
+	t.Parallel()
+	g := New(testDesign())
+
+	if got := g.Element("admin").CustomTags(); !equal(got, []string{"Staff"}) {
+		t.Errorf("got custom tags %v", got)
+	}
+	if got := g.Element("user").CustomTags(); len(got) != 0 {
+		t.Errorf("got custom tags %v, want none", got)
+	}
+}
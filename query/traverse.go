@@ -0,0 +1,137 @@
+package query
+
+import (
+	"goa.design/model/mdl"
+)
+
+// ImpliedOutgoing returns the relationships whose source is the element with
+// the given ID or one of its descendants and whose destination is outside of
+// the element. This makes it possible to consider the relationships of the
+// components of a container as relationships of the container for example.
+func (g *Graph) ImpliedOutgoing(id string) []*mdl.Relationship {
+	e := g.byID[id]
+	if e == nil {
+		return nil
+	}
+	var res []*mdl.Relationship
+	for _, src := range append([]*Element{e}, e.Descendants()...) {
+		for _, r := range g.outgoing[src.ID] {
+			if dest := g.byID[r.DestinationID]; dest == nil || !e.Contains(dest) {
+				res = append(res, r)
+			}
+		}
+	}
+	return res
+}
+
+// ImpliedIncoming returns the relationships whose destination is the element
+// with the given ID or one of its descendants and whose source is outside of
+// the element.
+func (g *Graph) ImpliedIncoming(id string) []*mdl.Relationship {
+	e := g.byID[id]
+	if e == nil {
+		return nil
+	}
+	var res []*mdl.Relationship
+	for _, dest := range append([]*Element{e}, e.Descendants()...) {
+		for _, r := range g.incoming[dest.ID] {
+			if src := g.byID[r.SourceID]; src == nil || !e.Contains(src) {
+				res = append(res, r)
+			}
+		}
+	}
+	return res
+}
+
+// Dependencies returns the elements that the element with the given ID
+// depends on directly or transitively, closest first. The relationships of
+// the descendants of an element are considered relationships of the element
+// (see ImpliedOutgoing).
+func (g *Graph) Dependencies(id string) []*Element {
+	return g.walk(id, func(id string) []string {
+		var ids []string
+		for _, r := range g.ImpliedOutgoing(id) {
+			ids = append(ids, r.DestinationID)
+		}
+		return ids
+	})
+}
+
+// Dependents returns the elements that depend on the element with the given
+// ID directly or transitively, closest first. The relationships to the
+// descendants of an element are considered relationships to the element (see
+// ImpliedIncoming).
+func (g *Graph) Dependents(id string) []*Element {
+	return g.walk(id, func(id string) []string {
+		var ids []string
+		for _, r := range g.ImpliedIncoming(id) {
+			ids = append(ids, r.SourceID)
+		}
+		return ids
+	})
+}
+
+// ShortestPath returns the shortest chain of relationships leading from the
+// element with ID from to the element with ID to or one of its descendants.
+// The relationships of the descendants of the elements along the way are
+// considered (see ImpliedOutgoing). ShortestPath returns nil if there is no
+// such chain.
+func (g *Graph) ShortestPath(from, to string) []*mdl.Relationship {
+	target := g.byID[to]
+	if target == nil || g.byID[from] == nil || from == to {
+		return nil
+	}
+	// prev records the element each visited element was reached from and
+	// via the relationship.
+	type step struct {
+		from string
+		rel  *mdl.Relationship
+	}
+	prev := map[string]step{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, r := range g.ImpliedOutgoing(id) {
+			if _, ok := prev[r.DestinationID]; ok {
+				continue
+			}
+			prev[r.DestinationID] = step{from: id, rel: r}
+			if dest := g.byID[r.DestinationID]; dest != nil && target.Contains(dest) {
+				var path []*mdl.Relationship
+				for s := prev[r.DestinationID]; s.rel != nil; s = prev[s.from] {
+					path = append([]*mdl.Relationship{s.rel}, path...)
+				}
+				return path
+			}
+			queue = append(queue, r.DestinationID)
+		}
+	}
+	return nil
+}
+
+// walk returns the elements reachable from the element with the given ID
+// using next to list the neighbors of each element in breadth first order.
+func (g *Graph) walk(id string, next func(string) []string) []*Element {
+	if g.byID[id] == nil {
+		return nil
+	}
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	var res []*Element
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, n := range next(cur) {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			if e := g.byID[n]; e != nil {
+				res = append(res, e)
+				queue = append(queue, n)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,276 @@
+package query
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"goa.design/model/mdl"
+)
+
+type (
+	// View is a view of the design resolved against the graph: the element
+	// and relationship IDs listed by the view are replaced with the
+	// corresponding elements and relationships.
+	View struct {
+		// Key of view.
+		Key string
+		// Title of view, may be empty.
+		Title string
+		// Description of view, may be empty.
+		Description string
+		// Kind of view. The kind of a filtered view is the kind of its
+		// base view.
+		Kind ViewKind
+		// Scope is the element the view is about: the software system of
+		// context, container and scoped deployment views, the container of
+		// component views, the component of code views and the optional
+		// scope of dynamic views. Scope is nil for other views.
+		Scope *Element
+		// Environment is the deployment environment of deployment views.
+		Environment string
+		// Props are the view properties, the properties of the base view
+		// for filtered views.
+		Props *mdl.ViewProps
+		// Elements lists the elements shown in the view in the view order.
+		Elements []*Element
+		// Relationships lists the relationships shown in the view whose
+		// source and destination are in Elements, sorted by order for
+		// dynamic views.
+		Relationships []*ViewRelationship
+	}
+
+	// ViewRelationship is a relationship shown in a view.
+	ViewRelationship struct {
+		// Relationship is the model relationship.
+		Relationship *mdl.Relationship
+		// Source is the relationship source element.
+		Source *Element
+		// Destination is the relationship destination element.
+		Destination *Element
+		// Description is the description of the relationship in the view:
+		// the description set in the view if any, the relationship
+		// description otherwise.
+		Description string
+		// Order is the order of the relationship in dynamic views.
+		Order string
+		// Props are the relationship view properties.
+		Props *mdl.RelationshipView
+	}
+
+	// ViewKind is the enum for possible view kinds.
+	ViewKind int
+)
+
+const (
+	// ViewUndefined means the kind of the view is unknown.
+	ViewUndefined ViewKind = iota
+	// ViewLandscape is the kind of system landscape views.
+	ViewLandscape
+	// ViewContext is the kind of system context views.
+	ViewContext
+	// ViewContainer is the kind of container views.
+	ViewContainer
+	// ViewComponent is the kind of component views.
+	ViewComponent
+	// ViewCode is the kind of code views.
+	ViewCode
+	// ViewDynamic is the kind of dynamic views.
+	ViewDynamic
+	// ViewDeployment is the kind of deployment views.
+	ViewDeployment
+)
+
+// AllViews returns the views of the design resolved against the graph in the
+// order they are defined in mdl.Views, filtered views last.
+func (g *Graph) AllViews() []*View {
+	if g.Design == nil || g.Design.Views == nil {
+		return nil
+	}
+	v := g.Design.Views
+	var res []*View
+	for _, lv := range v.LandscapeViews {
+		res = append(res, g.resolve(lv.ViewProps, ViewLandscape, "", ""))
+	}
+	for _, cv := range v.ContextViews {
+		res = append(res, g.resolve(cv.ViewProps, ViewContext, cv.SoftwareSystemID, ""))
+	}
+	for _, cv := range v.ContainerViews {
+		res = append(res, g.resolve(cv.ViewProps, ViewContainer, cv.SoftwareSystemID, ""))
+	}
+	for _, cv := range v.ComponentViews {
+		res = append(res, g.resolve(cv.ViewProps, ViewComponent, cv.ContainerID, ""))
+	}
+	for _, cv := range v.CodeViews {
+		res = append(res, g.resolve(cv.ViewProps, ViewCode, cv.ComponentID, ""))
+	}
+	for _, dv := range v.DynamicViews {
+		res = append(res, g.resolve(dv.ViewProps, ViewDynamic, dv.ElementID, ""))
+	}
+	for _, dv := range v.DeploymentViews {
+		res = append(res, g.resolve(dv.ViewProps, ViewDeployment, dv.SoftwareSystemID, dv.Environment))
+	}
+	for _, fv := range v.FilteredViews {
+		for _, base := range res {
+			if base.Key == fv.BaseKey {
+				res = append(res, filter(base, fv))
+				break
+			}
+		}
+	}
+	return res
+}
+
+// View returns the view with the given key resolved against the graph, nil if
+// there is none.
+func (g *Graph) View(key string) *View {
+	for _, v := range g.AllViews() {
+		if v.Key == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// Includes returns true if the view shows the element with the given ID.
+func (v *View) Includes(id string) bool {
+	for _, e := range v.Elements {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Enclosing returns the closest ancestor of e shown in the view, nil if there
+// is none.
+func (v *View) Enclosing(e *Element) *Element {
+	for p := e.Parent; p != nil; p = p.Parent {
+		if v.Includes(p.ID) {
+			return p
+		}
+	}
+	return nil
+}
+
+// String returns the name of the view kind.
+func (k ViewKind) String() string {
+	switch k {
+	case ViewLandscape:
+		return "System Landscape"
+	case ViewContext:
+		return "System Context"
+	case ViewContainer:
+		return "Container"
+	case ViewComponent:
+		return "Component"
+	case ViewCode:
+		return "Code"
+	case ViewDynamic:
+		return "Dynamic"
+	case ViewDeployment:
+		return "Deployment"
+	default:
+		return "Undefined"
+	}
+}
+
+// resolve returns the view described by vp.
+func (g *Graph) resolve(vp *mdl.ViewProps, kind ViewKind, scope, env string) *View {
+	v := &View{
+		Key:         vp.Key,
+		Title:       vp.Title,
+		Description: vp.Description,
+		Kind:        kind,
+		Scope:       g.byID[scope],
+		Environment: env,
+		Props:       vp,
+	}
+	for _, ev := range vp.ElementViews {
+		if e := g.byID[ev.ID]; e != nil {
+			v.Elements = append(v.Elements, e)
+		}
+	}
+	for _, rv := range vp.RelationshipViews {
+		r := g.rels[rv.ID]
+		if r == nil {
+			continue
+		}
+		src, dest := g.byID[r.SourceID], g.byID[r.DestinationID]
+		if src == nil || dest == nil || !v.Includes(src.ID) || !v.Includes(dest.ID) {
+			continue
+		}
+		desc := rv.Description
+		if desc == "" {
+			desc = r.Description
+		}
+		v.Relationships = append(v.Relationships, &ViewRelationship{
+			Relationship: r,
+			Source:       src,
+			Destination:  dest,
+			Description:  desc,
+			Order:        rv.Order,
+			Props:        rv,
+		})
+	}
+	if kind == ViewDynamic {
+		sort.SliceStable(v.Relationships, func(i, j int) bool {
+			return orderLess(v.Relationships[i].Order, v.Relationships[j].Order)
+		})
+	}
+	return v
+}
+
+// filter returns the view resulting from applying the filtered view fv to
+// base.
+func filter(base *View, fv *mdl.FilteredView) *View {
+	include := !strings.EqualFold(fv.Mode, "Exclude")
+	keep := func(tags string) bool {
+		for _, t := range strings.Split(tags, ",") {
+			for _, ft := range fv.Tags {
+				if strings.TrimSpace(t) == ft {
+					return include
+				}
+			}
+		}
+		return !include
+	}
+	title := fv.Title
+	if title == "" {
+		title = base.Title
+	}
+	desc := fv.Description
+	if desc == "" {
+		desc = base.Description
+	}
+	v := &View{
+		Key:         fv.Key,
+		Title:       title,
+		Description: desc,
+		Kind:        base.Kind,
+		Scope:       base.Scope,
+		Environment: base.Environment,
+		Props:       base.Props,
+	}
+	for _, e := range base.Elements {
+		if keep(e.Tags) {
+			v.Elements = append(v.Elements, e)
+		}
+	}
+	for _, r := range base.Relationships {
+		if keep(r.Relationship.Tags) && v.Includes(r.Source.ID) && v.Includes(r.Destination.ID) {
+			v.Relationships = append(v.Relationships, r)
+		}
+	}
+	return v
+}
+
+// orderLess compares dynamic view orders numerically when possible.
+func orderLess(a, b string) bool {
+	ai, aerr := strconv.Atoi(a)
+	bi, berr := strconv.Atoi(b)
+	if aerr == nil && berr == nil {
+		return ai < bi
+	}
+	return a < b
+}
@@ -0,0 +1,798 @@
+package stz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+type (
+	// dslWriter writes the Structurizr DSL representation of a design.
+	dslWriter struct {
+		b      strings.Builder
+		indent int
+		// idents maps element and relationship IDs to DSL identifiers.
+		idents map[string]string
+		// used lists the identifiers that are already taken.
+		used map[string]bool
+		// opened is true if the last line written opened a block.
+		opened bool
+	}
+)
+
+// WriteDSL writes the Structurizr DSL (workspace.dsl) representation of the
+// design to w. Views list the elements they include explicitly and exclude
+// the relationships that are not rendered so that the result is independent
+// of the Structurizr default inclusion rules. Implied relationships are
+// written explicitly and automatic implied relationships are disabled.
+func WriteDSL(w io.Writer, d *mdl.Design) error {
+	dw := &dslWriter{idents: make(map[string]string), used: make(map[string]bool)}
+	for _, k := range dslKeywords {
+		dw.used[k] = true
+	}
+	if d.Model == nil {
+		d.Model = &mdl.Model{}
+	}
+	dw.identify(d)
+	excludes := dw.excludedRelationships(d)
+	for id := range excludes {
+		dw.identifyRelationship(id)
+	}
+
+	dw.open("workspace %s", args(d.Name, d.Description))
+	dw.line("!impliedRelationships false")
+	dw.sep()
+	dw.writeModel(d.Model)
+	dw.sep()
+	dw.writeViews(d, excludes)
+	dw.close()
+
+	_, err := io.WriteString(w, dw.b.String())
+	return err
+}
+
+// dslKeywords lists the Structurizr DSL keywords that cannot be used as
+// identifiers.
+var dslKeywords = []string{
+	"workspace", "model", "views", "enterprise", "group", "person",
+	"softwareSystem", "container", "component", "deploymentEnvironment",
+	"deploymentGroup", "deploymentNode", "infrastructureNode",
+	"softwareSystemInstance", "containerInstance", "element", "relationship",
+	"styles", "this",
+}
+
+// identify assigns a DSL identifier to each element of the design, derived
+// from the element path.
+func (dw *dslWriter) identify(d *mdl.Design) {
+	for _, e := range query.New(d).Elements() {
+		dw.idents[e.ID] = dw.unique(identifier(e.Path))
+	}
+}
+
+// identifyRelationship assigns a DSL identifier to the relationship with the
+// given ID.
+func (dw *dslWriter) identifyRelationship(id string) {
+	if _, ok := dw.idents[id]; !ok {
+		dw.idents[id] = dw.unique("rel")
+	}
+}
+
+// unique returns ident or ident followed by the smallest number that makes it
+// unique.
+func (dw *dslWriter) unique(ident string) string {
+	res := ident
+	for i := 2; dw.used[res]; i++ {
+		res = fmt.Sprintf("%s%d", ident, i)
+	}
+	dw.used[res] = true
+	return res
+}
+
+// excludedRelationships returns the IDs of the relationships that must be
+// excluded from each view, indexed by relationship ID. A relationship must be
+// excluded if both its source and destination are included in the view but
+// the relationship is not.
+func (dw *dslWriter) excludedRelationships(d *mdl.Design) map[string]bool {
+	res := make(map[string]bool)
+	if d.Views == nil {
+		return res
+	}
+	g := query.New(d)
+	for _, vp := range staticViews(d.Views) {
+		for _, id := range viewExcludes(g, vp) {
+			res[id] = true
+		}
+	}
+	return res
+}
+
+// writeModel writes the model block.
+func (dw *dslWriter) writeModel(m *mdl.Model) {
+	dw.open("model")
+	// Elements that are not explicitly external belong to the enterprise.
+	var internal, external []any
+	for _, p := range m.People {
+		if m.Enterprise != nil && p.Location != mdl.LocationExternal {
+			internal = append(internal, p)
+		} else {
+			external = append(external, p)
+		}
+	}
+	for _, s := range m.Systems {
+		if m.Enterprise != nil && s.Location != mdl.LocationExternal {
+			internal = append(internal, s)
+		} else {
+			external = append(external, s)
+		}
+	}
+	if len(internal) > 0 {
+		dw.open("enterprise %s", args(m.Enterprise.Name))
+		dw.writeGrouped(internal)
+		dw.close()
+	}
+	dw.writeGrouped(external)
+
+	envs := make(map[string][]*mdl.DeploymentNode)
+	var envNames []string
+	for _, n := range m.DeploymentNodes {
+		if _, ok := envs[n.Environment]; !ok {
+			envNames = append(envNames, n.Environment)
+		}
+		envs[n.Environment] = append(envs[n.Environment], n)
+	}
+	for _, env := range envNames {
+		dw.sep()
+		dw.open("deploymentEnvironment %s", args(env))
+		for _, name := range deploymentGroups(envs[env]) {
+			ident := dw.unique(identifier(name))
+			dw.idents[deploymentGroupKey(env, name)] = ident
+			dw.line("%s = deploymentGroup %s", ident, quote(name))
+		}
+		for _, n := range envs[env] {
+			dw.writeDeploymentNode(n)
+		}
+		dw.close()
+	}
+
+	dw.sep()
+	dw.writeRelationships(m)
+	dw.close()
+}
+
+// writeGrouped writes the given elements, elements that belong to a group are
+// written in a group block.
+func (dw *dslWriter) writeGrouped(elems []any) {
+	var groups []string
+	grouped := make(map[string][]any)
+	for _, e := range elems {
+		g := elementGroup(e)
+		if g == "" {
+			dw.writeElement(e)
+			continue
+		}
+		if _, ok := grouped[g]; !ok {
+			groups = append(groups, g)
+		}
+		grouped[g] = append(grouped[g], e)
+	}
+	for _, g := range groups {
+		dw.open("group %s", args(g))
+		for _, e := range grouped[g] {
+			dw.writeElement(e)
+		}
+		dw.close()
+	}
+}
+
+// writeElement writes the declaration of a person, software system,
+// container or component and of its children.
+func (dw *dslWriter) writeElement(v any) {
+	switch e := v.(type) {
+	case *mdl.Person:
+		dw.declare(e.ID, "person", args(e.Name, e.Description, customTags(e.Tags, expr.PersonTags)), e.URL, e.Properties, nil)
+	case *mdl.SoftwareSystem:
+		children := make([]any, len(e.Containers))
+		for i, c := range e.Containers {
+			children[i] = c
+		}
+		dw.declare(e.ID, "softwareSystem", args(e.Name, e.Description, customTags(e.Tags, expr.SoftwareSystemTags)), e.URL, e.Properties, children)
+	case *mdl.Container:
+		children := make([]any, len(e.Components))
+		for i, c := range e.Components {
+			children[i] = c
+		}
+		dw.declare(e.ID, "container", args(e.Name, e.Description, e.Technology, customTags(e.Tags, expr.ContainerTags)), e.URL, e.Properties, children)
+	case *mdl.Component:
+		dw.declare(e.ID, "component", args(e.Name, e.Description, e.Technology, customTags(e.Tags, expr.ComponentTags)), e.URL, e.Properties, nil)
+	}
+}
+
+// declare writes an element declaration with the given keyword and
+// arguments followed by a block listing its URL, properties and children if
+// any.
+func (dw *dslWriter) declare(id, keyword, arguments, url string, props map[string]string, children []any) {
+	decl := fmt.Sprintf("%s = %s %s", dw.idents[id], keyword, arguments)
+	if url == "" && len(props) == 0 && len(children) == 0 {
+		dw.line("%s", decl)
+		return
+	}
+	dw.open("%s", decl)
+	dw.writeDetails(url, props)
+	dw.writeGrouped(children)
+	dw.close()
+}
+
+// writeDetails writes the url and properties of an element.
+func (dw *dslWriter) writeDetails(url string, props map[string]string) {
+	if url != "" {
+		dw.line("url %s", url)
+	}
+	if len(props) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	dw.open("properties")
+	for _, k := range keys {
+		dw.line("%s %s", quote(k), quote(props[k]))
+	}
+	dw.close()
+}
+
+// writeDeploymentNode writes the declaration of a deployment node and of its
+// children.
+func (dw *dslWriter) writeDeploymentNode(n *mdl.DeploymentNode) {
+	var instances string
+	if n.Instances != nil && *n.Instances != "1" {
+		instances = *n.Instances
+	}
+	a := args(n.Name, n.Description, n.Technology, customTags(n.Tags, expr.DeploymentNodeTags), instances)
+	dw.open("%s = deploymentNode %s", dw.idents[n.ID], a)
+	dw.writeDetails(n.URL, n.Properties)
+	for _, i := range n.InfrastructureNodes {
+		decl := fmt.Sprintf("%s = infrastructureNode %s", dw.idents[i.ID], args(i.Name, i.Description, i.Technology, customTags(i.Tags, expr.InfrastructureNodeTags)))
+		if i.URL == "" && len(i.Properties) == 0 {
+			dw.line("%s", decl)
+			continue
+		}
+		dw.open("%s", decl)
+		dw.writeDetails(i.URL, i.Properties)
+		dw.close()
+	}
+	for _, si := range n.SoftwareSystemInstances {
+		dw.writeInstance(si.ID, "softwareSystemInstance", si.SoftwareSystemID, dw.groupIdents(n.Environment, si.DeploymentGroups), customTags(si.Tags, append([]string{"Element"}, expr.SoftwareSystemInstanceTags...)), si.URL, si.Properties, si.HealthChecks)
+	}
+	for _, ci := range n.ContainerInstances {
+		dw.writeInstance(ci.ID, "containerInstance", ci.ContainerID, dw.groupIdents(n.Environment, ci.DeploymentGroups), customTags(ci.Tags, append([]string{"Element"}, expr.ContainerInstanceTags...)), ci.URL, ci.Properties, ci.HealthChecks)
+	}
+	for _, c := range n.Children {
+		dw.writeDeploymentNode(c)
+	}
+	dw.close()
+}
+
+// writeInstance writes the declaration of a container or software system
+// instance.
+func (dw *dslWriter) writeInstance(id, keyword, instanceOf string, groups []string, tags, url string, props map[string]string, checks []*mdl.HealthCheck) {
+	decl := fmt.Sprintf("%s = %s %s", dw.idents[id], keyword, dw.idents[instanceOf])
+	if len(groups) > 0 {
+		decl += " " + strings.Join(groups, ",")
+	}
+	if tags == "" && url == "" && len(props) == 0 && len(checks) == 0 {
+		dw.line("%s", decl)
+		return
+	}
+	dw.open("%s", decl)
+	if tags != "" {
+		// Set in the block as the tags argument requires deployment groups.
+		dw.line("tags %s", quote(tags))
+	}
+	dw.writeDetails(url, props)
+	for _, hc := range checks {
+		dw.line("healthCheck %s %s %d %d", quote(hc.Name), hc.URL, hc.Interval, hc.Timeout)
+	}
+	dw.close()
+}
+
+// groupIdents returns the identifiers of the deployment groups with the given
+// names in the given environment.
+func (dw *dslWriter) groupIdents(env string, names []string) []string {
+	res := make([]string, len(names))
+	for i, n := range names {
+		res[i] = dw.idents[deploymentGroupKey(env, n)]
+	}
+	return res
+}
+
+// writeRelationships writes all the relationships of the model except the
+// relationships between deployment instances that are derived from the
+// relationships between the corresponding containers or systems.
+func (dw *dslWriter) writeRelationships(m *mdl.Model) {
+	g := query.New(&mdl.Design{Model: m})
+	for _, e := range g.Elements() {
+		for _, rel := range g.Outgoing(e.ID) {
+			if rel.LinkedRelationshipID != "" {
+				continue
+			}
+			tags := customTags(rel.Tags, []string{expr.RelationshipTags[0], "Synchronous"})
+			decl := fmt.Sprintf("%s -> %s", dw.idents[rel.SourceID], dw.idents[rel.DestinationID])
+			if ident, ok := dw.idents[rel.ID]; ok {
+				decl = ident + " = " + decl
+			}
+			if a := args(rel.Description, rel.Technology, tags); a != "" {
+				decl += " " + a
+			}
+			if rel.URL == "" {
+				dw.line("%s", decl)
+				continue
+			}
+			dw.open("%s", decl)
+			dw.writeDetails(rel.URL, nil)
+			dw.close()
+		}
+	}
+}
+
+// writeViews writes the views block.
+func (dw *dslWriter) writeViews(d *mdl.Design, excludes map[string]bool) {
+	dw.open("views")
+	if v := d.Views; v != nil {
+		g := query.New(d)
+		for _, lv := range v.LandscapeViews {
+			dw.writeStaticView(g, fmt.Sprintf("systemLandscape %s", args(lv.Key, lv.Description)), lv.ViewProps)
+		}
+		for _, cv := range v.ContextViews {
+			dw.writeStaticView(g, fmt.Sprintf("systemContext %s %s", dw.idents[cv.SoftwareSystemID], args(cv.Key, cv.Description)), cv.ViewProps)
+		}
+		for _, cv := range v.ContainerViews {
+			dw.writeStaticView(g, fmt.Sprintf("container %s %s", dw.idents[cv.SoftwareSystemID], args(cv.Key, cv.Description)), cv.ViewProps)
+		}
+		for _, cv := range v.ComponentViews {
+			dw.writeStaticView(g, fmt.Sprintf("component %s %s", dw.idents[cv.ContainerID], args(cv.Key, cv.Description)), cv.ViewProps)
+		}
+		for _, dv := range v.DynamicViews {
+			scope := "*"
+			if dv.ElementID != "" {
+				scope = dw.idents[dv.ElementID]
+			}
+			dw.writeDynamicView(g, fmt.Sprintf("dynamic %s %s", scope, args(dv.Key, dv.Description)), dv.ViewProps)
+		}
+		for _, dv := range v.DeploymentViews {
+			scope := "*"
+			if dv.SoftwareSystemID != "" {
+				scope = dw.idents[dv.SoftwareSystemID]
+			}
+			dw.writeStaticView(g, fmt.Sprintf("deployment %s %s", scope, args(dv.Environment, dv.Key, dv.Description)), dv.ViewProps)
+		}
+		for _, fv := range v.FilteredViews {
+			mode := "include"
+			if strings.EqualFold(fv.Mode, "Exclude") {
+				mode = "exclude"
+			}
+			dw.sep()
+			dw.line("filtered %s %s %s", quote(fv.BaseKey), mode, args(strings.Join(fv.Tags, ","), fv.Key, fv.Description))
+		}
+		if v.Styles != nil && (len(v.Styles.Elements) > 0 || len(v.Styles.Relationships) > 0) {
+			dw.sep()
+			dw.writeStyles(v.Styles)
+		}
+	}
+	dw.close()
+}
+
+// writeStaticView writes a view that lists its elements explicitly.
+func (dw *dslWriter) writeStaticView(g *query.Graph, decl string, vp *mdl.ViewProps) {
+	dw.sep()
+	dw.open("%s", decl)
+	if vp.Title != "" {
+		dw.line("title %s", quote(vp.Title))
+	}
+	if len(vp.ElementViews) > 0 {
+		ids := make([]string, 0, len(vp.ElementViews))
+		for _, ev := range vp.ElementViews {
+			if ident, ok := dw.idents[ev.ID]; ok {
+				ids = append(ids, ident)
+			}
+		}
+		dw.line("include %s", strings.Join(ids, " "))
+	}
+	if excl := viewExcludes(g, vp); len(excl) > 0 {
+		ids := make([]string, len(excl))
+		for i, id := range excl {
+			ids[i] = dw.idents[id]
+		}
+		dw.line("exclude %s", strings.Join(ids, " "))
+	}
+	dw.writeAnimations(vp)
+	dw.writeAutoLayout(vp)
+	dw.close()
+}
+
+// writeDynamicView writes a dynamic view, one step per relationship.
+func (dw *dslWriter) writeDynamicView(g *query.Graph, decl string, vp *mdl.ViewProps) {
+	dw.sep()
+	dw.open("%s", decl)
+	if vp.Title != "" {
+		dw.line("title %s", quote(vp.Title))
+	}
+	rvs := make([]*mdl.RelationshipView, len(vp.RelationshipViews))
+	copy(rvs, vp.RelationshipViews)
+	sort.SliceStable(rvs, func(i, j int) bool { return orderLess(rvs[i].Order, rvs[j].Order) })
+	for _, rv := range rvs {
+		rel := g.Relationship(rv.ID)
+		if rel == nil {
+			continue
+		}
+		desc := rv.Description
+		if desc == "" {
+			desc = rel.Description
+		}
+		step := fmt.Sprintf("%s -> %s", dw.idents[rel.SourceID], dw.idents[rel.DestinationID])
+		if a := args(desc, rel.Technology); a != "" {
+			step += " " + a
+		}
+		dw.line("%s", step)
+	}
+	dw.writeAutoLayout(vp)
+	dw.close()
+}
+
+// writeAnimations writes the animation steps of a view.
+func (dw *dslWriter) writeAnimations(vp *mdl.ViewProps) {
+	if len(vp.Animations) == 0 {
+		return
+	}
+	steps := make([]*mdl.AnimationStep, len(vp.Animations))
+	copy(steps, vp.Animations)
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].Order < steps[j].Order })
+	dw.open("animation")
+	for _, s := range steps {
+		ids := make([]string, 0, len(s.Elements))
+		seen := make(map[string]bool, len(s.Elements))
+		for _, id := range s.Elements {
+			if ident, ok := dw.idents[id]; ok && !seen[ident] {
+				seen[ident] = true
+				ids = append(ids, ident)
+			}
+		}
+		if len(ids) > 0 {
+			dw.line("%s", strings.Join(ids, " "))
+		}
+	}
+	dw.close()
+}
+
+// writeAutoLayout writes the automatic layout settings of a view if any.
+func (dw *dslWriter) writeAutoLayout(vp *mdl.ViewProps) {
+	al := vp.AutoLayout
+	if al == nil {
+		return
+	}
+	dir := map[mdl.RankDirectionKind]string{
+		mdl.RankTopBottom: "tb",
+		mdl.RankBottomTop: "bt",
+		mdl.RankLeftRight: "lr",
+		mdl.RankRightLeft: "rl",
+	}[al.RankDirection]
+	if dir == "" {
+		dir = "tb"
+	}
+	decl := "autoLayout " + dir
+	if al.RankSep != nil {
+		decl += fmt.Sprintf(" %d", *al.RankSep)
+		if al.NodeSep != nil {
+			decl += fmt.Sprintf(" %d", *al.NodeSep)
+		}
+	}
+	dw.line("%s", decl)
+}
+
+// writeStyles writes the styles block.
+func (dw *dslWriter) writeStyles(s *mdl.Styles) {
+	dw.open("styles")
+	for _, es := range s.Elements {
+		dw.open("element %s", quote(es.Tag))
+		if es.Shape != mdl.ShapeUndefined {
+			dw.line("shape %s", enumString(es.Shape))
+		}
+		if es.Icon != "" {
+			dw.line("icon %s", es.Icon)
+		}
+		dw.intLine("width", es.Width)
+		dw.intLine("height", es.Height)
+		if es.Background != "" {
+			dw.line("background %s", es.Background)
+		}
+		if es.Color != "" {
+			dw.line("color %s", es.Color)
+		}
+		if es.Stroke != "" {
+			dw.line("stroke %s", es.Stroke)
+		}
+		dw.intLine("fontSize", es.FontSize)
+		if es.Border != mdl.BorderUndefined {
+			dw.line("border %s", strings.ToLower(enumString(es.Border)))
+		}
+		dw.intLine("opacity", es.Opacity)
+		dw.boolLine("metadata", es.Metadata)
+		dw.boolLine("description", es.Description)
+		dw.close()
+	}
+	for _, rs := range s.Relationships {
+		dw.open("relationship %s", quote(rs.Tag))
+		dw.intLine("thickness", rs.Thickness)
+		if rs.Color != "" {
+			dw.line("color %s", rs.Color)
+		}
+		if rs.Dashed != nil {
+			style := "solid"
+			if *rs.Dashed {
+				style = "dashed"
+			}
+			dw.line("style %s", style)
+		}
+		if rs.Routing != mdl.RoutingUndefined {
+			dw.line("routing %s", enumString(rs.Routing))
+		}
+		dw.intLine("fontSize", rs.FontSize)
+		dw.intLine("width", rs.Width)
+		dw.intLine("position", rs.Position)
+		dw.intLine("opacity", rs.Opacity)
+		dw.close()
+	}
+	dw.close()
+}
+
+// intLine writes the given style property if set.
+func (dw *dslWriter) intLine(name string, v *int) {
+	if v != nil {
+		dw.line("%s %d", name, *v)
+	}
+}
+
+// boolLine writes the given style property if set.
+func (dw *dslWriter) boolLine(name string, v *bool) {
+	if v != nil {
+		dw.line("%s %t", name, *v)
+	}
+}
+
+// line writes an indented line.
+func (dw *dslWriter) line(format string, a ...any) {
+	dw.b.WriteString(strings.Repeat("    ", dw.indent))
+	fmt.Fprintf(&dw.b, format, a...)
+	dw.b.WriteByte('\n')
+	dw.opened = false
+}
+
+// sep writes an empty line unless the previous line opened a block.
+func (dw *dslWriter) sep() {
+	if !dw.opened {
+		dw.b.WriteByte('\n')
+	}
+}
+
+// open writes a line that opens a block.
+func (dw *dslWriter) open(format string, a ...any) {
+	dw.line("%s {", strings.TrimSpace(fmt.Sprintf(format, a...)))
+	dw.indent++
+	dw.opened = true
+}
+
+// close writes the line that closes the current block.
+func (dw *dslWriter) close() {
+	dw.indent--
+	dw.line("}")
+}
+
+// staticViews returns the properties of all the views that list their
+// elements explicitly.
+func staticViews(v *mdl.Views) []*mdl.ViewProps {
+	var res []*mdl.ViewProps
+	for _, lv := range v.LandscapeViews {
+		res = append(res, lv.ViewProps)
+	}
+	for _, cv := range v.ContextViews {
+		res = append(res, cv.ViewProps)
+	}
+	for _, cv := range v.ContainerViews {
+		res = append(res, cv.ViewProps)
+	}
+	for _, cv := range v.ComponentViews {
+		res = append(res, cv.ViewProps)
+	}
+	for _, dv := range v.DeploymentViews {
+		res = append(res, dv.ViewProps)
+	}
+	return res
+}
+
+// viewExcludes returns the IDs of the relationships between elements of the
+// view that the view does not include. Relationships between deployment
+// instances that are derived from other relationships cannot be referred to
+// in the DSL and are ignored.
+func viewExcludes(g *query.Graph, vp *mdl.ViewProps) []string {
+	in := make(map[string]bool, len(vp.ElementViews))
+	for _, ev := range vp.ElementViews {
+		in[ev.ID] = true
+	}
+	shown := make(map[string]bool, len(vp.RelationshipViews))
+	for _, rv := range vp.RelationshipViews {
+		shown[rv.ID] = true
+	}
+	var res []string
+	for _, ev := range vp.ElementViews {
+		for _, rel := range g.Outgoing(ev.ID) {
+			if in[rel.DestinationID] && !shown[rel.ID] && rel.LinkedRelationshipID == "" {
+				res = append(res, rel.ID)
+			}
+		}
+	}
+	return res
+}
+
+// deploymentGroups returns the names of the deployment groups of the
+// instances deployed in the given nodes and their children.
+func deploymentGroups(nodes []*mdl.DeploymentNode) []string {
+	var res []string
+	seen := make(map[string]bool)
+	add := func(groups []string) {
+		for _, g := range groups {
+			if !seen[g] {
+				seen[g] = true
+				res = append(res, g)
+			}
+		}
+	}
+	var walk func([]*mdl.DeploymentNode)
+	walk = func(nodes []*mdl.DeploymentNode) {
+		for _, n := range nodes {
+			for _, si := range n.SoftwareSystemInstances {
+				add(si.DeploymentGroups)
+			}
+			for _, ci := range n.ContainerInstances {
+				add(ci.DeploymentGroups)
+			}
+			walk(n.Children)
+		}
+	}
+	walk(nodes)
+	return res
+}
+
+// deploymentGroupKey returns the key used to index the identifiers of
+// deployment groups.
+func deploymentGroupKey(env, name string) string {
+	return "deploymentGroup:" + env + "/" + name
+}
+
+// elementGroup returns the group of the given element if any.
+func elementGroup(v any) string {
+	switch e := v.(type) {
+	case *mdl.Person:
+		return e.Group
+	case *mdl.SoftwareSystem:
+		return e.Group
+	case *mdl.Container:
+		return e.Group
+	case *mdl.Component:
+		return e.Group
+	}
+	return ""
+}
+
+// customTags returns the comma separated list of tags omitting the given
+// default tags which Structurizr adds automatically.
+func customTags(tags string, defaults []string) string {
+	var res []string
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		isDefault := false
+		for _, d := range defaults {
+			if t == d {
+				isDefault = true
+				break
+			}
+		}
+		if !isDefault {
+			res = append(res, t)
+		}
+	}
+	return strings.Join(res, ",")
+}
+
+// identifier returns a camel case DSL identifier derived from the given
+// element path, e.g. "Internet Banking System/API Application" becomes
+// "internetBankingSystemAPIApplication".
+func identifier(path string) string {
+	var words []string
+	var word strings.Builder
+	for _, r := range path {
+		switch {
+		case r == '\'' || r == '’':
+			// Keep possessives in the same word.
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		default:
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	if len(words) == 0 {
+		return "element"
+	}
+	var b strings.Builder
+	for i, w := range words {
+		switch {
+		case i > 0:
+			b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+		case strings.ToUpper(w) == w:
+			b.WriteString(strings.ToLower(w))
+		default:
+			b.WriteString(strings.ToLower(w[:1]) + w[1:])
+		}
+	}
+	res := b.String()
+	if unicode.IsDigit(rune(res[0])) {
+		res = "e" + res
+	}
+	return res
+}
+
+// args returns the quoted arguments omitting the trailing empty ones.
+func args(vals ...string) string {
+	n := len(vals)
+	for n > 0 && vals[n-1] == "" {
+		n--
+	}
+	quoted := make([]string, n)
+	for i, v := range vals[:n] {
+		quoted[i] = quote(v)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quote returns s as a quoted DSL string.
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// enumString returns the JSON string representation of an enum value.
+func enumString(v json.Marshaler) string {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(string(b), `"`)
+}
+
+// orderLess compares dynamic view relationship orders, numerically when
+// possible.
+func orderLess(a, b string) bool {
+	var na, nb int
+	_, erra := fmt.Sscanf(a, "%d", &na)
+	_, errb := fmt.Sscanf(b, "%d", &nb)
+	if erra == nil && errb == nil {
+		return na < nb
+	}
+	return a < b
+}
@@ -0,0 +1,165 @@
+package stz
+
+import (
+	"bytes"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestWriteDSL(t *testing.T) {
+	rankSep, nodeSep, thickness := 300, 200, 4
+	dashed := true
+	d := &mdl.Design{
+		Name:        "Shop",
+		Description: `The "shop" workspace`,
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person,Customer",
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Description: "Sells things", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container", Group: "Frontend",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship,Asynchronous"},
+							{ID: "r3", SourceID: "web", DestinationID: "db", Description: "Writes", Tags: "Relationship"},
+						},
+					},
+					{ID: "db", Name: "DB", Technology: "PostgreSQL", Tags: "Element,Container,Database", Properties: map[string]string{"owner": "dba"}},
+				},
+			}},
+			DeploymentNodes: []*mdl.DeploymentNode{{
+				ID: "node", Name: "Server", Environment: "Production", Tags: "Element,Deployment Node",
+				ContainerInstances: []*mdl.ContainerInstance{
+					{ID: "webInst", ContainerID: "web", Tags: "Element,Container Instance", DeploymentGroups: []string{"Blue"}},
+					{ID: "dbInst", ContainerID: "db", Tags: "Element,Container Instance,Primary"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					Title:             "Shop containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r2"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankLeftRight, RankSep: &rankSep, NodeSep: &nodeSep},
+				},
+			}},
+			DynamicViews: []*mdl.DynamicView{{
+				ElementID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Checkout",
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r3", Order: "2"}, {ID: "r1", Order: "1", Description: "Checks out"}},
+				},
+			}},
+			DeploymentViews: []*mdl.DeploymentView{{
+				Environment: "Production",
+				ViewProps: &mdl.ViewProps{
+					Key:          "Deployment",
+					ElementViews: []*mdl.ElementView{{ID: "node"}, {ID: "webInst"}, {ID: "dbInst"}},
+				},
+			}},
+			FilteredViews: []*mdl.FilteredView{{Key: "NoDB", BaseKey: "Containers", Mode: "Exclude", Tags: []string{"Database"}}},
+			Styles: &mdl.Styles{
+				Elements:      []*mdl.ElementStyle{{Tag: "Database", Shape: mdl.ShapeCylinder, Background: "#ffffff", Border: mdl.BorderDashed}},
+				Relationships: []*mdl.RelationshipStyle{{Tag: "Asynchronous", Dashed: &dashed, Thickness: &thickness, Routing: mdl.RoutingOrthogonal}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDSL(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+	want := `workspace "Shop" "The \"shop\" workspace" {
+    !impliedRelationships false
+
+    model {
+        user = person "User" "" "Customer"
+        shop = softwareSystem "Shop" "Sells things" {
+            shopDB = container "DB" "" "PostgreSQL" "Database" {
+                properties {
+                    "owner" "dba"
+                }
+            }
+            group "Frontend" {
+                shopWeb = container "Web" "" "Go"
+            }
+        }
+
+        deploymentEnvironment "Production" {
+            blue = deploymentGroup "Blue"
+            productionServer = deploymentNode "Server" {
+                productionServerWeb = containerInstance shopWeb blue
+                productionServerDB = containerInstance shopDB {
+                    tags "Primary"
+                }
+            }
+        }
+
+        user -> shopWeb "Browses" "HTTPS"
+        shopWeb -> shopDB "Reads" "" "Asynchronous"
+        rel = shopWeb -> shopDB "Writes"
+    }
+
+    views {
+        container shop "Containers" {
+            title "Shop containers"
+            include user shopWeb shopDB
+            exclude rel
+            autoLayout lr 300 200
+        }
+
+        dynamic shop "Checkout" {
+            user -> shopWeb "Checks out" "HTTPS"
+            shopWeb -> shopDB "Writes"
+        }
+
+        deployment * "Production" "Deployment" {
+            include productionServer productionServerWeb productionServerDB
+        }
+
+        filtered "Containers" exclude "Database" "NoDB"
+
+        styles {
+            element "Database" {
+                shape Cylinder
+                background #ffffff
+                border dashed
+            }
+            relationship "Asynchronous" {
+                thickness 4
+                style dashed
+                routing Orthogonal
+            }
+        }
+    }
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"ATM":                      "atm",
+		"Internet Banking System":  "internetBankingSystem",
+		"System/API Application":   "systemAPIApplication",
+		"Live/Customer's computer": "liveCustomersComputer",
+		"3rd Party":                "e3rdParty",
+		"---":                      "element",
+	}
+	for path, want := range cases {
+		if got := identifier(path); got != want {
+			t.Errorf("identifier(%q): got %q, want %q", path, got, want)
+		}
+	}
+}
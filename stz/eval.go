@@ -1,7 +1,10 @@
 package stz
 
 import (
+	"fmt"
+
 	"goa.design/goa/v3/eval"
+	"goa.design/model/adr"
 	"goa.design/model/expr"
 	"goa.design/model/mdl"
 )
@@ -36,5 +39,139 @@ func WorkspaceFromDesign(d *expr.Design) *Workspace {
 			FilteredViews:   v.FilteredViews,
 			Configuration:   &Configuration{Styles: v.Styles},
 		},
+		Documentation: documentationFromDesign(design.Documentation),
+	}
+}
+
+// DesignFromWorkspace returns the design described by the given Structurizr
+// workspace.
+func DesignFromWorkspace(w *Workspace) *mdl.Design {
+	d := &mdl.Design{
+		Name:        w.Name,
+		Description: w.Description,
+		Version:     w.Version,
+		Model:       w.Model,
+	}
+	if d.Model == nil {
+		d.Model = &mdl.Model{}
+	}
+	if v := w.Views; v != nil {
+		d.Views = &mdl.Views{
+			LandscapeViews:  v.LandscapeViews,
+			ContextViews:    v.ContextViews,
+			ContainerViews:  v.ContainerViews,
+			ComponentViews:  v.ComponentViews,
+			DynamicViews:    v.DynamicViews,
+			DeploymentViews: v.DeploymentViews,
+			FilteredViews:   v.FilteredViews,
+		}
+		if v.Configuration != nil {
+			d.Views.Styles = v.Configuration.Styles
+		}
+	}
+	d.Documentation = documentationToDesign(w.Documentation)
+	return d
+}
+
+// ImportDecisions loads the architecture decision records stored in dir (see
+// package adr) and adds them to the workspace documentation.
+func (w *Workspace) ImportDecisions(dir string) error {
+	decs, err := adr.Load(dir, w.Model)
+	if err != nil {
+		return err
+	}
+	if w.Documentation == nil {
+		w.Documentation = &Documentation{}
+	}
+	for _, d := range decs {
+		for _, existing := range w.Documentation.Decisions {
+			if existing.ID == d.ID {
+				return fmt.Errorf("decision %q is already defined in the workspace", d.ID)
+			}
+		}
+		w.Documentation.Decisions = append(w.Documentation.Decisions, decisionFromDesign(d))
+	}
+	return nil
+}
+
+// documentationFromDesign returns the Structurizr documentation corresponding
+// to the given design documentation, nil if there is none.
+func documentationFromDesign(doc *mdl.Documentation) *Documentation {
+	if doc == nil {
+		return nil
+	}
+	res := &Documentation{
+		Sections:  make([]*DocumentationSection, len(doc.Sections)),
+		Decisions: make([]*Decision, len(doc.Decisions)),
+	}
+	for i, s := range doc.Sections {
+		res.Sections[i] = &DocumentationSection{
+			Title:     s.Title,
+			Content:   s.Content,
+			Format:    DocFormatKind(s.Format),
+			Order:     s.Order,
+			ElementID: s.ElementID,
+		}
+	}
+	for i, d := range doc.Decisions {
+		res.Decisions[i] = decisionFromDesign(d)
+	}
+	return res
+}
+
+// documentationToDesign returns the design documentation corresponding to
+// the given Structurizr documentation, nil if there is none.
+func documentationToDesign(doc *Documentation) *mdl.Documentation {
+	if doc == nil {
+		return nil
+	}
+	res := &mdl.Documentation{
+		Sections:  make([]*mdl.DocumentationSection, len(doc.Sections)),
+		Decisions: make([]*mdl.Decision, len(doc.Decisions)),
+	}
+	for i, s := range doc.Sections {
+		res.Sections[i] = &mdl.DocumentationSection{
+			Title:     s.Title,
+			Content:   s.Content,
+			Format:    mdl.DocFormatKind(s.Format),
+			Order:     s.Order,
+			ElementID: s.ElementID,
+		}
+	}
+	for i, d := range doc.Decisions {
+		var links []*mdl.DecisionLink
+		for _, l := range d.Links {
+			links = append(links, &mdl.DecisionLink{ID: l.ID, Description: l.Description})
+		}
+		res.Decisions[i] = &mdl.Decision{
+			ID:        d.ID,
+			Date:      d.Date,
+			Status:    mdl.DecisionStatusKind(d.Decision),
+			Title:     d.Title,
+			Content:   d.Content,
+			Format:    mdl.DocFormatKind(d.Format),
+			ElementID: d.ElementID,
+			Links:     links,
+		}
+	}
+	return res
+}
+
+// decisionFromDesign returns the Structurizr decision corresponding to the
+// given design decision.
+func decisionFromDesign(d *mdl.Decision) *Decision {
+	var links []*DecisionLink
+	for _, l := range d.Links {
+		links = append(links, &DecisionLink{ID: l.ID, Description: l.Description})
+	}
+	return &Decision{
+		ID:        d.ID,
+		Date:      d.Date,
+		Decision:  DecisionStatusKind(d.Status),
+		Title:     d.Title,
+		Content:   d.Content,
+		Format:    DocFormatKind(d.Format),
+		ElementID: d.ElementID,
+		Links:     links,
 	}
 }
@@ -0,0 +1,1704 @@
+package stz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"goa.design/model/expr"
+	"goa.design/model/mdl"
+)
+
+type (
+	// dslParser builds a design from the Structurizr DSL representation of a
+	// workspace.
+	dslParser struct {
+		lines []*dslLine
+		pos   int
+		// design is the design being built.
+		design *mdl.Design
+		// elements lists the elements in order of declaration.
+		elements []*dslElement
+		// byID indexes the elements by ID.
+		byID map[string]*dslElement
+		// idents maps DSL identifiers to element IDs.
+		idents map[string]string
+		// rels maps DSL identifiers to relationships.
+		rels map[string]*mdl.Relationship
+		// groups maps deployment group identifiers to group names.
+		groups map[string]string
+		// implied is false if implied relationships are disabled.
+		implied bool
+		// internal lists the IDs of the elements declared in the enterprise
+		// block if any.
+		internal map[string]bool
+		// views counts the views of each type to generate missing keys.
+		views  map[string]int
+		lastID int
+	}
+
+	// dslElement is an element declared in the DSL.
+	dslElement struct {
+		id     string
+		ident  string
+		kind   string
+		value  any
+		parent *dslElement
+		env    string
+	}
+
+	// dslLine is a non-empty line of DSL split into tokens.
+	dslLine struct {
+		num  int
+		toks []dslToken
+	}
+
+	// dslToken is a DSL token.
+	dslToken struct {
+		s      string
+		quoted bool
+	}
+)
+
+// LoadDesign reads the Structurizr workspace stored at path and returns the
+// corresponding design. The workspace may be described with the Structurizr
+// DSL (files with the .dsl extension) or with JSON. The JSON may either be a
+// Structurizr workspace or a design generated by "mdl gen", the latter is
+// loaded as is so that the elements specific to the model DSL (e.g. code views)
+// are preserved.
+func LoadDesign(path string) (*mdl.Design, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+	if strings.EqualFold(filepath.Ext(path), ".dsl") {
+		return ParseDSL(f)
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkspace(b) {
+		var w Workspace
+		if err := json.Unmarshal(b, &w); err != nil {
+			return nil, fmt.Errorf("failed to load workspace: %w", err)
+		}
+		return DesignFromWorkspace(&w), nil
+	}
+	var d mdl.Design
+	if err := json.Unmarshal(b, &d); err != nil {
+		return nil, fmt.Errorf("failed to load design: %w", err)
+	}
+	if d.Model == nil {
+		d.Model = &mdl.Model{}
+	}
+	return &d, nil
+}
+
+// isWorkspace returns true if b is the JSON representation of a Structurizr
+// workspace rather than of a design generated by "mdl gen". Only workspaces
+// have a view configuration (where they store the styles), an ID or revision
+// information.
+func isWorkspace(b []byte) bool {
+	var probe struct {
+		ID               int             `json:"id"`
+		Revision         int             `json:"revision"`
+		LastModifiedDate string          `json:"lastModifiedDate"`
+		Configuration    json.RawMessage `json:"configuration"`
+		Views            struct {
+			Configuration json.RawMessage `json:"configuration"`
+		} `json:"views"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		// Let the caller report the error.
+		return true
+	}
+	return probe.ID != 0 || probe.Revision != 0 || probe.LastModifiedDate != "" ||
+		probe.Configuration != nil || probe.Views.Configuration != nil
+}
+
+// ParseDSL reads the Structurizr DSL representation of a workspace and
+// returns the corresponding design. ParseDSL supports the subset of the
+// language that describes elements, relationships, deployment environments,
+// views and styles. Elements and relationships are given sequential IDs and
+// implied relationships are added unless disabled with
+// "!impliedRelationships false". Features that have no equivalent in the
+// model such as themes, branding, documentation or scripts are ignored.
+func ParseDSL(r io.Reader) (*mdl.Design, error) {
+	lines, err := tokenize(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dslParser{
+		lines:   lines,
+		design:  &mdl.Design{Model: &mdl.Model{}},
+		byID:    make(map[string]*dslElement),
+		idents:  make(map[string]string),
+		rels:    make(map[string]*mdl.Relationship),
+		groups:  make(map[string]string),
+		implied: true,
+		views:   make(map[string]int),
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("missing workspace")
+	}
+	l := p.next()
+	if l.keyword(0) != "workspace" || !l.opens() {
+		return nil, l.errorf("expected workspace block")
+	}
+	args := l.args()[1:]
+	if len(args) > 0 && args[0] == "extends" {
+		return nil, l.errorf("workspace extends is not supported")
+	}
+	if len(args) > 0 {
+		p.design.Name = args[0]
+	}
+	if len(args) > 1 {
+		p.design.Description = args[1]
+	}
+	if err := p.block(p.workspace); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.lines[p.pos].errorf("unexpected content after workspace")
+	}
+	return p.design, nil
+}
+
+// workspace parses a statement of the workspace block.
+func (p *dslParser) workspace(l *dslLine) error {
+	switch l.keyword(0) {
+	case "name":
+		p.design.Name = l.arg(1)
+	case "description":
+		p.design.Description = l.arg(1)
+	case "!identifiers", "!docs", "!adrs":
+	case "!impliedrelationships":
+		p.implied = l.arg(1) != "false"
+	case "model":
+		if err := p.block(p.model(nil, "")); err != nil {
+			return err
+		}
+		p.finalizeModel()
+	case "views":
+		return p.block(p.viewsBlock)
+	case "configuration", "properties":
+		return p.skip(l)
+	default:
+		return l.errorf("unsupported workspace statement %q", l.toks[0].s)
+	}
+	return nil
+}
+
+// model returns the parser for the statements of the model block or of an
+// element block. parent is the element being declared if any and group the
+// name of the enclosing group if any.
+func (p *dslParser) model(parent *dslElement, group string) func(*dslLine) error {
+	return func(l *dslLine) error {
+		ident, l2 := l.assignment()
+		if l2.isRelationship() {
+			return p.relationship(ident, parent, l2)
+		}
+		kw := l2.keyword(0)
+		switch kw {
+		case "enterprise":
+			if parent != nil {
+				return l.errorf("enterprise must appear in model")
+			}
+			p.design.Model.Enterprise = &mdl.Enterprise{Name: l2.arg(1)}
+			if p.internal == nil {
+				p.internal = make(map[string]bool)
+			}
+			return p.block(func(l *dslLine) error {
+				n := len(p.elements)
+				if err := p.model(nil, group)(l); err != nil {
+					return err
+				}
+				for _, e := range p.elements[n:] {
+					p.internal[e.id] = true
+				}
+				return nil
+			})
+		case "group":
+			if !l2.opens() {
+				return l.errorf("expected group block")
+			}
+			return p.block(p.model(parent, l2.arg(1)))
+		case "person", "softwaresystem", "container", "component":
+			return p.element(ident, kw, parent, group, l2)
+		case "deploymentenvironment":
+			if parent != nil {
+				return l.errorf("deploymentEnvironment must appear in model")
+			}
+			env := l2.arg(1)
+			if !l2.opens() {
+				return nil
+			}
+			return p.block(p.deployment(nil, env))
+		case "description", "technology", "tags", "url", "properties", "perspectives", "!docs", "!adrs":
+			if parent == nil {
+				return l.errorf("%s must appear in an element", l2.toks[0].s)
+			}
+			return p.elementProperty(parent, l2)
+		case "!identifiers", "!impliedrelationships":
+			return nil
+		default:
+			return l.errorf("unsupported model statement %q", l2.toks[0].s)
+		}
+	}
+}
+
+// element parses a person, software system, container or component
+// declaration.
+func (p *dslParser) element(ident, kind string, parent *dslElement, group string, l *dslLine) error {
+	args := l.args()[1:]
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	var (
+		e        *dslElement
+		id       = p.newID()
+		tagsArg  string
+		defaults []string
+	)
+	switch kind {
+	case "person":
+		if parent != nil {
+			return l.errorf("person must appear in model")
+		}
+		v := &mdl.Person{ID: id, Name: arg(0), Description: arg(1), Group: group}
+		p.design.Model.People = append(p.design.Model.People, v)
+		e, tagsArg, defaults = &dslElement{value: v}, arg(2), expr.PersonTags
+	case "softwaresystem":
+		if parent != nil {
+			return l.errorf("softwareSystem must appear in model")
+		}
+		v := &mdl.SoftwareSystem{ID: id, Name: arg(0), Description: arg(1), Group: group}
+		p.design.Model.Systems = append(p.design.Model.Systems, v)
+		e, tagsArg, defaults = &dslElement{value: v}, arg(2), expr.SoftwareSystemTags
+	case "container":
+		s, ok := elementValue[*mdl.SoftwareSystem](parent)
+		if !ok {
+			return l.errorf("container must appear in softwareSystem")
+		}
+		v := &mdl.Container{ID: id, Name: arg(0), Description: arg(1), Technology: arg(2), Group: group}
+		s.Containers = append(s.Containers, v)
+		e, tagsArg, defaults = &dslElement{value: v}, arg(3), expr.ContainerTags
+	case "component":
+		c, ok := elementValue[*mdl.Container](parent)
+		if !ok {
+			return l.errorf("component must appear in container")
+		}
+		v := &mdl.Component{ID: id, Name: arg(0), Description: arg(1), Technology: arg(2), Group: group}
+		c.Components = append(c.Components, v)
+		e, tagsArg, defaults = &dslElement{value: v}, arg(3), expr.ComponentTags
+	}
+	e.id, e.kind, e.parent = id, kind, parent
+	p.declare(e, ident)
+	*tagsField(e.value) = joinTags(defaults, tagsArg)
+	if l.opens() {
+		return p.block(p.model(e, ""))
+	}
+	return nil
+}
+
+// elementProperty parses a statement that sets a property of e.
+func (p *dslParser) elementProperty(e *dslElement, l *dslLine) error {
+	switch l.keyword(0) {
+	case "description":
+		switch v := e.value.(type) {
+		case *mdl.Person:
+			v.Description = l.arg(1)
+		case *mdl.SoftwareSystem:
+			v.Description = l.arg(1)
+		case *mdl.Container:
+			v.Description = l.arg(1)
+		case *mdl.Component:
+			v.Description = l.arg(1)
+		case *mdl.DeploymentNode:
+			v.Description = l.arg(1)
+		case *mdl.InfrastructureNode:
+			v.Description = l.arg(1)
+		}
+	case "technology":
+		switch v := e.value.(type) {
+		case *mdl.Container:
+			v.Technology = l.arg(1)
+		case *mdl.Component:
+			v.Technology = l.arg(1)
+		case *mdl.DeploymentNode:
+			v.Technology = l.arg(1)
+		case *mdl.InfrastructureNode:
+			v.Technology = l.arg(1)
+		}
+	case "tags":
+		t := tagsField(e.value)
+		*t = joinTags(strings.Split(*t, ","), strings.Join(l.args()[1:], ","))
+	case "url":
+		if u := urlField(e.value); u != nil {
+			*u = l.arg(1)
+		}
+	case "properties":
+		props := make(map[string]string)
+		if err := p.block(func(l *dslLine) error {
+			props[l.arg(0)] = l.arg(1)
+			return nil
+		}); err != nil {
+			return err
+		}
+		setProperties(e.value, props)
+	case "perspectives", "!docs", "!adrs":
+		return p.skip(l)
+	}
+	return nil
+}
+
+// deployment returns the parser for the statements of a deployment
+// environment or deployment node block.
+func (p *dslParser) deployment(parent *dslElement, env string) func(*dslLine) error {
+	return func(l *dslLine) error {
+		ident, l2 := l.assignment()
+		if l2.isRelationship() {
+			return p.relationship(ident, parent, l2)
+		}
+		args := l2.args()[1:]
+		arg := func(i int) string {
+			if i < len(args) {
+				return args[i]
+			}
+			return ""
+		}
+		switch kw := l2.keyword(0); kw {
+		case "deploymentgroup":
+			if parent != nil {
+				return l.errorf("deploymentGroup must appear in deploymentEnvironment")
+			}
+			if ident != "" {
+				p.groups[ident] = arg(0)
+			}
+			p.groups[arg(0)] = arg(0)
+		case "deploymentnode":
+			instances := arg(4)
+			if instances == "" {
+				instances = "1"
+			}
+			n := &mdl.DeploymentNode{
+				ID:          p.newID(),
+				Name:        arg(0),
+				Description: arg(1),
+				Technology:  arg(2),
+				Environment: env,
+				Instances:   &instances,
+				Tags:        joinTags(expr.DeploymentNodeTags, arg(3)),
+			}
+			if parent == nil {
+				p.design.Model.DeploymentNodes = append(p.design.Model.DeploymentNodes, n)
+			} else {
+				pn := parent.value.(*mdl.DeploymentNode)
+				pn.Children = append(pn.Children, n)
+			}
+			e := &dslElement{id: n.ID, kind: kw, value: n, parent: parent, env: env}
+			p.declare(e, ident)
+			if l2.opens() {
+				return p.block(p.deployment(e, env))
+			}
+		case "infrastructurenode":
+			pn, ok := elementValue[*mdl.DeploymentNode](parent)
+			if !ok {
+				return l.errorf("infrastructureNode must appear in deploymentNode")
+			}
+			n := &mdl.InfrastructureNode{
+				ID:          p.newID(),
+				Name:        arg(0),
+				Description: arg(1),
+				Technology:  arg(2),
+				Environment: env,
+				Tags:        joinTags(expr.InfrastructureNodeTags, arg(3)),
+			}
+			pn.InfrastructureNodes = append(pn.InfrastructureNodes, n)
+			e := &dslElement{id: n.ID, kind: kw, value: n, parent: parent, env: env}
+			p.declare(e, ident)
+			if l2.opens() {
+				return p.block(p.deployment(e, env))
+			}
+		case "containerinstance", "softwaresysteminstance":
+			pn, ok := elementValue[*mdl.DeploymentNode](parent)
+			if !ok {
+				return l.errorf("%s must appear in deploymentNode", l2.toks[0].s)
+			}
+			target, err := p.lookup(l, arg(0))
+			if err != nil {
+				return err
+			}
+			var groups []string
+			for _, g := range strings.Split(arg(1), ",") {
+				if g = strings.TrimSpace(g); g == "" {
+					continue
+				}
+				name, ok := p.groups[g]
+				if !ok {
+					return l.errorf("unknown deployment group %q", g)
+				}
+				groups = append(groups, name)
+			}
+			e := &dslElement{id: p.newID(), kind: kw, parent: parent, env: env}
+			if kw == "containerinstance" {
+				if target.kind != "container" {
+					return l.errorf("%q is not a container", arg(0))
+				}
+				ci := &mdl.ContainerInstance{
+					ID:               e.id,
+					ContainerID:      target.id,
+					InstanceID:       p.instanceID(env, target.id),
+					Environment:      env,
+					DeploymentGroups: groups,
+					Tags:             joinTags(append([]string{"Element"}, expr.ContainerInstanceTags...), arg(2)),
+				}
+				pn.ContainerInstances = append(pn.ContainerInstances, ci)
+				e.value = ci
+			} else {
+				if target.kind != "softwaresystem" {
+					return l.errorf("%q is not a software system", arg(0))
+				}
+				si := &mdl.SoftwareSystemInstance{
+					ID:               e.id,
+					SoftwareSystemID: target.id,
+					InstanceID:       p.instanceID(env, target.id),
+					Environment:      env,
+					DeploymentGroups: groups,
+					Tags:             joinTags(append([]string{"Element"}, expr.SoftwareSystemInstanceTags...), arg(2)),
+				}
+				pn.SoftwareSystemInstances = append(pn.SoftwareSystemInstances, si)
+				e.value = si
+			}
+			p.declare(e, ident)
+			if l2.opens() {
+				return p.block(p.deployment(e, env))
+			}
+		case "healthcheck":
+			if parent == nil {
+				return l.errorf("healthCheck must appear in containerInstance or softwareSystemInstance")
+			}
+			hc := &mdl.HealthCheck{Name: arg(0), URL: arg(1), Interval: 60, Timeout: 0}
+			if arg(2) != "" {
+				hc.Interval, _ = strconv.Atoi(arg(2)) // nolint: errcheck
+			}
+			if arg(3) != "" {
+				hc.Timeout, _ = strconv.Atoi(arg(3)) // nolint: errcheck
+			}
+			switch v := parent.value.(type) {
+			case *mdl.ContainerInstance:
+				v.HealthChecks = append(v.HealthChecks, hc)
+			case *mdl.SoftwareSystemInstance:
+				v.HealthChecks = append(v.HealthChecks, hc)
+			default:
+				return l.errorf("healthCheck must appear in containerInstance or softwareSystemInstance")
+			}
+		case "instances":
+			if n, ok := elementValue[*mdl.DeploymentNode](parent); ok {
+				v := arg(0)
+				n.Instances = &v
+			}
+		case "description", "technology", "tags", "url", "properties", "perspectives":
+			if parent == nil {
+				return l.errorf("%s must appear in an element", l2.toks[0].s)
+			}
+			return p.elementProperty(parent, l2)
+		default:
+			return l.errorf("unsupported deployment statement %q", l2.toks[0].s)
+		}
+		return nil
+	}
+}
+
+// relationship parses a relationship declaration. src is the element whose
+// block contains the declaration if any.
+func (p *dslParser) relationship(ident string, src *dslElement, l *dslLine) error {
+	args := l.args()
+	if args[0] == "->" {
+		if src == nil {
+			return l.errorf("missing relationship source")
+		}
+		args = args[1:]
+	} else {
+		if args[0] != "this" || src == nil {
+			s, err := p.lookup(l, args[0])
+			if err != nil {
+				return err
+			}
+			src = s
+		}
+		args = args[2:]
+	}
+	if len(args) == 0 {
+		return l.errorf("missing relationship destination")
+	}
+	dest, err := p.lookup(l, args[0])
+	if err != nil {
+		return err
+	}
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	r := &mdl.Relationship{
+		ID:            p.newID(),
+		SourceID:      src.id,
+		DestinationID: dest.id,
+		Description:   arg(1),
+		Technology:    arg(2),
+		Tags:          joinTags([]string{"Relationship"}, arg(3)),
+	}
+	if l.opens() {
+		if err := p.block(func(l *dslLine) error {
+			switch l.keyword(0) {
+			case "description":
+				r.Description = l.arg(1)
+			case "technology":
+				r.Technology = l.arg(1)
+			case "tags":
+				r.Tags = joinTags(strings.Split(r.Tags, ","), strings.Join(l.args()[1:], ","))
+			case "url":
+				r.URL = l.arg(1)
+			case "properties", "perspectives":
+				return p.skip(l)
+			default:
+				return l.errorf("unsupported relationship statement %q", l.toks[0].s)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	// Structurizr relationships are synchronous unless tagged otherwise.
+	r.InteractionStyle = mdl.InteractionSynchronous
+	for _, t := range strings.Split(r.Tags, ",") {
+		if t == "Asynchronous" {
+			r.InteractionStyle = mdl.InteractionAsynchronous
+		}
+	}
+	if r.InteractionStyle == mdl.InteractionSynchronous {
+		r.Tags = joinTags([]string{"Relationship", "Synchronous"}, r.Tags)
+	}
+	addRelationship(src.value, r)
+	if ident != "" {
+		p.rels[ident] = r
+	}
+	return nil
+}
+
+// finalizeModel sets the location of the people and software systems if the
+// model defines an enterprise and adds the implied relationships and the relationships
+// between container and software system instances.
+func (p *dslParser) finalizeModel() {
+	if p.internal != nil {
+		for _, e := range p.elements {
+			loc := mdl.LocationExternal
+			if p.internal[e.id] {
+				loc = mdl.LocationInternal
+			}
+			switch v := e.value.(type) {
+			case *mdl.Person:
+				v.Location = loc
+			case *mdl.SoftwareSystem:
+				v.Location = loc
+			}
+		}
+	}
+	if p.implied {
+		p.addImpliedRelationships()
+	}
+	p.addInstanceRelationships()
+}
+
+// addImpliedRelationships adds the relationships between the parents of the
+// source and destination of each relationship unless there is already a
+// relationship between them.
+func (p *dslParser) addImpliedRelationships() {
+	for _, r := range p.modelRelationships() {
+		src, dest := p.byID[r.SourceID], p.byID[r.DestinationID]
+		for s := src; s != nil; s = s.parent {
+			for d := dest; d != nil; d = d.parent {
+				if s == src && d == dest || s == d || s.contains(d) || d.contains(s) {
+					continue
+				}
+				if p.related(s.id, d.id) {
+					continue
+				}
+				addRelationship(s.value, &mdl.Relationship{
+					ID:               p.newID(),
+					SourceID:         s.id,
+					DestinationID:    d.id,
+					Description:      r.Description,
+					Technology:       r.Technology,
+					InteractionStyle: mdl.InteractionSynchronous,
+					Tags:             "Relationship,Synchronous",
+				})
+			}
+		}
+	}
+}
+
+// addInstanceRelationships adds the relationships between the container and
+// software system instances of the same deployment environment that share a
+// deployment group, derived from the relationships between the corresponding
+// containers and software systems.
+func (p *dslParser) addInstanceRelationships() {
+	var instances []*dslElement
+	for _, e := range p.elements {
+		if e.kind == "containerinstance" || e.kind == "softwaresysteminstance" {
+			instances = append(instances, e)
+		}
+	}
+	for _, src := range instances {
+		srcID, srcGroups := instanceOf(src.value)
+		for _, r := range relationships(p.byID[srcID].value) {
+			for _, dest := range instances {
+				destID, destGroups := instanceOf(dest.value)
+				if destID != r.DestinationID || dest.env != src.env || !shareGroup(srcGroups, destGroups) {
+					continue
+				}
+				addRelationship(src.value, &mdl.Relationship{
+					ID:                   p.newID(),
+					SourceID:             src.id,
+					DestinationID:        dest.id,
+					Description:          r.Description,
+					Technology:           r.Technology,
+					InteractionStyle:     r.InteractionStyle,
+					Tags:                 r.Tags,
+					LinkedRelationshipID: r.ID,
+				})
+			}
+		}
+	}
+}
+
+// viewsBlock parses a statement of the views block.
+func (p *dslParser) viewsBlock(l *dslLine) error {
+	d := p.design
+	if d.Views == nil {
+		d.Views = &mdl.Views{}
+	}
+	args := l.args()[1:]
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	vp := func(typ string, keyIdx int) *mdl.ViewProps {
+		key := arg(keyIdx)
+		if key == "" {
+			p.views[typ]++
+			key = fmt.Sprintf("%s-%03d", typ, p.views[typ])
+		}
+		return &mdl.ViewProps{Key: key, Description: arg(keyIdx + 1)}
+	}
+	scope := func() (*dslElement, error) {
+		return p.lookup(l, arg(0))
+	}
+	switch l.keyword(0) {
+	case "systemlandscape":
+		v := &mdl.LandscapeView{ViewProps: vp("SystemLandscape", 0)}
+		d.Views.LandscapeViews = append(d.Views.LandscapeViews, v)
+		return p.view(l, v.ViewProps, "systemlandscape", nil, "")
+	case "systemcontext":
+		s, err := scope()
+		if err != nil {
+			return err
+		}
+		v := &mdl.ContextView{SoftwareSystemID: s.id, ViewProps: vp("SystemContext", 1)}
+		d.Views.ContextViews = append(d.Views.ContextViews, v)
+		return p.view(l, v.ViewProps, "systemcontext", s, "")
+	case "container":
+		s, err := scope()
+		if err != nil {
+			return err
+		}
+		v := &mdl.ContainerView{SoftwareSystemID: s.id, ViewProps: vp("Container", 1)}
+		d.Views.ContainerViews = append(d.Views.ContainerViews, v)
+		return p.view(l, v.ViewProps, "container", s, "")
+	case "component":
+		c, err := scope()
+		if err != nil {
+			return err
+		}
+		v := &mdl.ComponentView{ContainerID: c.id, ViewProps: vp("Component", 1)}
+		d.Views.ComponentViews = append(d.Views.ComponentViews, v)
+		return p.view(l, v.ViewProps, "component", c, "")
+	case "dynamic":
+		v := &mdl.DynamicView{ViewProps: vp("Dynamic", 1)}
+		if arg(0) != "*" {
+			s, err := scope()
+			if err != nil {
+				return err
+			}
+			v.ElementID = s.id
+		}
+		d.Views.DynamicViews = append(d.Views.DynamicViews, v)
+		return p.dynamicView(l, v.ViewProps)
+	case "deployment":
+		v := &mdl.DeploymentView{Environment: arg(1), ViewProps: vp("Deployment", 2)}
+		var s *dslElement
+		if arg(0) != "*" {
+			var err error
+			if s, err = scope(); err != nil {
+				return err
+			}
+			v.SoftwareSystemID = s.id
+		}
+		d.Views.DeploymentViews = append(d.Views.DeploymentViews, v)
+		return p.view(l, v.ViewProps, "deployment", s, v.Environment)
+	case "filtered":
+		mode := "Include"
+		if strings.EqualFold(arg(1), "exclude") {
+			mode = "Exclude"
+		}
+		fv := &mdl.FilteredView{BaseKey: arg(0), Mode: mode, Key: arg(3), Description: arg(4)}
+		for _, t := range strings.Split(arg(2), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				fv.Tags = append(fv.Tags, t)
+			}
+		}
+		if fv.Key == "" {
+			p.views["Filtered"]++
+			fv.Key = fmt.Sprintf("Filtered-%03d", p.views["Filtered"])
+		}
+		d.Views.FilteredViews = append(d.Views.FilteredViews, fv)
+		return p.skip(l)
+	case "styles":
+		if d.Views.Styles == nil {
+			d.Views.Styles = &mdl.Styles{}
+		}
+		return p.block(p.styles)
+	case "theme", "themes", "branding", "terminology", "properties", "configuration", "!docs", "!adrs":
+		return p.skip(l)
+	default:
+		return l.errorf("unsupported views statement %q", l.toks[0].s)
+	}
+}
+
+// view parses the block of a static or deployment view. scope is the element
+// the view is about if any and env the deployment environment of deployment
+// views.
+func (p *dslParser) view(l *dslLine, vp *mdl.ViewProps, typ string, scope *dslElement, env string) error {
+	if !l.opens() {
+		return nil
+	}
+	var (
+		included    []string
+		excluded    = make(map[string]bool)
+		excludedRel = make(map[string]bool)
+		explicitRel []*mdl.Relationship
+	)
+	err := p.block(func(l *dslLine) error {
+		switch l.keyword(0) {
+		case "include", "exclude":
+			include := l.keyword(0) == "include"
+			args := l.args()[1:]
+			if len(args) == 3 && args[1] == "->" {
+				rels, err := p.matchRelationships(l, args[0], args[2])
+				if err != nil {
+					return err
+				}
+				for _, r := range rels {
+					if include {
+						explicitRel = append(explicitRel, r)
+						included = append(included, r.SourceID, r.DestinationID)
+					} else {
+						excludedRel[r.ID] = true
+					}
+				}
+				return nil
+			}
+			for _, a := range args {
+				if a == "*" {
+					if include {
+						included = append(included, p.defaultElements(typ, scope, env)...)
+					}
+					continue
+				}
+				if r, ok := p.rels[a]; ok {
+					if include {
+						explicitRel = append(explicitRel, r)
+						included = append(included, r.SourceID, r.DestinationID)
+					} else {
+						excludedRel[r.ID] = true
+					}
+					continue
+				}
+				e, err := p.lookup(l, a)
+				if err != nil {
+					return err
+				}
+				ids := []string{e.id}
+				if typ == "deployment" && e.kind == "deploymentnode" {
+					ids = append(ids, p.descendants(e)...)
+				}
+				if include {
+					included = append(included, ids...)
+				} else {
+					for _, id := range ids {
+						excluded[id] = true
+					}
+				}
+			}
+		case "autolayout":
+			vp.AutoLayout = autoLayout(l)
+		case "title":
+			vp.Title = l.arg(1)
+		case "description":
+			vp.Description = l.arg(1)
+		case "animation":
+			return p.block(func(l *dslLine) error {
+				step := &mdl.AnimationStep{Order: len(vp.Animations) + 1}
+				for _, a := range l.args() {
+					e, err := p.lookup(l, a)
+					if err != nil {
+						return err
+					}
+					step.Elements = append(step.Elements, e.id)
+				}
+				vp.Animations = append(vp.Animations, step)
+				return nil
+			})
+		case "default":
+		case "properties":
+			return p.skip(l)
+		default:
+			return l.errorf("unsupported view statement %q", l.toks[0].s)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	inView := make(map[string]bool)
+	for _, id := range included {
+		if inView[id] || excluded[id] {
+			continue
+		}
+		inView[id] = true
+		vp.ElementViews = append(vp.ElementViews, &mdl.ElementView{ID: id})
+	}
+	added := make(map[string]bool)
+	addRel := func(r *mdl.Relationship) {
+		if added[r.ID] || excludedRel[r.ID] || !inView[r.SourceID] || !inView[r.DestinationID] {
+			return
+		}
+		added[r.ID] = true
+		vp.RelationshipViews = append(vp.RelationshipViews, &mdl.RelationshipView{ID: r.ID})
+	}
+	for _, r := range explicitRel {
+		addRel(r)
+	}
+	for _, e := range p.elements {
+		if !inView[e.id] {
+			continue
+		}
+		for _, r := range relationships(e.value) {
+			if typ != "deployment" && r.LinkedRelationshipID != "" {
+				continue
+			}
+			addRel(r)
+		}
+	}
+	return nil
+}
+
+// dynamicView parses the block of a dynamic view.
+func (p *dslParser) dynamicView(l *dslLine, vp *mdl.ViewProps) error {
+	if !l.opens() {
+		return nil
+	}
+	order := 0
+	inView := make(map[string]bool)
+	var steps func(l *dslLine) error
+	steps = func(l *dslLine) error {
+		if len(l.toks) == 1 && l.opens() {
+			// Parallel sequence
+			return p.block(steps)
+		}
+		switch l.keyword(0) {
+		case "autolayout":
+			vp.AutoLayout = autoLayout(l)
+			return nil
+		case "title":
+			vp.Title = l.arg(1)
+			return nil
+		case "description":
+			vp.Description = l.arg(1)
+			return nil
+		case "default":
+			return nil
+		case "properties":
+			return p.skip(l)
+		}
+		args := l.args()
+		var (
+			r    *mdl.Relationship
+			desc string
+		)
+		if len(args) >= 3 && args[1] == "->" {
+			var want string
+			if len(args) > 3 {
+				want = args[3]
+			}
+			rels, err := p.matchRelationships(l, args[0], args[2])
+			if err != nil {
+				return err
+			}
+			for _, rel := range rels {
+				if r == nil || want != "" && rel.Description == want && r.Description != want {
+					r = rel
+				}
+			}
+			if r == nil {
+				return l.errorf("no relationship between %q and %q", args[0], args[2])
+			}
+			if len(args) > 3 {
+				desc = args[3]
+			}
+		} else if rel, ok := p.rels[args[0]]; ok {
+			r = rel
+			if len(args) > 1 {
+				desc = args[1]
+			}
+		} else {
+			return l.errorf("unsupported dynamic view statement %q", l.toks[0].s)
+		}
+		order++
+		if desc == "" {
+			desc = r.Description
+		}
+		for _, id := range []string{r.SourceID, r.DestinationID} {
+			if !inView[id] {
+				inView[id] = true
+				vp.ElementViews = append(vp.ElementViews, &mdl.ElementView{ID: id})
+			}
+		}
+		vp.RelationshipViews = append(vp.RelationshipViews, &mdl.RelationshipView{
+			ID:          r.ID,
+			Description: desc,
+			Order:       strconv.Itoa(order),
+		})
+		return nil
+	}
+	return p.block(steps)
+}
+
+// styles parses a statement of the styles block.
+func (p *dslParser) styles(l *dslLine) error {
+	s := p.design.Views.Styles
+	switch l.keyword(0) {
+	case "element":
+		es := &mdl.ElementStyle{Tag: l.arg(1)}
+		s.Elements = append(s.Elements, es)
+		return p.block(func(l *dslLine) error {
+			v := l.arg(1)
+			switch l.keyword(0) {
+			case "shape":
+				es.Shape = mdl.ShapeKind(enumValue(v, func(i int) string { return enumString(mdl.ShapeKind(i)) }))
+			case "icon":
+				es.Icon = v
+			case "width":
+				es.Width = intValue(v)
+			case "height":
+				es.Height = intValue(v)
+			case "background":
+				es.Background = v
+			case "color", "colour":
+				es.Color = v
+			case "stroke":
+				es.Stroke = v
+			case "fontsize":
+				es.FontSize = intValue(v)
+			case "border":
+				es.Border = mdl.BorderKind(enumValue(v, func(i int) string { return enumString(mdl.BorderKind(i)) }))
+			case "opacity":
+				es.Opacity = intValue(v)
+			case "metadata":
+				b := v != "false"
+				es.Metadata = &b
+			case "description":
+				b := v != "false"
+				es.Description = &b
+			case "properties":
+				return p.skip(l)
+			}
+			return nil
+		})
+	case "relationship":
+		rs := &mdl.RelationshipStyle{Tag: l.arg(1)}
+		s.Relationships = append(s.Relationships, rs)
+		return p.block(func(l *dslLine) error {
+			v := l.arg(1)
+			switch l.keyword(0) {
+			case "thickness":
+				rs.Thickness = intValue(v)
+			case "color", "colour":
+				rs.Color = v
+			case "fontsize":
+				rs.FontSize = intValue(v)
+			case "width":
+				rs.Width = intValue(v)
+			case "style":
+				b := !strings.EqualFold(v, "solid")
+				rs.Dashed = &b
+			case "routing":
+				rs.Routing = mdl.RoutingKind(enumValue(v, func(i int) string { return enumString(mdl.RoutingKind(i)) }))
+			case "position":
+				rs.Position = intValue(v)
+			case "opacity":
+				rs.Opacity = intValue(v)
+			case "properties":
+				return p.skip(l)
+			}
+			return nil
+		})
+	case "theme", "themes", "branding", "terminology", "properties":
+		return p.skip(l)
+	default:
+		return l.errorf("unsupported styles statement %q", l.toks[0].s)
+	}
+}
+
+// defaultElements returns the IDs of the elements included by "include *" in
+// a view of the given type.
+func (p *dslParser) defaultElements(typ string, scope *dslElement, env string) []string {
+	var res []string
+	switch typ {
+	case "systemlandscape":
+		for _, e := range p.elements {
+			if e.kind == "person" || e.kind == "softwaresystem" {
+				res = append(res, e.id)
+			}
+		}
+	case "systemcontext":
+		res = append(res, scope.id)
+		res = append(res, p.connected([]string{scope.id}, "person", "softwaresystem")...)
+	case "container", "component":
+		var children []string
+		for _, e := range p.elements {
+			if e.parent == scope {
+				children = append(children, e.id)
+			}
+		}
+		res = append(res, children...)
+		kinds := []string{"person", "softwaresystem"}
+		if typ == "component" {
+			kinds = append(kinds, "container")
+		}
+		res = append(res, p.connected(children, kinds...)...)
+	case "deployment":
+		for _, e := range p.elements {
+			if e.env != env {
+				continue
+			}
+			switch e.kind {
+			case "containerinstance":
+				if scope != nil && p.byID[e.value.(*mdl.ContainerInstance).ContainerID].parent != scope {
+					continue
+				}
+			case "softwaresysteminstance":
+				if scope != nil && e.value.(*mdl.SoftwareSystemInstance).SoftwareSystemID == scope.id {
+					continue
+				}
+			case "deploymentnode":
+				continue
+			}
+			res = append(res, e.id)
+			for a := e.parent; a != nil; a = a.parent {
+				res = append(res, a.id)
+			}
+		}
+	}
+	return res
+}
+
+// connected returns the IDs of the elements of the given kinds that have a
+// relationship with one of the elements with the given IDs.
+func (p *dslParser) connected(ids []string, kinds ...string) []string {
+	set := make(map[string]bool)
+	for _, id := range ids {
+		set[id] = true
+	}
+	var res []string
+	seen := make(map[string]bool)
+	for _, r := range p.modelRelationships() {
+		var other string
+		switch {
+		case set[r.SourceID]:
+			other = r.DestinationID
+		case set[r.DestinationID]:
+			other = r.SourceID
+		default:
+			continue
+		}
+		if set[other] || seen[other] {
+			continue
+		}
+		e := p.byID[other]
+		for _, k := range kinds {
+			if e.kind == k {
+				seen[other] = true
+				res = append(res, other)
+			}
+		}
+	}
+	return res
+}
+
+// matchRelationships returns the relationships from src to dest where src
+// and dest are element identifiers or "*".
+func (p *dslParser) matchRelationships(l *dslLine, src, dest string) ([]*mdl.Relationship, error) {
+	var srcID, destID string
+	if src != "*" {
+		e, err := p.lookup(l, src)
+		if err != nil {
+			return nil, err
+		}
+		srcID = e.id
+	}
+	if dest != "*" {
+		e, err := p.lookup(l, dest)
+		if err != nil {
+			return nil, err
+		}
+		destID = e.id
+	}
+	var res []*mdl.Relationship
+	for _, e := range p.elements {
+		for _, r := range relationships(e.value) {
+			if (srcID == "" || r.SourceID == srcID) && (destID == "" || r.DestinationID == destID) {
+				res = append(res, r)
+			}
+		}
+	}
+	return res, nil
+}
+
+// modelRelationships returns the relationships between elements that are not
+// deployment elements.
+func (p *dslParser) modelRelationships() []*mdl.Relationship {
+	var res []*mdl.Relationship
+	for _, e := range p.elements {
+		if e.env != "" {
+			continue
+		}
+		res = append(res, relationships(e.value)...)
+	}
+	return res
+}
+
+// related returns true if there is a relationship from the element with ID
+// src to the element with ID dest.
+func (p *dslParser) related(src, dest string) bool {
+	for _, r := range relationships(p.byID[src].value) {
+		if r.DestinationID == dest {
+			return true
+		}
+	}
+	return false
+}
+
+// descendants returns the IDs of the elements contained in e.
+func (p *dslParser) descendants(e *dslElement) []string {
+	var res []string
+	for _, c := range p.elements {
+		if c != e && e.contains(c) {
+			res = append(res, c.id)
+		}
+	}
+	return res
+}
+
+// instanceID returns the instance ID of a new instance of the element with
+// the given ID deployed in env. Instances are numbered per deployment
+// environment as done by Structurizr.
+func (p *dslParser) instanceID(env, id string) int {
+	res := 1
+	for _, e := range p.elements {
+		if target, _ := instanceOf(e.value); e.env == env && target == id {
+			res++
+		}
+	}
+	return res
+}
+
+// declare records e and associates it with the given identifier if any.
+func (p *dslParser) declare(e *dslElement, ident string) {
+	p.elements = append(p.elements, e)
+	p.byID[e.id] = e
+	if ident == "" {
+		return
+	}
+	e.ident = ident
+	p.idents[ident] = e.id
+	if e.parent != nil && e.parent.ident != "" {
+		// Support hierarchical identifiers.
+		p.idents[e.parent.ident+"."+ident] = e.id
+		e.ident = e.parent.ident + "." + ident
+	}
+}
+
+// lookup returns the element with the given identifier.
+func (p *dslParser) lookup(l *dslLine, ident string) (*dslElement, error) {
+	if id, ok := p.idents[ident]; ok {
+		return p.byID[id], nil
+	}
+	return nil, l.errorf("unknown identifier %q", ident)
+}
+
+// newID returns a new element or relationship ID.
+func (p *dslParser) newID() string {
+	p.lastID++
+	return strconv.Itoa(p.lastID)
+}
+
+// next returns the next line.
+func (p *dslParser) next() *dslLine {
+	l := p.lines[p.pos]
+	p.pos++
+	return l
+}
+
+// block calls fn for each statement of the block that starts at the current
+// line up to the closing brace. fn must parse the nested blocks of the
+// statements it is given.
+func (p *dslParser) block(fn func(*dslLine) error) error {
+	for p.pos < len(p.lines) {
+		l := p.next()
+		if len(l.toks) == 1 && l.toks[0].s == "}" && !l.toks[0].quoted {
+			return nil
+		}
+		if err := fn(l); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("unexpected end of file: missing closing brace")
+}
+
+// skip skips the block opened by l if any.
+func (p *dslParser) skip(l *dslLine) error {
+	if !l.opens() {
+		return nil
+	}
+	return p.block(func(l *dslLine) error { return p.skip(l) })
+}
+
+// contains returns true if e is an ancestor of other.
+func (e *dslElement) contains(other *dslElement) bool {
+	for a := other.parent; a != nil; a = a.parent {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
+
+// keyword returns the lower case i-th token of l if it is not quoted.
+func (l *dslLine) keyword(i int) string {
+	if i >= len(l.toks) || l.toks[i].quoted {
+		return ""
+	}
+	return strings.ToLower(l.toks[i].s)
+}
+
+// arg returns the i-th token of l, the empty string if there is none.
+func (l *dslLine) arg(i int) string {
+	args := l.args()
+	if i >= len(args) {
+		return ""
+	}
+	return args[i]
+}
+
+// args returns the tokens of l excluding the trailing opening brace.
+func (l *dslLine) args() []string {
+	toks := l.toks
+	if l.opens() {
+		toks = toks[:len(toks)-1]
+	}
+	res := make([]string, len(toks))
+	for i, t := range toks {
+		res[i] = t.s
+	}
+	return res
+}
+
+// opens returns true if l ends with an opening brace.
+func (l *dslLine) opens() bool {
+	last := l.toks[len(l.toks)-1]
+	return last.s == "{" && !last.quoted
+}
+
+// assignment returns the identifier assigned by l if any and the line
+// without the assignment.
+func (l *dslLine) assignment() (string, *dslLine) {
+	if len(l.toks) > 2 && l.toks[1].s == "=" && !l.toks[1].quoted {
+		return l.toks[0].s, &dslLine{num: l.num, toks: l.toks[2:]}
+	}
+	return "", l
+}
+
+// isRelationship returns true if l declares a relationship.
+func (l *dslLine) isRelationship() bool {
+	for i, t := range l.toks {
+		if i > 1 {
+			break
+		}
+		if t.s == "->" && !t.quoted {
+			return true
+		}
+	}
+	return false
+}
+
+// errorf returns an error that includes the line number.
+func (l *dslLine) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", l.num, fmt.Sprintf(format, args...))
+}
+
+// tokenize splits the DSL read from r into lines of tokens. Comments and
+// empty lines are skipped and lines ending with a backslash are joined with
+// the next line. Empty blocks ("{ }" or "{}") at the end of a line are
+// removed.
+func tokenize(r io.Reader) ([]*dslLine, error) {
+	var (
+		res     []*dslLine
+		comment bool
+		pending string
+		start   int
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	num := 0
+	for sc.Scan() {
+		num++
+		text := strings.TrimSpace(sc.Text())
+		if comment {
+			if i := strings.Index(text, "*/"); i >= 0 {
+				comment = false
+				text = strings.TrimSpace(text[i+2:])
+			} else {
+				continue
+			}
+		}
+		if strings.HasPrefix(text, "/*") {
+			if i := strings.Index(text, "*/"); i < 0 {
+				comment = true
+				continue
+			} else {
+				text = strings.TrimSpace(text[i+2:])
+			}
+		}
+		if strings.HasPrefix(text, "#") || strings.HasPrefix(text, "//") {
+			continue
+		}
+		if strings.HasSuffix(text, "\\") {
+			if pending == "" {
+				start = num
+			}
+			pending += strings.TrimSuffix(text, "\\") + " "
+			continue
+		}
+		lnum := num
+		if pending != "" {
+			text, pending, lnum = pending+text, "", start
+		}
+		if text == "" {
+			continue
+		}
+		toks, err := splitTokens(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lnum, err)
+		}
+		if n := len(toks); n > 1 && toks[n-1].s == "}" && toks[n-2].s == "{" && !toks[n-1].quoted && !toks[n-2].quoted {
+			toks = toks[:n-2]
+		} else if n := len(toks); n > 0 && toks[n-1].s == "{}" && !toks[n-1].quoted {
+			toks = toks[:n-1]
+		}
+		if len(toks) > 0 {
+			res = append(res, &dslLine{num: lnum, toks: toks})
+		}
+	}
+	return res, sc.Err()
+}
+
+// splitTokens splits a line into whitespace separated tokens. Tokens may be
+// enclosed in double quotes in which case they may contain whitespace and
+// escaped double quotes.
+func splitTokens(text string) ([]dslToken, error) {
+	var toks []dslToken
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(text) {
+				if text[i] == '\\' && i+1 < len(text) && (text[i+1] == '"' || text[i+1] == '\\') {
+					b.WriteByte(text[i+1])
+					i += 2
+					continue
+				}
+				if text[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteByte(text[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, dslToken{s: b.String(), quoted: true})
+		default:
+			j := i
+			for j < len(text) && text[j] != ' ' && text[j] != '\t' {
+				j++
+			}
+			toks = append(toks, dslToken{s: text[i:j]})
+			i = j
+		}
+	}
+	return toks, nil
+}
+
+// autoLayout returns the automatic layout described by l.
+func autoLayout(l *dslLine) *mdl.AutoLayout {
+	al := &mdl.AutoLayout{RankDirection: mdl.RankTopBottom}
+	args := l.args()[1:]
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "bt":
+			al.RankDirection = mdl.RankBottomTop
+		case "lr":
+			al.RankDirection = mdl.RankLeftRight
+		case "rl":
+			al.RankDirection = mdl.RankRightLeft
+		}
+	}
+	if len(args) > 1 {
+		al.RankSep = intValue(args[1])
+	}
+	if len(args) > 2 {
+		al.NodeSep = intValue(args[2])
+	}
+	return al
+}
+
+// joinTags returns the comma separated list made of defaults followed by the
+// tags listed in the comma separated list tags that are not in defaults.
+func joinTags(defaults []string, tags string) string {
+	res := append([]string{}, defaults...)
+	for _, t := range strings.Split(tags, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		found := false
+		for _, d := range res {
+			if d == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, t)
+		}
+	}
+	return strings.Join(res, ",")
+}
+
+// enumValue returns the value of the enum whose string representation (as
+// returned by name) matches s ignoring case and underscores, 0 if none does.
+func enumValue(s string, name func(int) string) int {
+	norm := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, "_", "")) }
+	for i := 1; ; i++ {
+		n := name(i)
+		if n == "" {
+			return 0
+		}
+		if norm(n) == norm(s) {
+			return i
+		}
+	}
+}
+
+// intValue returns a pointer to the integer represented by s, nil if s is not
+// an integer.
+func intValue(s string) *int {
+	i, err := strconv.Atoi(strings.TrimSuffix(s, "px"))
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// elementValue returns the mdl value of e if it has type T.
+func elementValue[T any](e *dslElement) (T, bool) {
+	var zero T
+	if e == nil {
+		return zero, false
+	}
+	v, ok := e.value.(T)
+	return v, ok
+}
+
+// instanceOf returns the ID of the element instantiated by a container or
+// software system instance and the instance deployment groups.
+func instanceOf(v any) (string, []string) {
+	switch i := v.(type) {
+	case *mdl.ContainerInstance:
+		return i.ContainerID, i.DeploymentGroups
+	case *mdl.SoftwareSystemInstance:
+		return i.SoftwareSystemID, i.DeploymentGroups
+	}
+	return "", nil
+}
+
+// shareGroup returns true if the two lists of deployment groups have a group
+// in common or are both empty.
+func shareGroup(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	for _, g := range a {
+		for _, g2 := range b {
+			if g == g2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagsField returns a pointer to the tags of the element v.
+func tagsField(v any) *string {
+	switch e := v.(type) {
+	case *mdl.Person:
+		return &e.Tags
+	case *mdl.SoftwareSystem:
+		return &e.Tags
+	case *mdl.Container:
+		return &e.Tags
+	case *mdl.Component:
+		return &e.Tags
+	case *mdl.DeploymentNode:
+		return &e.Tags
+	case *mdl.InfrastructureNode:
+		return &e.Tags
+	case *mdl.ContainerInstance:
+		return &e.Tags
+	case *mdl.SoftwareSystemInstance:
+		return &e.Tags
+	}
+	return new(string)
+}
+
+// urlField returns a pointer to the URL of the element v.
+func urlField(v any) *string {
+	switch e := v.(type) {
+	case *mdl.Person:
+		return &e.URL
+	case *mdl.SoftwareSystem:
+		return &e.URL
+	case *mdl.Container:
+		return &e.URL
+	case *mdl.Component:
+		return &e.URL
+	case *mdl.DeploymentNode:
+		return &e.URL
+	case *mdl.InfrastructureNode:
+		return &e.URL
+	case *mdl.ContainerInstance:
+		return &e.URL
+	case *mdl.SoftwareSystemInstance:
+		return &e.URL
+	}
+	return nil
+}
+
+// setProperties sets the properties of the element v.
+func setProperties(v any, props map[string]string) {
+	switch e := v.(type) {
+	case *mdl.Person:
+		e.Properties = props
+	case *mdl.SoftwareSystem:
+		e.Properties = props
+	case *mdl.Container:
+		e.Properties = props
+	case *mdl.Component:
+		e.Properties = props
+	case *mdl.DeploymentNode:
+		e.Properties = props
+	case *mdl.InfrastructureNode:
+		e.Properties = props
+	case *mdl.ContainerInstance:
+		e.Properties = props
+	case *mdl.SoftwareSystemInstance:
+		e.Properties = props
+	}
+}
+
+// relationships returns the relationships of the element v.
+func relationships(v any) []*mdl.Relationship {
+	switch e := v.(type) {
+	case *mdl.Person:
+		return e.Relationships
+	case *mdl.SoftwareSystem:
+		return e.Relationships
+	case *mdl.Container:
+		return e.Relationships
+	case *mdl.Component:
+		return e.Relationships
+	case *mdl.DeploymentNode:
+		return e.Relationships
+	case *mdl.InfrastructureNode:
+		return e.Relationships
+	case *mdl.ContainerInstance:
+		return e.Relationships
+	case *mdl.SoftwareSystemInstance:
+		return e.Relationships
+	}
+	return nil
+}
+
+// addRelationship adds r to the relationships of the element v.
+func addRelationship(v any, r *mdl.Relationship) {
+	switch e := v.(type) {
+	case *mdl.Person:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.SoftwareSystem:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.Container:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.Component:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.DeploymentNode:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.InfrastructureNode:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.ContainerInstance:
+		e.Relationships = append(e.Relationships, r)
+	case *mdl.SoftwareSystemInstance:
+		e.Relationships = append(e.Relationships, r)
+	}
+}
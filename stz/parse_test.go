@@ -0,0 +1,349 @@
+package stz
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"goa.design/model/mdl"
+)
+
+func TestParseDSL(t *testing.T) {
+	const src = `workspace "Shop" "The \"shop\" workspace" {
+    !identifiers hierarchical
+
+    model {
+        # Comments are ignored
+        enterprise "Acme" {
+            shop = softwareSystem "Shop" "Sells things" {
+                db = container "DB" {
+                    technology "PostgreSQL"
+                    tags "Database"
+                }
+                web = container "Web" "" "Go" {
+                    -> shop.db "Reads" "" "Asynchronous"
+                }
+            }
+        }
+        user = person "User" "" "Customer" {
+            this -> shop.web "Browses" "HTTPS" {
+                url "https://shop.example.com"
+            }
+        }
+
+        deploymentEnvironment "Production" {
+            server = deploymentNode "Server" "" "Linux" "" "3" {
+                containerInstance shop.web
+                containerInstance shop.db {
+                    healthCheck "Ping" "https://db/ping" 30
+                }
+            }
+        }
+    }
+
+    views {
+        systemContext shop {
+            include *
+            autoLayout lr 300
+        }
+        container shop "Containers" {
+            include *
+            exclude user
+        }
+        dynamic shop "Checkout" {
+            user -> shop.web "Checks out"
+            {
+                shop.web -> shop.db
+            }
+        }
+        deployment * "Production" "Deployment" {
+            include *
+        }
+        filtered "Containers" exclude "Database" "NoDB"
+
+        styles {
+            element "Database" {
+                shape cylinder
+                background #ffffff
+            }
+            relationship "Asynchronous" {
+                style dashed
+                routing orthogonal
+            }
+        }
+        theme default
+    }
+}
+`
+	d, err := ParseDSL(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Shop" || d.Description != `The "shop" workspace` {
+		t.Errorf("got name %q and description %q", d.Name, d.Description)
+	}
+	m := d.Model
+	if m.Enterprise == nil || m.Enterprise.Name != "Acme" {
+		t.Fatalf("got enterprise %v, want Acme", m.Enterprise)
+	}
+	if len(m.People) != 1 || len(m.Systems) != 1 {
+		t.Fatalf("got %d people and %d systems, want 1 and 1", len(m.People), len(m.Systems))
+	}
+	user, shop := m.People[0], m.Systems[0]
+	if user.Location != mdl.LocationExternal || shop.Location != mdl.LocationInternal {
+		t.Errorf("got locations %v and %v", user.Location, shop.Location)
+	}
+	if user.Tags != "Element,Person,Customer" {
+		t.Errorf("got person tags %q", user.Tags)
+	}
+	if len(shop.Containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(shop.Containers))
+	}
+	db, web := shop.Containers[0], shop.Containers[1]
+	if db.Technology != "PostgreSQL" || db.Tags != "Element,Container,Database" {
+		t.Errorf("got DB technology %q and tags %q", db.Technology, db.Tags)
+	}
+	if len(web.Relationships) != 1 {
+		t.Fatalf("got %d web relationships, want 1", len(web.Relationships))
+	}
+	if r := web.Relationships[0]; r.DestinationID != db.ID || r.InteractionStyle != mdl.InteractionAsynchronous || r.Tags != "Relationship,Asynchronous" {
+		t.Errorf("got web relationship %+v", r)
+	}
+	if len(user.Relationships) != 2 {
+		t.Fatalf("got %d user relationships (including implied), want 2", len(user.Relationships))
+	}
+	browses, implied := user.Relationships[0], user.Relationships[1]
+	if browses.DestinationID != web.ID || browses.Technology != "HTTPS" || browses.URL != "https://shop.example.com" || browses.Tags != "Relationship,Synchronous" {
+		t.Errorf("got user relationship %+v", browses)
+	}
+	if implied.DestinationID != shop.ID || implied.Description != "Browses" {
+		t.Errorf("got implied relationship %+v", implied)
+	}
+
+	if len(m.DeploymentNodes) != 1 {
+		t.Fatalf("got %d deployment nodes, want 1", len(m.DeploymentNodes))
+	}
+	server := m.DeploymentNodes[0]
+	if server.Environment != "Production" || server.Technology != "Linux" || *server.Instances != "3" {
+		t.Errorf("got deployment node %+v", server)
+	}
+	if len(server.ContainerInstances) != 2 {
+		t.Fatalf("got %d container instances, want 2", len(server.ContainerInstances))
+	}
+	webInst, dbInst := server.ContainerInstances[0], server.ContainerInstances[1]
+	if len(webInst.Relationships) != 1 || webInst.Relationships[0].DestinationID != dbInst.ID || webInst.Relationships[0].LinkedRelationshipID != web.Relationships[0].ID {
+		t.Errorf("got web instance relationships %+v", webInst.Relationships)
+	}
+	if len(dbInst.HealthChecks) != 1 || dbInst.HealthChecks[0].Interval != 30 {
+		t.Errorf("got health checks %+v", dbInst.HealthChecks)
+	}
+
+	v := d.Views
+	if len(v.ContextViews) != 1 || v.ContextViews[0].Key != "SystemContext-001" {
+		t.Fatalf("got context views %+v", v.ContextViews)
+	}
+	ctx := v.ContextViews[0]
+	if got := elementIDs(ctx.ElementViews); got != shop.ID+","+user.ID {
+		t.Errorf("got context view elements %q", got)
+	}
+	if len(ctx.RelationshipViews) != 1 || ctx.RelationshipViews[0].ID != implied.ID {
+		t.Errorf("got context view relationships %+v", ctx.RelationshipViews)
+	}
+	if al := ctx.AutoLayout; al == nil || al.RankDirection != mdl.RankLeftRight || *al.RankSep != 300 || al.NodeSep != nil {
+		t.Errorf("got auto layout %+v", al)
+	}
+	if got := elementIDs(v.ContainerViews[0].ElementViews); got != db.ID+","+web.ID {
+		t.Errorf("got container view elements %q", got)
+	}
+	dyn := v.DynamicViews[0]
+	if dyn.ElementID != shop.ID || len(dyn.RelationshipViews) != 2 {
+		t.Fatalf("got dynamic view %+v", dyn)
+	}
+	if rv := dyn.RelationshipViews[0]; rv.ID != browses.ID || rv.Description != "Checks out" || rv.Order != "1" {
+		t.Errorf("got first dynamic step %+v", rv)
+	}
+	if rv := dyn.RelationshipViews[1]; rv.ID != web.Relationships[0].ID || rv.Order != "2" {
+		t.Errorf("got second dynamic step %+v", rv)
+	}
+	if got := elementIDs(v.DeploymentViews[0].ElementViews); got != webInst.ID+","+server.ID+","+dbInst.ID {
+		t.Errorf("got deployment view elements %q", got)
+	}
+	if fv := v.FilteredViews[0]; fv.Key != "NoDB" || fv.BaseKey != "Containers" || fv.Mode != "Exclude" || len(fv.Tags) != 1 {
+		t.Errorf("got filtered view %+v", fv)
+	}
+	es, rs := v.Styles.Elements[0], v.Styles.Relationships[0]
+	if es.Shape != mdl.ShapeCylinder || es.Background != "#ffffff" {
+		t.Errorf("got element style %+v", es)
+	}
+	if rs.Dashed == nil || !*rs.Dashed || rs.Routing != mdl.RoutingOrthogonal {
+		t.Errorf("got relationship style %+v", rs)
+	}
+}
+
+func TestParseDSLRoundTrip(t *testing.T) {
+	d := &mdl.Design{
+		Name: "Shop",
+		Model: &mdl.Model{
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Tags: "Element,Person",
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Tags: "Relationship,Synchronous", InteractionStyle: mdl.InteractionSynchronous},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container", Group: "Frontend"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}},
+				},
+			}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteDSL(&buf, d); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseDSL(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := got.Model.Systems[0].Containers[0]
+	if web.Name != "Web" || web.Technology != "Go" || web.Group != "Frontend" {
+		t.Errorf("got container %+v", web)
+	}
+	rels := got.Model.People[0].Relationships
+	if len(rels) != 1 || rels[0].DestinationID != web.ID || rels[0].Tags != "Relationship,Synchronous" {
+		t.Errorf("got relationships %+v", rels)
+	}
+	cv := got.Views.ContainerViews[0]
+	if cv.Key != "Containers" || len(cv.ElementViews) != 2 || len(cv.RelationshipViews) != 1 {
+		t.Errorf("got container view %+v", cv.ViewProps)
+	}
+}
+
+func TestLoadDesign(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, v any) string {
+		t.Helper()
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("design", func(t *testing.T) {
+		d := &mdl.Design{
+			Name: "Shop",
+			Model: &mdl.Model{
+				Systems: []*mdl.SoftwareSystem{{
+					ID: "shop", Name: "Shop", Tags: "Element,Software System",
+					Containers: []*mdl.Container{{
+						ID: "api", Name: "API", Tags: "Element,Container",
+						Components: []*mdl.Component{{ID: "users", Name: "Users", Tags: "Element,Component"}},
+					}},
+				}},
+			},
+			Views: &mdl.Views{
+				CodeViews: []*mdl.CodeView{{
+					ComponentID: "users",
+					ViewProps:   &mdl.ViewProps{Key: "Code", ElementViews: []*mdl.ElementView{{ID: "users"}}},
+				}},
+				Styles: &mdl.Styles{Elements: []*mdl.ElementStyle{{Tag: "Component"}}},
+			},
+			Documentation: &mdl.Documentation{
+				Sections: []*mdl.DocumentationSection{
+					{Title: "Overview", Content: "The shop.", Format: mdl.FormatMarkdown, Order: 1, ElementID: "shop"},
+				},
+				Decisions: []*mdl.Decision{
+					{ID: "1", Date: "2024-01-02", Status: mdl.DecisionAccepted, Title: "Use Go", Content: "Go.", Format: mdl.FormatMarkdown, ElementID: "api"},
+				},
+			},
+		}
+		want, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadDesign(write("design.json", d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != string(want) {
+			t.Errorf("got design:\n%s\nwant:\n%s", b, want)
+		}
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		w := &Workspace{
+			ID:   42,
+			Name: "Shop",
+			Model: &mdl.Model{
+				Systems: []*mdl.SoftwareSystem{{ID: "shop", Name: "Shop", Tags: "Element,Software System"}},
+			},
+			Views: &Views{
+				Configuration: &Configuration{Styles: &mdl.Styles{Elements: []*mdl.ElementStyle{{Tag: "Software System"}}}},
+			},
+			Documentation: &Documentation{
+				Decisions: []*Decision{{ID: "1", Decision: DecisionAccepted, Title: "Use Go", Content: "Go.", Format: FormatMarkdown}},
+			},
+		}
+		got, err := LoadDesign(write("workspace.json", w))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Model.Systems) != 1 || got.Model.Systems[0].Name != "Shop" {
+			t.Errorf("got systems %+v", got.Model.Systems)
+		}
+		if s := got.Views.Styles; s == nil || len(s.Elements) != 1 || s.Elements[0].Tag != "Software System" {
+			t.Errorf("got styles %+v", s)
+		}
+		if doc := got.Documentation; doc == nil || len(doc.Decisions) != 1 || doc.Decisions[0].Status != mdl.DecisionAccepted {
+			t.Errorf("got documentation %+v", doc)
+		}
+	})
+}
+
+func TestParseDSLErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing workspace":     "model {\n}\n",
+		"unknown identifier":    "workspace {\n    model {\n        a -> b\n    }\n}\n",
+		"unterminated string":   "workspace \"Shop {\n}\n",
+		"missing brace":         "workspace {\n    model {\n",
+		"unsupported":           "workspace {\n    !script groovy {\n    }\n}\n",
+		"container in model":    "workspace {\n    model {\n        container \"A\"\n    }\n}\n",
+		"extends not supported": "workspace extends other.dsl {\n}\n",
+	}
+	for name, src := range cases {
+		if _, err := ParseDSL(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func elementIDs(evs []*mdl.ElementView) string {
+	ids := make([]string, len(evs))
+	for i, ev := range evs {
+		ids[i] = ev.ID
+	}
+	return strings.Join(ids, ",")
+}
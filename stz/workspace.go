@@ -71,7 +71,7 @@ type (
 		// Title (name/section heading) of section.
 		Title string `json:"title"`
 		// Markdown or AsciiDoc content of section.
-		Content string `json:"string"`
+		Content string `json:"content"`
 		// Content format.
 		Format DocFormatKind `json:"format"`
 		// Order (index) of section in document.
@@ -87,7 +87,7 @@ type (
 		// Date of decision in ISO 8601 format.
 		Date string `json:"date"`
 		// Status of decision.
-		Decision DecisionStatusKind `json:"decision"`
+		Decision DecisionStatusKind `json:"status"`
 		// Title of decision
 		Title string `json:"title"`
 		// Markdown or AsciiDoc content of decision.
@@ -96,12 +96,22 @@ type (
 		Format DocFormatKind `json:"format"`
 		// ID of element (in model) that decision applies to (optional).
 		ElementID string `json:"elementId,omitempty"`
+		// Links to other decisions (e.g. "Supersedes").
+		Links []*DecisionLink `json:"links,omitempty"`
+	}
+
+	// DecisionLink describes a link between two decisions.
+	DecisionLink struct {
+		// ID of linked decision.
+		ID string `json:"id"`
+		// Description of link (e.g. "Superseded by").
+		Description string `json:"description,omitempty"`
 	}
 
 	// Image represents a Base64 encoded image (PNG/JPG/GIF).
 	Image struct {
 		// Name of image.
-		Name string `json:"image"`
+		Name string `json:"name"`
 		// Base64 encoded content.
 		Content string `json:"content"`
 		// Image MIME type (e.g. "image/png")
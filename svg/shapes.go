@@ -0,0 +1,238 @@
+package svg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"goa.design/model/layout"
+	"goa.design/model/mdl"
+)
+
+// cylinderRadiusY returns the vertical radius of the ellipses of cylinders.
+func cylinderRadiusY(width float64) float64 {
+	return width / 2 / (5.5 + width/70)
+}
+
+// labelOffsetY returns the vertical offset of the content of nodes with the
+// given shape, some shapes move their content down to make room for their
+// decoration.
+func labelOffsetY(shape mdl.ShapeKind, width, height float64) float64 {
+	switch shape {
+	case mdl.ShapeCylinder:
+		return 2 * cylinderRadiusY(width)
+	case mdl.ShapePerson:
+		return height * 0.4
+	case mdl.ShapeFolder:
+		return width / 10
+	case mdl.ShapeRobot:
+		return height * 0.35
+	case mdl.ShapeWebBrowser:
+		return height / 8
+	default:
+		return 0
+	}
+}
+
+// writeShape writes the outline of a node with the given shape and size
+// centered on the origin. attrs are the presentation attributes of the
+// outline.
+func writeShape(b *strings.Builder, shape mdl.ShapeKind, w, h float64, attrs string) {
+	el := func(name string, args ...any) {
+		fmt.Fprintf(b, "<%s", name)
+		for i := 0; i < len(args); i += 2 {
+			fmt.Fprintf(b, ` %s="%v"`, args[i], args[i+1])
+		}
+		b.WriteString("/>")
+	}
+	translate := fmt.Sprintf("translate(%s,%s)", num(-w/2), num(-h/2))
+	switch shape {
+	case mdl.ShapeRoundedBox:
+		fmt.Fprintf(b, `<rect class="nodeBorder" %s rx="%s" ry="%s" x="%s" y="%s" width="%s" height="%s"/>`,
+			attrs, num(w/8), num(w/8), num(-w/2), num(-h/2), num(w), num(h))
+	case mdl.ShapeCylinder:
+		rx, ry := w/2, cylinderRadiusY(w)
+		d := fmt.Sprintf("M 0,%s a%s,%s 0,0,0 %s 0 a %s,%s 0,0,0 %s 0 l 0,%s a %s,%s 0,0,0 %s 0 l 0,%s",
+			num(ry), num(rx), num(ry), num(w), num(rx), num(ry), num(-w), num(h-2*ry), num(rx), num(ry), num(w), num(-h+2*ry))
+		fmt.Fprintf(b, `<path class="nodeBorder" %s d="%s" transform="%s"/>`, attrs, d, translate)
+	case mdl.ShapePerson:
+		d := fmt.Sprintf("M %s,%s A%s,%s 0,0,0 0 %s L%s,%s L%s,%s L%s,%s A%s,%s 0,0,0 %s %s A%s,%s 0,1,0 %s %s",
+			num(.38*w), num(h/3), num(w/2), num(h/2), num(h/2),
+			num(w/11), num(h), num(w-w/11), num(h), num(w), num(h/2),
+			num(w/2), num(h/2), num(w-.38*w), num(h/3),
+			num(w/6), num(w/6), num(.38*w), num(h/3))
+		fmt.Fprintf(b, `<path class="nodeBorder" %s d="%s" transform="%s"/>`, attrs, d, translate)
+	case mdl.ShapeCircle:
+		fmt.Fprintf(b, `<ellipse class="nodeBorder" %s cx="0" cy="0" rx="%s" ry="%s"/>`, attrs, num(w/2), num(w/2))
+	case mdl.ShapeEllipse:
+		fmt.Fprintf(b, `<ellipse class="nodeBorder" %s cx="0" cy="0" rx="%s" ry="%s"/>`, attrs, num(w*.55), num(w*.45))
+	case mdl.ShapeHexagon:
+		r := w / 2
+		var points []string
+		for i := 0; i <= 6; i++ {
+			a := math.Pi/3*float64(i) + math.Pi/6
+			points = append(points, num(math.Sin(a)*r), num(math.Cos(a)*r))
+		}
+		fmt.Fprintf(b, `<polygon class="nodeBorder" %s points="%s"/>`, attrs, strings.Join(points, ","))
+	case mdl.ShapeComponent:
+		dx := w / 10
+		fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+		el("rect", "rx", 3, "ry", 3, "x", num(-w/2), "y", num(-h/2), "width", num(w), "height", num(h))
+		el("rect", "rx", 3, "ry", 3, "x", num(-w/2-dx), "y", num(-h/2+dx*2.5), "width", num(dx*2), "height", num(dx))
+		el("rect", "rx", 3, "ry", 3, "x", num(-w/2-dx), "y", num(-h/2+dx), "width", num(dx*2), "height", num(dx))
+		b.WriteString("</g>")
+	case mdl.ShapeFolder:
+		dy := w / 20
+		fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+		el("path", "d", fmt.Sprintf("M0,%s l%s,%s h%s v%s", num(-h/2+2*dy), num(dy), num(-2*dy), num(w/2-dy*2), num(dy*2)))
+		el("rect", "rx", 3, "ry", 3, "x", num(-w/2), "y", num(-h/2+dy*2), "width", num(w), "height", num(h-dy*2))
+		b.WriteString("</g>")
+	case mdl.ShapeMobileDeviceLandscape:
+		dx, r := w/8, w/14
+		fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+		el("rect", "rx", num(r), "ry", num(r), "x", num(-w/2-dx), "y", num(-h/2), "width", num(w+2*dx), "height", num(h))
+		el("rect", "x", num(w/2+dx/2-r*.2), "y", num(-r), "width", num(r*.4), "height", num(r*2))
+		el("circle", "cx", num(-w/2-dx/2), "cy", 0, "r", num(r*.4))
+		el("path", "d", fmt.Sprintf("M%s,%s l0,%s M%s,%s l0,%s", num(-w/2), num(-h/2), num(h), num(w/2), num(-h/2), num(h)))
+		b.WriteString("</g>")
+	case mdl.ShapeMobileDevicePortrait:
+		dy, r := w/8, w/14
+		fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+		el("rect", "rx", num(r), "ry", num(r), "x", num(-w/2), "y", num(-h/2-dy), "width", num(w), "height", num(h+2*dy))
+		el("rect", "x", num(-r), "y", num(-h/2-dy/2-r*.2), "width", num(r*2), "height", num(r*.4))
+		el("circle", "cx", 0, "cy", num(h/2+dy/2), "r", num(r*.4))
+		el("path", "d", fmt.Sprintf("M%s,%s l%s,0 M%s,%s l%s,0", num(-w/2), num(-h/2), num(w), num(-w/2), num(h/2), num(w)))
+		b.WriteString("</g>")
+	case mdl.ShapePipe:
+		ry := h / 2
+		rx := ry / (2.5 + w/70)
+		d := fmt.Sprintf("M%s,0 a%s,%s 0,0,1 0,%s a%s,%s 0,0,1 0,%s l%s,0 a%s,%s 0,0,1 0,%s l%s,0",
+			num(-rx), num(rx), num(ry), num(h), num(rx), num(ry), num(-h), num(w), num(rx), num(ry), num(h), num(-w))
+		fmt.Fprintf(b, `<path class="nodeBorder" %s d="%s" transform="%s"/>`, attrs, d, translate)
+	case mdl.ShapeRobot:
+		writeRobot(b, w, h, attrs, el)
+	case mdl.ShapeWebBrowser:
+		dy := h / 8
+		fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+		el("rect", "rx", 3, "ry", 3, "x", num(-w/2), "y", num(-h/2), "width", num(w), "height", num(h))
+		el("path", "d", fmt.Sprintf("M%s,%s h%s M%s,%s h%s v%s h%s z M%s,%s h%s v%s h%s z",
+			num(-w/2), num(-h/2+dy), num(w),
+			num(-w/2+dy/4), num(-h/2+dy/4), num(dy/2), num(dy/2), num(-dy/2),
+			num(-w/2+dy), num(-h/2+dy/4), num(w-dy-dy/4), num(dy/2), num(-w+dy+dy/4)))
+		b.WriteString("</g>")
+	default:
+		fmt.Fprintf(b, `<rect class="nodeBorder" %s rx="3" ry="3" x="%s" y="%s" width="%s" height="%s"/>`,
+			attrs, num(-w/2), num(-h/2), num(w), num(h))
+	}
+}
+
+// writeRobot writes the outline of robot nodes: a body with a head on top.
+func writeRobot(b *strings.Builder, w, h float64, attrs string, el func(string, ...any)) {
+	head := math.Min(w*0.28, h*0.25)
+	headR := head * 0.2
+	antennaH := head * 0.25
+	antennaR := head * 0.08
+	eyeR := head * 0.12
+	eyeSpacing := head * 0.22
+	earW := head * 0.12
+	earH := head * 0.3
+	bodyTop := -h/2 + antennaH + head
+	headTop := -h/2 + antennaH
+	eyeY := headTop + head*0.4
+	mouthY := headTop + head*0.7
+	mouthW := head * 0.28
+
+	fmt.Fprintf(b, `<g class="nodeBorder" %s>`, attrs)
+	el("rect", "rx", num(earW*0.25), "ry", num(earW*0.25), "x", num(head/2+1), "y", num(eyeY-earH/2), "width", num(earW), "height", num(earH))
+	el("rect", "rx", num(earW*0.25), "ry", num(earW*0.25), "x", num(-head/2-earW-1), "y", num(eyeY-earH/2), "width", num(earW), "height", num(earH))
+	el("path", "class", "robot-mouth", "d", fmt.Sprintf("M%s,%s Q0,%s %s,%s", num(-mouthW/2), num(mouthY), num(mouthY+mouthW*0.3), num(mouthW/2), num(mouthY)),
+		"fill", "none", "stroke-width", num(antennaR*0.5), "stroke-linecap", "round")
+	el("circle", "class", "robot-eye", "cx", num(eyeSpacing), "cy", num(eyeY), "r", num(eyeR))
+	el("circle", "class", "robot-eye", "cx", num(-eyeSpacing), "cy", num(eyeY), "r", num(eyeR))
+	el("circle", "class", "robot-antenna-ball", "cx", 0, "cy", num(-h/2+antennaR*2), "r", num(antennaR*1.2))
+	el("line", "class", "robot-antenna", "x1", 0, "y1", num(headTop), "x2", 0, "y2", num(-h/2+antennaR*2),
+		"stroke-width", num(antennaR*0.6), "stroke-linecap", "round")
+	el("rect", "rx", num(headR), "ry", num(headR), "x", num(-head/2), "y", num(headTop), "width", num(head), "height", num(head))
+	el("rect", "rx", 3, "ry", 3, "x", num(-w/2), "y", num(bodyTop), "width", num(w), "height", num(h-antennaH-head))
+	b.WriteString("</g>")
+}
+
+// intersect returns the point where the segment between the center of the
+// node and p crosses the outline of the node.
+func intersect(n *node, p layout.Point) layout.Point {
+	w, h := n.width, n.height
+	c := layout.Point{X: n.x, Y: n.y}
+	switch n.style.shape {
+	case mdl.ShapeCircle, mdl.ShapeHexagon:
+		return intersectEllipse(c, w/2, w/2, p)
+	case mdl.ShapeEllipse:
+		return intersectEllipse(c, w*.55, w*.45, p)
+	case mdl.ShapeComponent:
+		dx := w / 10
+		return intersectRect(layout.Point{X: c.X - dx/2, Y: c.Y}, w+dx, h, p)
+	case mdl.ShapeFolder:
+		dy := w / 20
+		return intersectRect(layout.Point{X: c.X, Y: c.Y + dy/2}, w, h+dy, p)
+	case mdl.ShapeMobileDeviceLandscape:
+		return intersectRect(c, w+w/4, h, p)
+	case mdl.ShapeMobileDevicePortrait:
+		return intersectRect(c, w, h+w/4, p)
+	case mdl.ShapePipe:
+		rx := h / 2 / (2.5 + w/70)
+		return intersectRect(layout.Point{X: c.X - rx, Y: c.Y}, w+2*rx, h, p)
+	case mdl.ShapeCylinder:
+		ry := cylinderRadiusY(w)
+		pos := intersectRect(c, w, h, p)
+		if cy := c.Y + h/2 - ry; pos.Y > cy {
+			return intersectEllipse(layout.Point{X: c.X, Y: cy}, w/2, ry, p)
+		}
+		if cy := c.Y - h/2 + ry; pos.Y < cy {
+			return intersectEllipse(layout.Point{X: c.X, Y: cy}, w/2, ry, p)
+		}
+		return pos
+	default:
+		return intersectRect(c, w, h, p)
+	}
+}
+
+// intersectRect returns the point where the segment between the center c of
+// a rectangle of size w x h and p crosses the rectangle.
+func intersectRect(c layout.Point, w, h float64, p layout.Point) layout.Point {
+	dx, dy := p.X-c.X, p.Y-c.Y
+	hw, hh := w/2, h/2
+	if dx == 0 && dy == 0 {
+		return c
+	}
+	var sx, sy float64
+	if math.Abs(dy)*hw > math.Abs(dx)*hh {
+		if dy < 0 {
+			hh = -hh
+		}
+		sx, sy = hh*dx/dy, hh
+	} else {
+		if dx < 0 {
+			hw = -hw
+		}
+		sx, sy = hw, hw*dy/dx
+	}
+	return layout.Point{X: c.X + sx, Y: c.Y + sy}
+}
+
+// intersectEllipse returns the point where the segment between the center c
+// of an ellipse with radii rx and ry and p crosses the ellipse.
+func intersectEllipse(c layout.Point, rx, ry float64, p layout.Point) layout.Point {
+	px, py := c.X-p.X, c.Y-p.Y
+	det := math.Sqrt(rx*rx*py*py + ry*ry*px*px)
+	if det == 0 {
+		return c
+	}
+	dx := math.Abs(rx * ry * px / det)
+	if p.X < c.X {
+		dx = -dx
+	}
+	dy := math.Abs(rx * ry * py / det)
+	if p.Y < c.Y {
+		dy = -dy
+	}
+	return layout.Point{X: c.X + dx, Y: c.Y + dy}
+}
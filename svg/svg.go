@@ -0,0 +1,1189 @@
+/*
+Package svg renders views as SVG documents without a browser.
+
+The documents have the same structure as the documents saved by the mdl
+editor: elements are drawn with the shapes and colors defined by the element
+styles of the design, relationships with the thickness, color and dash
+defined by the relationship styles and the elements that contain other
+elements of the view are drawn as boundaries. The documents embed the view
+metadata and layout in a script element so that the editor and subsequent
+renderings reuse the layout.
+
+Render places the elements using, in order of preference, the layout saved
+by the editor, the positions set in the view and the automatic layout
+computed by package layout.
+*/
+package svg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"goa.design/model/diff"
+	"goa.design/model/layout"
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+type (
+	// Options are the rendering settings.
+	Options struct {
+		// Direction overrides the rank direction of the view automatic
+		// layout if set.
+		Direction mdl.RankDirectionKind
+		// Compact reduces the distance between elements placed by the
+		// automatic layout.
+		Compact bool
+		// Layout is the layout saved by the editor for the view if any:
+		// element centers keyed by element ID and relationship vertices
+		// keyed by "e-" followed by the relationship ID.
+		Layout map[string]any
+	}
+
+	// diagram is a view ready to be rendered.
+	diagram struct {
+		v        *query.View
+		design   *mdl.Design
+		styles   *mdl.Styles
+		nodes    []*node
+		byID     map[string]*node
+		groups   []*group
+		edges    []*edge
+		elements []*elementMetadata
+		deleted  []string
+	}
+
+	// node is the rendering of a view element.
+	node struct {
+		id            string
+		name          string
+		link          string
+		style         *elementStyle
+		blocks        []*block
+		textHeight    float64
+		width, height float64
+		x, y          float64
+		group         *group
+	}
+
+	// block is a paragraph of the text of a node.
+	block struct {
+		lines    []string
+		fontSize float64
+		bold     bool
+		field    string
+		gapAfter float64
+	}
+
+	// group is the boundary drawn around the nodes of the elements that
+	// belong to the same parent element or named group.
+	group struct {
+		id     string
+		name   string
+		parent *group
+		nodes  []*node
+		groups []*group
+		style  *elementStyle
+		// x, y is the top left corner of the group.
+		x, y          float64
+		width, height float64
+	}
+
+	// edge is the rendering of a view relationship.
+	edge struct {
+		id          string
+		from, to    *node
+		lines       []string
+		labelWidth  float64
+		labelHeight float64
+		style       *relationshipStyle
+		vertices    []*vertex
+		// label is the center of the label computed by the automatic
+		// layout if any.
+		label *layout.Point
+	}
+
+	// vertex is a point an edge goes through as saved in layouts.
+	vertex struct {
+		X     float64 `json:"x"`
+		Y     float64 `json:"y"`
+		Label bool    `json:"label,omitempty"`
+		Auto  bool    `json:"auto,omitempty"`
+	}
+
+	// elementStyle is the style of a node obtained by merging the styles of
+	// the element tags.
+	elementStyle struct {
+		width, height float64
+		fontSize      float64
+		background    string
+		color         string
+		stroke        string
+		shape         mdl.ShapeKind
+		border        mdl.BorderKind
+		opacity       float64
+		metadata      bool
+		description   bool
+	}
+
+	// relationshipStyle is the style of an edge obtained by merging the
+	// styles of the relationship tags.
+	relationshipStyle struct {
+		thickness float64
+		color     string
+		fontSize  float64
+		width     float64
+		dashed    bool
+		routing   mdl.RoutingKind
+		position  float64
+		opacity   float64
+	}
+
+	// metadata is the view metadata embedded in the document.
+	metadata struct {
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		Version     string             `json:"version,omitempty"`
+		Elements    []*elementMetadata `json:"elements"`
+		Layout      map[string]any     `json:"layout"`
+	}
+
+	// elementMetadata is the metadata of a view element.
+	elementMetadata struct {
+		ID             string            `json:"id"`
+		Tags           string            `json:"tags,omitempty"`
+		Location       mdl.LocationKind  `json:"location,omitempty"`
+		Properties     map[string]string `json:"properties,omitempty"`
+		ElementViewKey string            `json:"elementViewKey,omitempty"`
+		Technology     string            `json:"technology,omitempty"`
+		URL            string            `json:"url,omitempty"`
+	}
+)
+
+const (
+	// padding is the distance between the content and the document border.
+	padding = 50
+	// groupPadding is the distance between groups and their content.
+	groupPadding = 25
+	// groupLabelHeight is the space reserved for the name of groups below
+	// their content.
+	groupLabelHeight = 30
+	// groupFontSize is the font size of the name of groups.
+	groupFontSize = 22
+	// labelGap is the distance between edges and their labels.
+	labelGap = 12
+	// enterpriseID is the ID of the group of the internal people and
+	// software systems of landscape views.
+	enterpriseID = "__enterprise__"
+)
+
+// Render writes the view v of the design indexed by g to w as a SVG
+// document.
+func Render(w io.Writer, g *query.Graph, v *query.View, opts Options) error {
+	d := newDiagram(g, v)
+	if err := d.place(opts); err != nil {
+		return fmt.Errorf("view %s: %w", v.Key, err)
+	}
+	return d.write(w)
+}
+
+// newDiagram builds the nodes, groups and edges of v.
+func newDiagram(g *query.Graph, v *query.View) *diagram {
+	d := &diagram{v: v, design: g.Design, byID: make(map[string]*node)}
+	if g.Design.Views != nil {
+		d.styles = g.Design.Views.Styles
+	}
+
+	// Elements that contain other elements of the view are rendered as
+	// groups.
+	grouping := make(map[string]string)
+	parents := make(map[string]string)
+	switch {
+	case v.Kind == query.ViewDeployment || v.Kind == query.ViewContainer || v.Kind == query.ViewCode:
+		for _, e := range v.Elements {
+			if e.Parent != nil {
+				grouping[e.Parent.ID] = e.Parent.Name
+				parents[e.ID] = e.Parent.ID
+			}
+		}
+	case v.Kind == query.ViewContext && v.Scope != nil && !v.Includes(v.Scope.ID):
+		grouping[v.Scope.ID] = v.Scope.Name
+		for _, e := range v.Elements {
+			if e.Parent == v.Scope {
+				parents[e.ID] = v.Scope.ID
+			}
+		}
+	case v.Kind == query.ViewLandscape:
+		var name string
+		if g.Design.Model != nil && g.Design.Model.Enterprise != nil {
+			name = g.Design.Model.Enterprise.Name
+		}
+		grouping[enterpriseID] = name
+		for _, e := range v.Elements {
+			if location(e) != mdl.LocationExternal {
+				parents[e.ID] = enterpriseID
+			}
+		}
+	}
+
+	// Named groups are nested in the group of the parent of their elements.
+	named := make(map[string]string)
+	var namedIDs []string
+	for _, e := range v.Elements {
+		name := groupName(rendered(e))
+		if name == "" {
+			continue
+		}
+		id := "__group__:" + parents[e.ID] + ":" + name
+		if _, ok := grouping[id]; !ok {
+			grouping[id] = name
+			named[id] = parents[e.ID]
+			namedIDs = append(namedIDs, id)
+		}
+		parents[e.ID] = id
+	}
+
+	byID := make(map[string]*group)
+	groupOf := func(id string) *group {
+		if _, ok := grouping[id]; !ok {
+			return nil
+		}
+		gr, ok := byID[id]
+		if !ok {
+			gr = &group{id: id, name: grouping[id], style: &elementStyle{}}
+			byID[id] = gr
+		}
+		return gr
+	}
+	for _, e := range v.Elements {
+		if _, ok := grouping[e.ID]; ok {
+			if gr, p := groupOf(e.ID), groupOf(parents[e.ID]); p != nil {
+				gr.parent = p
+			}
+			if v.Kind == query.ViewDeployment {
+				byID[e.ID].style = d.elementStyle(e)
+			}
+			continue
+		}
+		n := d.newNode(e)
+		n.group = groupOf(parents[e.ID])
+		if n.group != nil {
+			n.group.nodes = append(n.group.nodes, n)
+		}
+		d.nodes = append(d.nodes, n)
+		d.byID[n.id] = n
+	}
+	for _, id := range namedIDs {
+		if gr := byID[id]; gr != nil {
+			gr.parent = groupOf(named[id])
+		}
+	}
+
+	// Keep the groups that contain nodes, parents first.
+	var add func(gr *group)
+	added := make(map[*group]bool)
+	add = func(gr *group) {
+		if added[gr] {
+			return
+		}
+		if gr.parent != nil {
+			add(gr.parent)
+		}
+		added[gr] = true
+		d.groups = append(d.groups, gr)
+		if gr.parent != nil {
+			gr.parent.groups = append(gr.parent.groups, gr)
+		}
+	}
+	for _, n := range d.nodes {
+		if n.group != nil {
+			add(n.group)
+		}
+	}
+
+	for _, r := range v.Relationships {
+		from, to := d.byID[r.Source.ID], d.byID[r.Destination.ID]
+		if from == nil || to == nil {
+			continue
+		}
+		d.edges = append(d.edges, d.newEdge(r, from, to))
+	}
+	return d
+}
+
+// subtitleTag returns the last tag of the comma separated list tags that was
+// not added to highlight changes, see diff.Highlight.
+func subtitleTag(tags string) string {
+	list := strings.Split(tags, ",")
+	for i := len(list) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(strings.TrimSpace(list[i]), diff.TagPrefix) {
+			return list[i]
+		}
+	}
+	return ""
+}
+
+// newNode returns the node of the view element e.
+func (d *diagram) newNode(e *query.Element) *node {
+	el := rendered(e)
+	style := d.elementStyle(e)
+	sub := subtitleTag(el.Tags)
+	if el.Technology != "" {
+		sub += ": " + el.Technology
+	}
+	n := &node{id: e.ID, name: el.Name, style: style}
+	if n.name == "" {
+		n.name = e.ID
+	}
+
+	// Size the node so that its text fits.
+	n.width = math.Max(280, style.width)
+	fs := style.fontSize
+	textW := math.Max(n.width-36, 80)
+	n.blocks = append(n.blocks, newBlock(n.name, textW, fs, true, 6, "name"))
+	if style.metadata {
+		n.blocks = append(n.blocks, newBlock("["+sub+"]", textW, fs*0.75, false, 10, ""))
+	}
+	if style.description {
+		n.blocks = append(n.blocks, newBlock(el.Description, textW, math.Min(fs*0.8, 16), false, 0, "description"))
+	}
+	for _, b := range n.blocks {
+		n.textHeight += float64(len(b.lines))*(b.fontSize+2) + b.gapAfter
+	}
+	minimum := n.textHeight + 36
+	n.height = 180
+	if style.shape == mdl.ShapePerson {
+		n.height = 240
+	}
+	n.height = math.Max(math.Max(n.height, style.height), minimum)
+	for range 20 {
+		required := minimum + math.Abs(labelOffsetY(style.shape, n.width, n.height))
+		if required <= n.height+0.1 {
+			break
+		}
+		n.height = required
+	}
+
+	meta := &elementMetadata{
+		ID:         el.ID,
+		Tags:       el.Tags,
+		Location:   location(el),
+		Properties: el.Properties,
+		Technology: el.Technology,
+		URL:        elementURL(el),
+	}
+	if el == e {
+		meta.ElementViewKey = d.containerViewKey(e.ID)
+	} else {
+		meta.ID = e.ID
+	}
+	switch {
+	case meta.ElementViewKey != "":
+		n.link = url.PathEscape(meta.ElementViewKey) + ".svg"
+	case meta.URL != "":
+		n.link = meta.URL
+	}
+	d.elements = append(d.elements, meta)
+	return n
+}
+
+// newBlock returns a paragraph of node text wrapped to fit width.
+func newBlock(text string, width, fontSize float64, bold bool, gapAfter float64, field string) *block {
+	lines, _ := wrap(text, width, fontSize, bold)
+	return &block{lines: lines, fontSize: fontSize, bold: bold, field: field, gapAfter: gapAfter}
+}
+
+// newEdge returns the edge of the view relationship r.
+func (d *diagram) newEdge(r *query.ViewRelationship, from, to *node) *edge {
+	style := d.relationshipStyle(r.Relationship)
+	if r.Props != nil && r.Props.Routing != mdl.RoutingUndefined {
+		style.routing = r.Props.Routing
+	}
+	if r.Props != nil && r.Props.Position != nil {
+		style.position = float64(*r.Props.Position)
+	}
+	e := &edge{id: r.Relationship.ID, from: from, to: to, style: style}
+	if strings.TrimSpace(r.Description) != "" {
+		fs := style.fontSize
+		var maxW float64
+		e.lines, maxW = wrap(r.Description, style.width, fs, false)
+		e.labelWidth = maxW + fs
+		e.labelHeight = float64(len(e.lines)+1)*(fs+2) - fs/2
+	}
+	return e
+}
+
+// place computes the position of the nodes, groups and edges.
+func (d *diagram) place(opts Options) error {
+	saved := make(map[string]layout.Point)
+	for _, n := range d.nodes {
+		if p, ok := point(opts.Layout[n.id]); ok {
+			saved[n.id] = p
+		}
+	}
+	for k, v := range opts.Layout {
+		if deleted, ok := v.(bool); ok && deleted && strings.HasSuffix(k, "-deleted") {
+			d.deleted = append(d.deleted, k)
+		}
+	}
+	switch {
+	case len(d.nodes) > 0 && len(saved) == len(d.nodes):
+		for _, n := range d.nodes {
+			n.x, n.y = saved[n.id].X, saved[n.id].Y
+		}
+		for _, e := range d.edges {
+			e.vertices = vertices(opts.Layout["e-"+e.id])
+		}
+	case d.viewPositions():
+	default:
+		sep, err := d.autoLayout(opts)
+		if err != nil {
+			return err
+		}
+		if len(saved) > 0 {
+			d.merge(saved, opts.Layout, sep)
+		}
+	}
+	d.placeGroups()
+	return nil
+}
+
+// viewPositions places the nodes at the positions set in the view if all
+// nodes have one. It returns false if some node has no position.
+func (d *diagram) viewPositions() bool {
+	if d.v.Props == nil || len(d.nodes) == 0 {
+		return false
+	}
+	positions := make(map[string]*mdl.ElementView)
+	for _, ev := range d.v.Props.ElementViews {
+		if ev.X != nil && ev.Y != nil {
+			positions[ev.ID] = ev
+		}
+	}
+	for _, n := range d.nodes {
+		if positions[n.id] == nil {
+			return false
+		}
+	}
+	for _, n := range d.nodes {
+		ev := positions[n.id]
+		n.x = float64(*ev.X) + n.width/2
+		n.y = float64(*ev.Y) + n.height/2
+	}
+	for _, rv := range d.v.Props.RelationshipViews {
+		for _, e := range d.edges {
+			if e.id != rv.ID {
+				continue
+			}
+			for _, p := range rv.Vertices {
+				e.vertices = append(e.vertices, &vertex{X: float64(p.X), Y: float64(p.Y)})
+			}
+		}
+	}
+	return true
+}
+
+// autoLayout places the nodes and edges using the automatic layout. It
+// returns the rank separation.
+func (d *diagram) autoLayout(opts Options) (float64, error) {
+	sep := 80.0
+	if opts.Compact {
+		sep = 56
+	}
+	lopts := layout.Options{
+		Direction:          opts.Direction,
+		RankSep:            sep,
+		NodeSep:            sep,
+		ClusterPadding:     groupPadding,
+		ClusterLabelHeight: groupLabelHeight,
+	}
+	if p := d.v.Props; p != nil && p.AutoLayout != nil {
+		al := p.AutoLayout
+		if lopts.Direction == mdl.RankUndefined {
+			lopts.Direction = al.RankDirection
+		}
+		if al.RankSep != nil {
+			lopts.RankSep = float64(*al.RankSep)
+		}
+		if al.NodeSep != nil {
+			lopts.NodeSep = float64(*al.NodeSep)
+		}
+		if al.EdgeSep != nil {
+			lopts.EdgeSep = float64(*al.EdgeSep)
+		}
+	}
+
+	var g layout.Graph
+	lnodes := make(map[*node]*layout.Node)
+	lgroups := make(map[*group]*layout.Node)
+	for _, gr := range d.groups {
+		ln := &layout.Node{ID: gr.id}
+		lgroups[gr] = ln
+		if gr.parent == nil {
+			g.Nodes = append(g.Nodes, ln)
+		} else {
+			parent := lgroups[gr.parent]
+			parent.Children = append(parent.Children, ln)
+		}
+	}
+	for _, n := range d.nodes {
+		top, bottom := extent(n)
+		ln := &layout.Node{ID: n.id, Width: n.width, Height: 2 * math.Max(top, bottom)}
+		lnodes[n] = ln
+		if n.group == nil {
+			g.Nodes = append(g.Nodes, ln)
+		} else {
+			parent := lgroups[n.group]
+			parent.Children = append(parent.Children, ln)
+		}
+	}
+	ledges := make([]*layout.Edge, len(d.edges))
+	for i, e := range d.edges {
+		ledges[i] = &layout.Edge{
+			ID:          e.id,
+			Source:      e.from.id,
+			Destination: e.to.id,
+			LabelWidth:  e.labelWidth,
+			LabelHeight: e.labelHeight,
+		}
+	}
+	g.Edges = ledges
+	if err := layout.Layout(&g, lopts); err != nil {
+		return 0, err
+	}
+	for n, ln := range lnodes {
+		n.x, n.y = ln.X, ln.Y
+	}
+	for i, e := range d.edges {
+		le := ledges[i]
+		e.vertices = nil
+		for _, p := range le.Vertices {
+			e.vertices = append(e.vertices, &vertex{X: p.X, Y: p.Y, Auto: true})
+		}
+		if e.labelWidth > 0 && e.from != e.to {
+			e.label = &le.Label
+		}
+	}
+	return lopts.RankSep, nil
+}
+
+// merge moves the nodes that have a saved position to that position and the
+// other nodes below them so that elements added to a view since its layout
+// was saved do not disturb the saved layout.
+func (d *diagram) merge(saved map[string]layout.Point, lay map[string]any, sep float64) {
+	savedBox, autoBox := newBox(), newBox()
+	for _, n := range d.nodes {
+		top, bottom := extent(n)
+		if p, ok := saved[n.id]; ok {
+			savedBox.add(p.X-n.width/2, p.Y-top, p.X+n.width/2, p.Y+bottom)
+		} else {
+			autoBox.add(n.x-n.width/2, n.y-top, n.x+n.width/2, n.y+bottom)
+		}
+	}
+	dx, dy := savedBox.minX-autoBox.minX, savedBox.maxY+sep+groupLabelHeight-autoBox.minY
+	for _, n := range d.nodes {
+		if p, ok := saved[n.id]; ok {
+			n.x, n.y = p.X, p.Y
+			continue
+		}
+		n.x += dx
+		n.y += dy
+	}
+	for _, e := range d.edges {
+		_, fromSaved := saved[e.from.id]
+		_, toSaved := saved[e.to.id]
+		switch {
+		case fromSaved && toSaved:
+			e.vertices, e.label = vertices(lay["e-"+e.id]), nil
+		case !fromSaved && !toSaved:
+			for _, v := range e.vertices {
+				v.X += dx
+				v.Y += dy
+			}
+			if e.label != nil {
+				e.label = &layout.Point{X: e.label.X + dx, Y: e.label.Y + dy}
+			}
+		default:
+			e.vertices, e.label = nil, nil
+		}
+	}
+}
+
+// placeGroups computes the bounds of the groups from the bounds of their
+// content, nested groups first.
+func (d *diagram) placeGroups() {
+	for i := len(d.groups) - 1; i >= 0; i-- {
+		gr := d.groups[i]
+		b := newBox()
+		for _, n := range gr.nodes {
+			top, bottom := extent(n)
+			b.add(n.x-n.width/2, n.y-top, n.x+n.width/2, n.y+bottom)
+		}
+		for _, c := range gr.groups {
+			b.add(c.x, c.y, c.x+c.width, c.y+c.height)
+		}
+		gr.x, gr.y = b.minX-groupPadding, b.minY-groupPadding
+		gr.width = math.Max(b.maxX-b.minX, 200) + 2*groupPadding
+		gr.height = b.maxY - b.minY + 2*groupPadding + groupLabelHeight
+	}
+}
+
+// route returns the points of the path of e from the border of its source
+// to the border of its destination.
+func (d *diagram) route(e *edge) []layout.Point {
+	points := []layout.Point{{X: e.from.x, Y: e.from.y}}
+	for _, v := range e.vertices {
+		points = append(points, layout.Point{X: v.X, Y: v.Y})
+	}
+	if e.from == e.to && len(e.vertices) == 0 {
+		x := e.from.x + e.from.width/2 + 40
+		points = append(points, layout.Point{X: x, Y: e.from.y - e.from.height/4}, layout.Point{X: x, Y: e.from.y + e.from.height/4})
+	}
+	points = append(points, layout.Point{X: e.to.x, Y: e.to.y})
+	if e.style.routing == mdl.RoutingOrthogonal {
+		res := []layout.Point{points[0]}
+		for _, p := range points[1:] {
+			last := res[len(res)-1]
+			if last.X != p.X && last.Y != p.Y {
+				res = append(res, layout.Point{X: p.X, Y: last.Y})
+			}
+			res = append(res, p)
+		}
+		points = res
+	}
+	n := len(points)
+	points[0] = intersect(e.from, points[1])
+	points[n-1] = intersect(e.to, points[n-2])
+	return points
+}
+
+// labelBounds returns the top left corner of the label of e whose path
+// goes through points.
+func labelBounds(e *edge, points []layout.Point) (x, y float64) {
+	if e.label != nil {
+		return e.label.X - e.labelWidth/2, e.label.Y - e.labelHeight/2
+	}
+	var (
+		p        layout.Point
+		vertical bool
+	)
+	found := false
+	for i, v := range e.vertices {
+		if v.Label {
+			p, found = layout.Point{X: v.X, Y: v.Y}, true
+			a, b := points[0], points[len(points)-1]
+			if i > 0 {
+				a = layout.Point{X: e.vertices[i-1].X, Y: e.vertices[i-1].Y}
+			}
+			if i < len(e.vertices)-1 {
+				b = layout.Point{X: e.vertices[i+1].X, Y: e.vertices[i+1].Y}
+			}
+			vertical = math.Abs(b.Y-a.Y) > math.Abs(b.X-a.X)
+			break
+		}
+	}
+	if !found {
+		var total float64
+		for i := 1; i < len(points); i++ {
+			total += distance(points[i-1], points[i])
+		}
+		target := total * e.style.position / 100
+		p = points[0]
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+			l := distance(a, b)
+			if l > 0 && target <= l {
+				p = layout.Point{X: a.X + (b.X-a.X)*target/l, Y: a.Y + (b.Y-a.Y)*target/l}
+				vertical = math.Abs(b.Y-a.Y) > math.Abs(b.X-a.X)
+				break
+			}
+			target -= l
+		}
+	}
+	if vertical {
+		return p.X + labelGap, p.Y - e.labelHeight/2
+	}
+	return p.X - e.labelWidth/2, p.Y - e.labelHeight - labelGap
+}
+
+// write writes the document.
+func (d *diagram) write(w io.Writer) error {
+	routes := make([][]layout.Point, len(d.edges))
+	labels := make([]layout.Point, len(d.edges))
+	bounds := newBox()
+	for _, n := range d.nodes {
+		top, bottom := extent(n)
+		bounds.add(n.x-n.width/2, n.y-top, n.x+n.width/2, n.y+bottom)
+	}
+	for _, gr := range d.groups {
+		bounds.add(gr.x, gr.y, gr.x+gr.width, gr.y+gr.height)
+	}
+	for i, e := range d.edges {
+		routes[i] = d.route(e)
+		for _, p := range routes[i] {
+			bounds.add(p.X, p.Y, p.X, p.Y)
+		}
+		if len(e.lines) > 0 {
+			x, y := labelBounds(e, routes[i])
+			labels[i] = layout.Point{X: x, Y: y}
+			bounds.add(x, y, x+e.labelWidth, y+e.labelHeight)
+		}
+	}
+	if len(d.nodes) == 0 {
+		bounds = &box{0, 0, 100, 100}
+	}
+	width, height := bounds.maxX-bounds.minX+2*padding, bounds.maxY-bounds.minY+2*padding
+
+	meta, err := d.metadata()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg id="graph" xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %s %s" width="%s" height="%s">`,
+		num(width), num(height), num(width), num(height))
+	b.WriteString("\n<script type=\"application/json\"><![CDATA[")
+	b.Write(meta)
+	b.WriteString("]]></script>\n")
+	b.WriteString(defs)
+	fmt.Fprintf(&b, "<g class=\"zoom\" transform=\"scale(1) translate(%s, %s)\">\n", num(padding-bounds.minX), num(padding-bounds.minY))
+	for _, gr := range d.groups {
+		writeGroup(&b, gr)
+	}
+	for i, e := range d.edges {
+		writeEdge(&b, e, routes[i], labels[i])
+	}
+	for _, n := range d.nodes {
+		writeNode(&b, n)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// defs contains the definitions shared by all documents.
+const defs = `<defs>
+<marker id="arrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="8" markerHeight="8" orient="auto" markerUnits="strokeWidth">
+<path fill="context-stroke" stroke="none" d="M0,0 L10,5 L0,10 Z" class="arrowHead"/>
+</marker>
+<filter id="shadow">
+<feDropShadow dx="3" dy="3" stdDeviation="5" flood-color="#00000030" flood-opacity="1"></feDropShadow>
+</filter>
+</defs>
+`
+
+// metadata returns the JSON representation of the view metadata and layout.
+func (d *diagram) metadata() ([]byte, error) {
+	lay := make(map[string]any)
+	for _, n := range d.nodes {
+		lay[n.id] = &vertex{X: round(n.x), Y: round(n.y)}
+	}
+	for _, e := range d.edges {
+		if len(e.vertices) == 0 {
+			continue
+		}
+		vs := make([]*vertex, len(e.vertices))
+		for i, v := range e.vertices {
+			vs[i] = &vertex{X: round(v.X), Y: round(v.Y), Label: v.Label, Auto: v.Auto}
+		}
+		lay["e-"+e.id] = vs
+	}
+	for _, k := range d.deleted {
+		lay[k] = true
+	}
+	name := d.v.Title
+	if name == "" {
+		name = d.v.Key
+	}
+	elements := d.elements
+	if elements == nil {
+		elements = []*elementMetadata{}
+	}
+	m := &metadata{Name: name, Description: d.v.Description, Version: d.design.Version, Elements: elements, Layout: lay}
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// writeGroup writes the boundary of a group.
+func writeGroup(b *strings.Builder, gr *group) {
+	fill, stroke, color := "rgba(0, 0, 0, 0.02)", "#666", "#666"
+	if gr.style.background != "" {
+		fill = gr.style.background
+	}
+	if gr.style.stroke != "" {
+		stroke = gr.style.stroke
+	}
+	if gr.style.color != "" {
+		color = gr.style.color
+	}
+	b.WriteString(`<g class="group">`)
+	fmt.Fprintf(b, `<rect x="%s" y="%s" rx="0" ry="0" width="%s" height="%s" fill="%s" stroke="%s" stroke-width="3" stroke-dasharray="4"/>`,
+		num(gr.x), num(gr.y), num(gr.width), num(gr.height), esc(fill), esc(stroke))
+	fmt.Fprintf(b, `<text x="%s" y="%s" fill="%s" font-size="%d" font-weight="500" font-family="%s">%s</text>`,
+		num(gr.x+groupPadding), num(gr.y+gr.height-groupFontSize), esc(color), groupFontSize, fontFamily, esc(gr.name))
+	b.WriteString("</g>\n")
+}
+
+// writeEdge writes an edge going through points with its label at the
+// given top left corner.
+func writeEdge(b *strings.Builder, e *edge, points []layout.Point, label layout.Point) {
+	fmt.Fprintf(b, `<g class="edge" id="%s" data-from="%s" data-to="%s">`, esc(e.id), esc(e.from.id), esc(e.to.id))
+	if len(e.lines) > 0 {
+		fs := e.style.fontSize
+		fmt.Fprintf(b, `<rect x="%s" y="%s" rx="0" ry="0" width="%s" height="%s" fill="none" stroke="none"/>`,
+			num(label.X), num(label.Y), num(e.labelWidth), num(e.labelHeight))
+		cx := label.X + e.labelWidth/2
+		fmt.Fprintf(b, `<text x="0" y="%s" text-anchor="middle" font-family="%s" stroke="none" font-size="%s" fill="%s" data-field="label">`,
+			num(label.Y), fontFamily, num(fs), esc(e.style.color))
+		for _, line := range e.lines {
+			fmt.Fprintf(b, `<tspan x="%s" dy="%spx" font-size="%spx" font-weight="normal">%s</tspan>`, num(cx), num(fs+2), num(fs), esc(line))
+		}
+		b.WriteString("</text>")
+	}
+	path := make([]string, len(points))
+	for i, p := range points {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		path[i] = cmd + num(p.X) + "," + num(p.Y)
+	}
+	fmt.Fprintf(b, `<path class="edge" d="%s" marker-end="url(#arrow)" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round"`,
+		strings.Join(path, " "), esc(e.style.color), num(e.style.thickness))
+	if e.style.dashed {
+		b.WriteString(` stroke-dasharray="8"`)
+	}
+	if e.style.opacity < 100 {
+		fmt.Fprintf(b, ` opacity="%s"`, num(e.style.opacity/100))
+	}
+	b.WriteString("/></g>\n")
+}
+
+// writeNode writes a node and its text.
+func writeNode(b *strings.Builder, n *node) {
+	s := n.style
+	fmt.Fprintf(b, `<g class="node" id="%s" transform="translate(%s,%s)">`, esc(n.id), num(n.x), num(n.y))
+	if n.link != "" {
+		fmt.Fprintf(b, `<a class="nodeLink" href="%s" aria-label="Open %s">`, esc(n.link), esc(n.name))
+	}
+	attrs := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="3" opacity="%s" filter="url(#shadow)"`,
+		esc(s.background), esc(s.stroke), num(s.opacity))
+	switch s.border {
+	case mdl.BorderDashed:
+		attrs += ` stroke-dasharray="4"`
+	case mdl.BorderDotted:
+		attrs += ` stroke-dasharray="2"`
+	}
+	writeShape(b, s.shape, n.width, n.height, attrs)
+	fmt.Fprintf(b, `<g transform="translate(0,%s)">`, num(labelOffsetY(s.shape, n.width, n.height)/2))
+	top := -n.textHeight / 2
+	for _, bl := range n.blocks {
+		fmt.Fprintf(b, `<text text-anchor="middle" font-family="%s" stroke="none" fill="%s"`, fontFamily, esc(s.color))
+		if bl.field != "" {
+			fmt.Fprintf(b, ` data-field="%s"`, bl.field)
+		}
+		b.WriteString(">")
+		weight := "normal"
+		if bl.bold {
+			weight = "bold"
+		}
+		for i, line := range bl.lines {
+			fmt.Fprintf(b, `<tspan x="0" y="%s" font-size="%spx" font-weight="%s">%s</tspan>`,
+				num(top+bl.fontSize+float64(i)*(bl.fontSize+2)), num(bl.fontSize), weight, esc(line))
+		}
+		b.WriteString("</text>")
+		top += float64(len(bl.lines))*(bl.fontSize+2) + bl.gapAfter
+	}
+	b.WriteString("</g>")
+	if n.link != "" {
+		b.WriteString("</a>")
+	}
+	b.WriteString("</g>\n")
+}
+
+// elementStyle returns the style of e obtained by merging the styles of its
+// tags in the order of the tags, later tags override earlier ones.
+func (d *diagram) elementStyle(e *query.Element) *elementStyle {
+	res := &elementStyle{
+		background:  "rgba(255, 255, 255, .9)",
+		color:       "#666",
+		stroke:      "#999",
+		fontSize:    22,
+		opacity:     .9,
+		metadata:    true,
+		description: true,
+	}
+	if d.styles == nil {
+		return res
+	}
+	for _, tag := range strings.Split(rendered(e).Tags, ",") {
+		for _, s := range d.styles.Elements {
+			if s.Tag != strings.TrimSpace(tag) {
+				continue
+			}
+			if s.Width != nil {
+				res.width = float64(*s.Width)
+			}
+			if s.Height != nil {
+				res.height = float64(*s.Height)
+			}
+			if s.FontSize != nil {
+				res.fontSize = float64(*s.FontSize)
+			}
+			if s.Background != "" {
+				res.background = s.Background
+			}
+			if s.Color != "" {
+				res.color = s.Color
+			}
+			if s.Stroke != "" {
+				res.stroke = s.Stroke
+			}
+			if s.Shape != mdl.ShapeUndefined {
+				res.shape = s.Shape
+			}
+			if s.Border != mdl.BorderUndefined {
+				res.border = s.Border
+			}
+			if s.Opacity != nil {
+				res.opacity = float64(*s.Opacity) / 100
+			}
+			if s.Metadata != nil {
+				res.metadata = *s.Metadata
+			}
+			if s.Description != nil {
+				res.description = *s.Description
+			}
+		}
+	}
+	return res
+}
+
+// relationshipStyle returns the style of r obtained by merging the styles of
+// its tags in the order of the tags, later tags override earlier ones.
+func (d *diagram) relationshipStyle(r *mdl.Relationship) *relationshipStyle {
+	res := &relationshipStyle{
+		thickness: 3,
+		color:     "#999",
+		fontSize:  22,
+		width:     200,
+		dashed:    true,
+		position:  50,
+		opacity:   100,
+	}
+	if d.styles == nil {
+		return res
+	}
+	for _, tag := range strings.Split(r.Tags, ",") {
+		for _, s := range d.styles.Relationships {
+			if s.Tag != strings.TrimSpace(tag) {
+				continue
+			}
+			if s.Thickness != nil {
+				res.thickness = float64(*s.Thickness)
+			}
+			if s.Color != "" {
+				res.color = s.Color
+			}
+			if s.FontSize != nil {
+				res.fontSize = float64(*s.FontSize)
+			}
+			if s.Width != nil {
+				res.width = float64(*s.Width)
+			}
+			if s.Dashed != nil {
+				res.dashed = *s.Dashed
+			}
+			if s.Routing != mdl.RoutingUndefined {
+				res.routing = s.Routing
+			}
+			if s.Position != nil {
+				res.position = float64(*s.Position)
+			}
+			if s.Opacity != nil {
+				res.opacity = float64(*s.Opacity)
+			}
+		}
+	}
+	return res
+}
+
+// containerViewKey returns the key of the first container view of the
+// software system with the given ID if any.
+func (d *diagram) containerViewKey(id string) string {
+	if d.design.Views == nil {
+		return ""
+	}
+	for _, cv := range d.design.Views.ContainerViews {
+		if cv.SoftwareSystemID == id {
+			return cv.Key
+		}
+	}
+	return ""
+}
+
+// rendered returns the element rendered for the view element e: the
+// instantiated element for container and software system instances, e
+// otherwise.
+func rendered(e *query.Element) *query.Element {
+	if e.InstanceOf != nil {
+		return e.InstanceOf
+	}
+	return e
+}
+
+// location returns the location of e, people and software systems only have
+// a location.
+func location(e *query.Element) mdl.LocationKind {
+	switch v := e.Value.(type) {
+	case *mdl.Person:
+		return v.Location
+	case *mdl.SoftwareSystem:
+		return v.Location
+	}
+	return mdl.LocationUndefined
+}
+
+// groupName returns the name of the group of e if any.
+func groupName(e *query.Element) string {
+	switch v := e.Value.(type) {
+	case *mdl.Person:
+		return v.Group
+	case *mdl.SoftwareSystem:
+		return v.Group
+	case *mdl.Container:
+		return v.Group
+	case *mdl.Component:
+		return v.Group
+	}
+	return ""
+}
+
+// elementURL returns the URL of e if any.
+func elementURL(e *query.Element) string {
+	switch v := e.Value.(type) {
+	case *mdl.Person:
+		return v.URL
+	case *mdl.SoftwareSystem:
+		return v.URL
+	case *mdl.Container:
+		return v.URL
+	case *mdl.Component:
+		return v.URL
+	case *mdl.CodeElement:
+		return v.URL
+	case *mdl.DeploymentNode:
+		return v.URL
+	case *mdl.InfrastructureNode:
+		return v.URL
+	}
+	return ""
+}
+
+// extent returns the distance between the center of n and the top and the
+// bottom of its shape.
+func extent(n *node) (top, bottom float64) {
+	top, bottom = n.height/2, n.height/2
+	switch n.style.shape {
+	case mdl.ShapeRobot:
+		top += n.height * 0.12
+	case mdl.ShapeHexagon:
+		top = math.Max(top, n.width/2*0.866)
+		bottom = top
+	}
+	return
+}
+
+// point returns the point represented by v if v is a JSON object with
+// numeric x and y fields.
+func point(v any) (layout.Point, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return layout.Point{}, false
+	}
+	x, okx := m["x"].(float64)
+	y, oky := m["y"].(float64)
+	return layout.Point{X: x, Y: y}, okx && oky
+}
+
+// vertices returns the vertices represented by v if v is a JSON array of
+// vertices.
+func vertices(v any) []*vertex {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	var res []*vertex
+	for _, item := range list {
+		p, ok := point(item)
+		if !ok {
+			continue
+		}
+		m := item.(map[string]any)
+		label, _ := m["label"].(bool)
+		auto, _ := m["auto"].(bool)
+		res = append(res, &vertex{X: p.X, Y: p.Y, Label: label, Auto: auto})
+	}
+	return res
+}
+
+// box is a bounding box.
+type box struct {
+	minX, minY, maxX, maxY float64
+}
+
+// newBox returns an empty bounding box.
+func newBox() *box {
+	return &box{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+}
+
+// add extends b to include the given rectangle.
+func (b *box) add(x0, y0, x1, y1 float64) {
+	b.minX, b.minY = math.Min(b.minX, x0), math.Min(b.minY, y0)
+	b.maxX, b.maxY = math.Max(b.maxX, x1), math.Max(b.maxY, y1)
+}
+
+// distance returns the distance between a and b.
+func distance(a, b layout.Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// round rounds f to two decimals.
+func round(f float64) float64 {
+	r := math.Round(f*100) / 100
+	if r == 0 {
+		return 0
+	}
+	return r
+}
+
+// num formats f with at most two decimals.
+func num(f float64) string {
+	return strconv.FormatFloat(round(f), 'f', -1, 64)
+}
+
+// esc escapes the characters of s that have a special meaning in XML.
+func esc(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		switch r {
+		case '&':
+			buf.WriteString("&amp;")
+		case '<':
+			buf.WriteString("&lt;")
+		case '>':
+			buf.WriteString("&gt;")
+		case '"':
+			buf.WriteString("&quot;")
+		case '\'':
+			buf.WriteString("&#39;")
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
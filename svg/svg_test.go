@@ -0,0 +1,253 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"goa.design/model/diff"
+	"goa.design/model/mdl"
+	"goa.design/model/query"
+)
+
+func testDesign() *mdl.Design {
+	return &mdl.Design{
+		Version: "1.0",
+		Model: &mdl.Model{
+			Enterprise: &mdl.Enterprise{Name: "Acme"},
+			People: []*mdl.Person{{
+				ID: "user", Name: "User", Description: "A <user>", Tags: "Element,Person", Location: mdl.LocationExternal,
+				Relationships: []*mdl.Relationship{
+					{ID: "r1", SourceID: "user", DestinationID: "web", Description: "Browses", Technology: "HTTPS", Tags: "Relationship"},
+					{ID: "r4", SourceID: "user", DestinationID: "shop", Description: "Buys from", Tags: "Relationship"},
+				},
+			}},
+			Systems: []*mdl.SoftwareSystem{{
+				ID: "shop", Name: "Shop", Description: "Sells things", Tags: "Element,Software System",
+				Containers: []*mdl.Container{
+					{
+						ID: "web", Name: "Web", Technology: "Go", Tags: "Element,Container",
+						Relationships: []*mdl.Relationship{
+							{ID: "r2", SourceID: "web", DestinationID: "db", Description: "Reads", Tags: "Relationship,Asynchronous"},
+						},
+					},
+					{ID: "db", Name: "DB", Technology: "PostgreSQL", Tags: "Element,Container,Database"},
+				},
+			}},
+		},
+		Views: &mdl.Views{
+			ContextViews: []*mdl.ContextView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Context",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "shop"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r4"}},
+				},
+			}},
+			ContainerViews: []*mdl.ContainerView{{
+				SoftwareSystemID: "shop",
+				ViewProps: &mdl.ViewProps{
+					Key:               "Containers",
+					Title:             "Shop containers",
+					ElementViews:      []*mdl.ElementView{{ID: "user"}, {ID: "web"}, {ID: "db"}},
+					RelationshipViews: []*mdl.RelationshipView{{ID: "r1"}, {ID: "r2"}},
+					AutoLayout:        &mdl.AutoLayout{RankDirection: mdl.RankLeftRight},
+				},
+			}},
+			Styles: &mdl.Styles{
+				Elements: []*mdl.ElementStyle{
+					{Tag: "Person", Shape: mdl.ShapePerson, Background: "#08427b", Color: "#ffffff"},
+					{Tag: "Database", Shape: mdl.ShapeCylinder},
+				},
+				Relationships: []*mdl.RelationshipStyle{
+					{Tag: "Relationship", Dashed: &solid},
+					{Tag: "Asynchronous", Dashed: &dashed},
+				},
+			},
+		},
+	}
+}
+
+var dashed, solid = true, false
+
+func TestRender(t *testing.T) {
+	g := query.New(testDesign())
+	doc := render(t, g, "Containers", Options{})
+	for _, want := range []string{
+		`<svg id="graph"`,
+		`<g class="group">`,
+		`>Shop</text>`,
+		`fill="#08427b"`,
+		`A &lt;user&gt;`,
+		`stroke-dasharray="8"`,
+		`marker-end="url(#arrow)"`,
+		`[Database: PostgreSQL]`,
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("missing %q in:\n%s", want, doc)
+		}
+	}
+	if strings.Count(doc, `stroke-dasharray="8"`) != 1 {
+		t.Errorf("got %d dashed edges, want 1", strings.Count(doc, `stroke-dasharray="8"`))
+	}
+	m := parseMetadata(t, doc)
+	if m.Name != "Shop containers" || m.Version != "1.0" {
+		t.Errorf("got name %q and version %q, want %q and %q", m.Name, m.Version, "Shop containers", "1.0")
+	}
+	var ids []string
+	for _, e := range m.Elements {
+		ids = append(ids, e.ID)
+	}
+	if got := strings.Join(ids, ","); got != "user,web,db" {
+		t.Errorf("got elements %s, want user,web,db", got)
+	}
+	user, web := m.Layout["user"].(map[string]any), m.Layout["web"].(map[string]any)
+	if user["x"].(float64) >= web["x"].(float64) {
+		t.Errorf("got user at %v and web at %v, want user left of web", user, web)
+	}
+	if _, ok := m.Layout["e-r1"]; !ok {
+		t.Errorf("missing vertices of r1 in layout %v", m.Layout)
+	}
+}
+
+func TestRenderLink(t *testing.T) {
+	g := query.New(testDesign())
+	doc := render(t, g, "Context", Options{})
+	if !strings.Contains(doc, `<a class="nodeLink" href="Containers.svg"`) {
+		t.Errorf("missing link to container view in:\n%s", doc)
+	}
+	m := parseMetadata(t, doc)
+	if m.Elements[1].ElementViewKey != "Containers" {
+		t.Errorf("got element view key %q, want Containers", m.Elements[1].ElementViewKey)
+	}
+}
+
+func TestRenderSavedLayout(t *testing.T) {
+	g := query.New(testDesign())
+	lay := map[string]any{
+		"user":         map[string]any{"x": 100.0, "y": 200.0},
+		"web":          map[string]any{"x": 500.0, "y": 200.0},
+		"db":           map[string]any{"x": 900.0, "y": 200.0},
+		"e-r1":         []any{map[string]any{"x": 300.0, "y": 100.0}},
+		"e-r2-deleted": true,
+	}
+	doc := render(t, g, "Containers", Options{Layout: lay})
+	if !strings.Contains(doc, `<g class="node" id="web" transform="translate(500,200)">`) {
+		t.Errorf("web is not at its saved position in:\n%s", doc)
+	}
+	m := parseMetadata(t, doc)
+	got, _ := json.Marshal(m.Layout)
+	want := `{"db":{"x":900,"y":200},"e-r1":[{"x":300,"y":100}],"e-r2-deleted":true,"user":{"x":100,"y":200},"web":{"x":500,"y":200}}`
+	if string(got) != want {
+		t.Errorf("got layout %s, want %s", got, want)
+	}
+}
+
+func TestRenderViewPositions(t *testing.T) {
+	design := testDesign()
+	for i, ev := range design.Views.ContainerViews[0].ElementViews {
+		x, y := 400*i, 100
+		ev.X, ev.Y = &x, &y
+	}
+	g := query.New(design)
+	dg := newDiagram(g, g.View("Containers"))
+	if err := dg.place(Options{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, n := range dg.nodes {
+		if x := float64(400*i) + n.width/2; n.x != x || n.y != 100+n.height/2 {
+			t.Errorf("got %s at %v,%v, want %v,%v", n.id, n.x, n.y, x, 100+n.height/2)
+		}
+	}
+}
+
+func TestRenderAutoLayout(t *testing.T) {
+	cases := []mdl.RankDirectionKind{mdl.RankTopBottom, mdl.RankBottomTop, mdl.RankLeftRight, mdl.RankRightLeft}
+	g := query.New(testDesign())
+	for _, dir := range cases {
+		dg := newDiagram(g, g.View("Containers"))
+		if err := dg.place(Options{Direction: dir, Compact: true}); err != nil {
+			t.Fatal(err)
+		}
+		for i, a := range dg.nodes {
+			for _, b := range dg.nodes[i+1:] {
+				if math.Abs(a.x-b.x) < (a.width+b.width)/2 && math.Abs(a.y-b.y) < (a.height+b.height)/2 {
+					t.Errorf("direction %s: %s overlaps %s", dir.Name(), a.id, b.id)
+				}
+			}
+		}
+		gr := dg.groups[0]
+		for _, n := range gr.nodes {
+			if n.x-n.width/2 < gr.x || n.x+n.width/2 > gr.x+gr.width {
+				t.Errorf("direction %s: %s is outside of group %s", dir.Name(), n.id, gr.name)
+			}
+		}
+	}
+}
+
+func TestRenderEmptyView(t *testing.T) {
+	g := query.New(testDesign())
+	var buf bytes.Buffer
+	v := &query.View{Key: "Empty"}
+	if err := Render(&buf, g, v, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if m := parseMetadata(t, buf.String()); len(m.Elements) != 0 || m.Name != "Empty" {
+		t.Errorf("got metadata %+v, want empty view", m)
+	}
+}
+
+func render(t *testing.T, g *query.Graph, key string, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Render(&buf, g, g.View(key), opts); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func parseMetadata(t *testing.T, doc string) *metadata {
+	t.Helper()
+	_, rest, ok := strings.Cut(doc, "<![CDATA[")
+	if !ok {
+		t.Fatalf("missing metadata in:\n%s", doc)
+	}
+	data, _, _ := strings.Cut(rest, "]]>")
+	var m metadata
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		t.Fatalf("invalid metadata: %v", err)
+	}
+	return &m
+}
+
+func TestRenderHighlight(t *testing.T) {
+	base, design := testDesign(), testDesign()
+	design.Model.Systems[0].Containers = append(design.Model.Systems[0].Containers,
+		&mdl.Container{ID: "cache", Name: "Cache", Technology: "Redis", Tags: "Element,Container"})
+	vp := design.Views.ContainerViews[0].ViewProps
+	vp.ElementViews = append(vp.ElementViews, &mdl.ElementView{ID: "cache"})
+	design.Views.Styles.Elements = append(design.Views.Styles.Elements,
+		&mdl.ElementStyle{Tag: "Container", Background: "#438dd5", Color: "#ffffff"})
+
+	diff.Highlight(base, design)
+
+	g := query.New(design)
+	dg := newDiagram(g, g.View("Containers"))
+	var cache *node
+	for _, n := range dg.nodes {
+		if n.id == "cache" {
+			cache = n
+		}
+	}
+	if cache == nil {
+		t.Fatal("added container not rendered")
+	}
+	if cache.style.background != "#d4edda" {
+		t.Errorf("got background %q, want highlight background", cache.style.background)
+	}
+	if got := strings.Join(cache.blocks[1].lines, " "); got != "[Container: Redis]" {
+		t.Errorf("got subtitle %q, want [Container: Redis]", got)
+	}
+}
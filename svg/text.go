@@ -0,0 +1,111 @@
+package svg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// fontFamily is the font family used by the editor to render text.
+const fontFamily = "Inter, -apple-system, BlinkMacSystemFont, sans-serif"
+
+// Advance widths of the printable ASCII characters (space to tilde) in
+// thousandths of the font size. The editor measures text in the browser, the
+// widths of Helvetica approximate the widths of the sans-serif fonts it
+// uses.
+var (
+	regularWidths = [...]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	boldWidths = [...]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// textWidth returns the width of s rendered with the given font size.
+func textWidth(s string, fontSize float64, bold bool) float64 {
+	widths, other := regularWidths[:], 556
+	if bold {
+		widths, other = boldWidths[:], 611
+	}
+	var total int
+	for _, r := range s {
+		if r >= ' ' && int(r-' ') < len(widths) {
+			total += widths[r-' ']
+		} else {
+			total += other
+		}
+	}
+	return float64(total) * fontSize / 1000
+}
+
+// wrap splits text into lines that fit width, breaking words that do not fit
+// on a line of their own. It returns the lines and the width of the widest
+// line. wrap mirrors the text wrapping of the editor.
+func wrap(text string, width, fontSize float64, bold bool) ([]string, float64) {
+	var (
+		lines []string
+		maxW  float64
+	)
+	measure := func(s string) float64 { return textWidth(s, fontSize, bold) }
+	for _, paragraph := range strings.Split(strings.TrimSpace(text), "\n") {
+		var current []string
+		for _, word := range strings.Fields(paragraph) {
+			if measure(word) > width {
+				if len(current) > 0 {
+					lines = append(lines, strings.Join(current, " "))
+					current = nil
+				}
+				parts := breakWord(word, width, measure)
+				for _, part := range parts[:len(parts)-1] {
+					lines = append(lines, part)
+					maxW = max(maxW, measure(part))
+				}
+				current = parts[len(parts)-1:]
+				continue
+			}
+			next := append(current[:len(current):len(current)], word)
+			w := measure(strings.Join(next, " "))
+			if w > width && len(current) > 0 {
+				lines = append(lines, strings.Join(current, " "))
+				current = []string{word}
+				continue
+			}
+			maxW = max(maxW, w)
+			current = next
+		}
+		line := strings.Join(current, " ")
+		lines = append(lines, line)
+		maxW = max(maxW, measure(line))
+	}
+	return lines, maxW
+}
+
+// breakWord splits word into parts that fit width.
+func breakWord(word string, width float64, measure func(string) float64) []string {
+	var (
+		parts   []string
+		current string
+	)
+	for len(word) > 0 {
+		_, size := utf8.DecodeRuneInString(word)
+		next := current + word[:size]
+		if measure(next) > width && current != "" {
+			parts = append(parts, current)
+			current = word[:size]
+		} else {
+			current = next
+		}
+		word = word[size:]
+	}
+	return append(parts, current)
+}